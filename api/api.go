@@ -0,0 +1,374 @@
+// Package api 提供只监听本机回环地址的HTTP/JSON接口，供IDE插件、托盘程序等列出JDK、查看当前版本、切换和备份，
+// 切换通知通过server-sent events推送。请求需要携带保存在config.json旁边的令牌
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"switch/config"
+	"switch/jdk"
+	"sync"
+	"time"
+)
+
+// DefaultAddr 默认的监听地址
+const DefaultAddr = "127.0.0.1:7017"
+
+// SwitchRequest POST /api/v1/switch 的请求
+type SwitchRequest struct {
+	// Version 版本名称、版本号或配置方案名称，与 -set 相同
+	Version string `json:"version"`
+	// Arch 只考虑指定架构的JDK
+	Arch string `json:"arch,omitempty"`
+	// Verify 切换后运行java和javac验证
+	Verify bool `json:"verify,omitempty"`
+	// Rollback 验证失败时切换回原版本
+	Rollback bool `json:"rollback,omitempty"`
+}
+
+// SwitchResult 切换成功后的当前版本
+type SwitchResult struct {
+	Version string `json:"version"`
+	Profile string `json:"profile,omitempty"`
+	Path    string `json:"path"`
+}
+
+// Actions 需要修改系统环境变量的操作，由命令行程序提供，与命令行使用相同的逻辑
+type Actions struct {
+	// Switch 切换到指定版本或配置方案
+	Switch func(req SwitchRequest) (*SwitchResult, error)
+	// Backup 备份环境变量，返回备份标识
+	Backup func() (string, error)
+}
+
+// RequestError 请求本身有误（如版本不存在），返回400而不是500
+type RequestError struct {
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+// JDK GET /api/v1/list 中的一个JDK
+type JDK struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Major   int    `json:"major"`
+	Vendor  string `json:"vendor,omitempty"`
+	Arch    string `json:"arch,omitempty"`
+	// Kind 安装检查的结果：jdk、jre或corrupt
+	Kind    string `json:"kind"`
+	Current bool   `json:"current"`
+}
+
+// Profile GET /api/v1/list 中的一个配置方案
+type Profile struct {
+	Name string `json:"name"`
+	JDK  string `json:"jdk"`
+}
+
+// ListResponse GET /api/v1/list 的响应
+type ListResponse struct {
+	Current        string    `json:"current"`
+	CurrentProfile string    `json:"current_profile,omitempty"`
+	JDKs           []JDK     `json:"jdks"`
+	Profiles       []Profile `json:"profiles"`
+}
+
+// CurrentResponse GET /api/v1/current 的响应
+type CurrentResponse struct {
+	Version     string `json:"version"`
+	Profile     string `json:"profile,omitempty"`
+	Path        string `json:"path"`
+	JavaVersion string `json:"java_version,omitempty"`
+	Major       int    `json:"major"`
+	LinkMode    bool   `json:"link_mode"`
+}
+
+// Server JSON接口的处理程序
+type Server struct {
+	token   string
+	actions Actions
+	events  *broker
+
+	// mu 串行执行切换和备份，并保护current
+	mu sync.Mutex
+	// current 最近一次已知的当前版本和配置方案，用于发现命令行所做的切换
+	current [2]string
+	known   bool
+}
+
+// New 创建使用指定令牌的Server
+func New(token string, actions Actions) *Server {
+	return &Server{token: token, actions: actions, events: newBroker()}
+}
+
+// Handler 返回处理所有接口的http.Handler
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/list", s.method(http.MethodGet, s.handleList))
+	mux.HandleFunc("/api/v1/current", s.method(http.MethodGet, s.handleCurrent))
+	mux.HandleFunc("/api/v1/switch", s.method(http.MethodPost, s.handleSwitch))
+	mux.HandleFunc("/api/v1/backup", s.method(http.MethodPost, s.handleBackup))
+	mux.HandleFunc("/api/v1/events", s.method(http.MethodGet, s.handleEvents))
+	return s.guard(mux)
+}
+
+// ListenAndServe 在addr上启动服务并监视配置变化，addr必须是回环地址
+func ListenAndServe(addr string, s *Server) error {
+	if err := CheckLoopback(addr); err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("监听 %s 失败: %v", addr, err)
+	}
+	go s.Watch(2*time.Second, nil)
+	server := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	return server.Serve(listener)
+}
+
+// CheckLoopback 检查监听地址是否只能从本机访问
+func CheckLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("无效的监听地址 %s: %v", addr, err)
+	}
+	if !isLoopbackHost(host) {
+		return fmt.Errorf("监听地址 %s 不是本机回环地址，只允许 127.0.0.1、::1 或 localhost", addr)
+	}
+	return nil
+}
+
+// isLoopbackHost 判断主机名是否为回环地址
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// guard 拒绝非本机的连接、Host不是回环地址的请求（防止DNS重绑定）和令牌错误的请求
+func (s *Server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err != nil || !isLoopbackHost(host) {
+			writeError(w, http.StatusForbidden, errors.New("只接受本机的连接"))
+			return
+		}
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if !isLoopbackHost(host) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("不接受Host为 %s 的请求", r.Host))
+			return
+		}
+
+		// EventSource不能设置请求头，允许通过 ?token= 传递令牌
+		token := r.URL.Query().Get("token")
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			token = strings.TrimPrefix(auth, "Bearer ")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("令牌错误"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// method 只允许指定的请求方法
+func (s *Server) method(method string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("只支持 %s 请求", method))
+			return
+		}
+		handler(w, r)
+	}
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	resp := ListResponse{Current: cfg.CurrentVersion, CurrentProfile: cfg.CurrentProfile, JDKs: []JDK{}, Profiles: []Profile{}}
+	for _, install := range jdk.Installations(cfg.JDKPaths) {
+		item := JDK{
+			Name:    install.Key,
+			Path:    install.Path,
+			Version: install.Version(),
+			Major:   install.Major(),
+			Vendor:  install.Vendor(),
+			Kind:    kindName(jdk.ValidateInstallation(install.Path).Kind),
+			Current: install.Key == cfg.CurrentVersion,
+		}
+		if info, err := jdk.DetectArch(install.Path); err == nil {
+			item.Arch = info.Arch
+		}
+		resp.JDKs = append(resp.JDKs, item)
+	}
+	for name, profile := range cfg.Profiles {
+		resp.Profiles = append(resp.Profiles, Profile{Name: name, JDK: profile.JDK})
+	}
+	sortProfiles(resp.Profiles)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleCurrent(w http.ResponseWriter, r *http.Request) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if cfg.CurrentVersion == "" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("没有当前版本，请先切换到一个JDK版本"))
+		return
+	}
+	path, err := cfg.GetJDKPath(cfg.CurrentVersion)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	install := jdk.Installation{Key: cfg.CurrentVersion, Path: path}
+	if release, err := jdk.ReadRelease(path); err == nil {
+		install.Release = release
+	}
+	writeJSON(w, http.StatusOK, CurrentResponse{
+		Version:     cfg.CurrentVersion,
+		Profile:     cfg.CurrentProfile,
+		Path:        path,
+		JavaVersion: install.Version(),
+		Major:       install.Major(),
+		LinkMode:    cfg.LinkMode,
+	})
+}
+
+func (s *Server) handleSwitch(w http.ResponseWriter, r *http.Request) {
+	var req SwitchRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("解析请求失败: %v", err))
+		return
+	}
+	if strings.TrimSpace(req.Version) == "" {
+		writeError(w, http.StatusBadRequest, errors.New("缺少version"))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	from := s.current
+	if cfg, err := config.LoadConfig(); err == nil {
+		from = [2]string{cfg.CurrentVersion, cfg.CurrentProfile}
+	}
+	result, err := s.actions.Switch(req)
+	event := Event{Type: EventSwitch, Source: SourceAPI, From: from[0], FromProfile: from[1], To: req.Version, Time: time.Now()}
+	if err != nil {
+		event.Error = err.Error()
+		s.events.publish(event)
+		status := http.StatusInternalServerError
+		var reqErr *RequestError
+		if errors.As(err, &reqErr) {
+			status = http.StatusBadRequest
+		}
+		writeError(w, status, err)
+		return
+	}
+	event.OK, event.To, event.Profile = true, result.Version, result.Profile
+	s.current, s.known = [2]string{result.Version, result.Profile}, true
+	s.events.publish(event)
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleBackup(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, err := s.actions.Backup()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"backup_id": id})
+}
+
+// Watch 定期检查配置中的当前版本，发现命令行或其他程序所做的切换时发送通知，stop关闭时返回
+func (s *Server) Watch(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.checkConfig()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkConfig 读取配置，当前版本与已知的不同时发送通知
+func (s *Server) checkConfig() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return
+	}
+	now := [2]string{cfg.CurrentVersion, cfg.CurrentProfile}
+	if s.known && now != s.current {
+		s.events.publish(Event{
+			Type:        EventSwitch,
+			Source:      SourceExternal,
+			From:        s.current[0],
+			FromProfile: s.current[1],
+			To:          now[0],
+			Profile:     now[1],
+			OK:          true,
+			Time:        time.Now(),
+		})
+	}
+	s.current, s.known = now, true
+}
+
+// kindName 安装目录分类在接口中的名称
+func kindName(kind jdk.InstallKind) string {
+	switch kind {
+	case jdk.KindJDK:
+		return "jdk"
+	case jdk.KindJRE:
+		return "jre"
+	default:
+		return "corrupt"
+	}
+}
+
+// sortProfiles 按名称排序配置方案
+func sortProfiles(profiles []Profile) {
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+}
+
+// writeJSON 输出JSON响应
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
+
+// writeError 输出 {"error": "..."} 格式的错误
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
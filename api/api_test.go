@@ -0,0 +1,172 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"switch/config"
+	"testing"
+	"time"
+)
+
+// setupServer 在临时配置目录中创建配置和使用假切换操作的服务
+func setupServer(t *testing.T) (*Server, *httptest.Server, *[]SwitchRequest) {
+	dir := t.TempDir()
+	t.Setenv(config.HomeEnv, dir)
+	cfg := &config.Config{
+		JDKPaths:       map[string]string{"11": filepath.Join(dir, "jdk11"), "17": filepath.Join(dir, "jdk17")},
+		CurrentVersion: "11",
+		Profiles:       map[string]*config.Profile{"legacy": {JDK: "11"}},
+	}
+	if err := cfg.SaveConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	var requests []SwitchRequest
+	server := New("secret", Actions{
+		Switch: func(req SwitchRequest) (*SwitchResult, error) {
+			requests = append(requests, req)
+			if req.Version == "8" {
+				return nil, &RequestError{Err: errors.New("JDK版本 8 不存在")}
+			}
+			updated, err := config.Update(func(c *config.Config) error {
+				return c.UpdateCurrentVersion(req.Version)
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &SwitchResult{Version: updated.CurrentVersion, Path: updated.JDKPaths[updated.CurrentVersion]}, nil
+		},
+		Backup: func() (string, error) { return "20240101_120000", nil },
+	})
+	ts := httptest.NewServer(server.Handler())
+	t.Cleanup(ts.Close)
+	server.checkConfig()
+	return server, ts, &requests
+}
+
+func request(t *testing.T, ts *httptest.Server, method, path, token, body string) (*http.Response, map[string]interface{}) {
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var result map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&result)
+	return resp, result
+}
+
+func TestAPI(t *testing.T) {
+	_, ts, requests := setupServer(t)
+
+	if resp, _ := request(t, ts, "GET", "/api/v1/list", "", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("没有令牌时应返回401，得到 %d", resp.StatusCode)
+	}
+	if resp, _ := request(t, ts, "GET", "/api/v1/list", "wrong", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("令牌错误时应返回401，得到 %d", resp.StatusCode)
+	}
+
+	resp, list := request(t, ts, "GET", "/api/v1/list", "secret", "")
+	if resp.StatusCode != http.StatusOK || list["current"] != "11" {
+		t.Fatalf("列表错误: %d %v", resp.StatusCode, list)
+	}
+	if jdks := list["jdks"].([]interface{}); len(jdks) != 2 || jdks[0].(map[string]interface{})["kind"] != "corrupt" {
+		t.Errorf("JDK列表错误: %v", jdks)
+	}
+	if profiles := list["profiles"].([]interface{}); len(profiles) != 1 {
+		t.Errorf("配置方案列表错误: %v", profiles)
+	}
+
+	if resp, _ := request(t, ts, "GET", "/api/v1/switch", "secret", ""); resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("切换只接受POST，得到 %d", resp.StatusCode)
+	}
+	if resp, _ := request(t, ts, "POST", "/api/v1/switch", "secret", `{}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("缺少version时应返回400，得到 %d", resp.StatusCode)
+	}
+	if resp, body := request(t, ts, "POST", "/api/v1/switch", "secret", `{"version":"8"}`); resp.StatusCode != http.StatusBadRequest || body["error"] == nil {
+		t.Errorf("版本不存在时应返回400和错误信息，得到 %d %v", resp.StatusCode, body)
+	}
+
+	resp, result := request(t, ts, "POST", "/api/v1/switch", "secret", `{"version":"17","verify":true}`)
+	if resp.StatusCode != http.StatusOK || result["version"] != "17" {
+		t.Errorf("切换失败: %d %v", resp.StatusCode, result)
+	}
+	if last := (*requests)[len(*requests)-1]; !last.Verify {
+		t.Errorf("请求参数没有传给切换操作: %+v", last)
+	}
+	if _, current := request(t, ts, "GET", "/api/v1/current", "secret", ""); current["version"] != "17" {
+		t.Errorf("当前版本错误: %v", current)
+	}
+	if _, backup := request(t, ts, "POST", "/api/v1/backup", "secret", ""); backup["backup_id"] != "20240101_120000" {
+		t.Errorf("备份结果错误: %v", backup)
+	}
+
+	// 非回环地址的Host（DNS重绑定）被拒绝
+	req, _ := http.NewRequest("GET", ts.URL+"/api/v1/list", nil)
+	req.Host = "evil.example.com"
+	req.Header.Set("Authorization", "Bearer secret")
+	if resp, err := http.DefaultClient.Do(req); err != nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("非本机Host应返回403: %v", err)
+	}
+}
+
+// 测试通过接口切换和命令行修改配置时推送的事件
+func TestEvents(t *testing.T) {
+	server, ts, _ := setupServer(t)
+
+	resp, err := http.Get(ts.URL + "/api/v1/events?token=secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Content-Type错误: %s", resp.Header.Get("Content-Type"))
+	}
+	events := make(chan Event, 4)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+				var event Event
+				json.Unmarshal([]byte(data), &event)
+				events <- event
+			}
+		}
+	}()
+	next := func() Event {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("没有收到事件")
+			return Event{}
+		}
+	}
+
+	request(t, ts, "POST", "/api/v1/switch", "secret", `{"version":"17"}`)
+	if event := next(); event.Source != SourceAPI || event.From != "11" || event.To != "17" || !event.OK {
+		t.Errorf("切换事件错误: %+v", event)
+	}
+
+	// 模拟命令行直接修改配置
+	data, _ := os.ReadFile(config.Path())
+	os.WriteFile(config.Path(), []byte(strings.Replace(string(data), `"current_version": "17"`, `"current_version": "11"`, 1)), 0644)
+	stop := make(chan struct{})
+	defer close(stop)
+	go server.Watch(10*time.Millisecond, stop)
+	if event := next(); event.Source != SourceExternal || event.From != "17" || event.To != "11" {
+		t.Errorf("外部切换事件错误: %+v", event)
+	}
+}
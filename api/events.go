@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// EventSwitch 切换事件的类型
+const EventSwitch = "switch"
+
+const (
+	// SourceAPI 通过本接口所做的切换
+	SourceAPI = "api"
+	// SourceExternal 从配置文件中发现的切换，例如命令行或交互模式所做的切换
+	SourceExternal = "external"
+)
+
+// keepAliveInterval 没有事件时发送注释行的间隔，避免连接被代理或客户端超时断开
+const keepAliveInterval = 30 * time.Second
+
+// Event 通过 GET /api/v1/events 推送的通知
+type Event struct {
+	Type        string    `json:"type"`
+	Source      string    `json:"source"`
+	From        string    `json:"from,omitempty"`
+	FromProfile string    `json:"from_profile,omitempty"`
+	To          string    `json:"to,omitempty"`
+	Profile     string    `json:"profile,omitempty"`
+	OK          bool      `json:"ok"`
+	Error       string    `json:"error,omitempty"`
+	Time        time.Time `json:"time"`
+}
+
+// broker 将事件分发给所有连接的客户端
+type broker struct {
+	mu   sync.Mutex
+	subs map[chan Event]bool
+}
+
+func newBroker() *broker {
+	return &broker{subs: make(map[chan Event]bool)}
+}
+
+func (b *broker) subscribe() chan Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ch := make(chan Event, 16)
+	b.subs[ch] = true
+	return ch
+}
+
+func (b *broker) unsubscribe(ch chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subs, ch)
+}
+
+// publish 发送事件，客户端处理不及时时丢弃该客户端的事件，不阻塞切换
+func (b *broker) publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// handleEvents 以server-sent events格式推送事件，直到客户端断开
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("不支持推送事件"))
+		return
+	}
+	ch := s.events.subscribe()
+	defer s.events.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case event := <-ch:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"switch/config"
+	"switch/fsutil"
+)
+
+// TokenFile 令牌文件名，保存在config.json所在目录
+const TokenFile = "api-token"
+
+// TokenPath 返回令牌文件的路径
+func TokenPath() string {
+	return filepath.Join(config.Dir(), TokenFile)
+}
+
+// LoadOrCreateToken 读取令牌，文件不存在或为空时生成新的令牌并只允许当前用户读取
+// 删除令牌文件后重新启动服务即可更换令牌
+func LoadOrCreateToken() (string, error) {
+	path := TokenPath()
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("读取令牌失败: %v", err)
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成令牌失败: %v", err)
+	}
+	token := hex.EncodeToString(buf)
+	if err := os.MkdirAll(config.Dir(), 0755); err != nil {
+		return "", fmt.Errorf("创建配置目录失败: %v", err)
+	}
+	if err := fsutil.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("保存令牌失败: %v", err)
+	}
+	return token, nil
+}
@@ -0,0 +1,182 @@
+package buildtool
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"switch/fsutil"
+	"switch/jdk"
+)
+
+// Gradle工具链相关的属性名称
+const (
+	GradleInstallationsPaths = "org.gradle.java.installations.paths"
+	GradleAutoDetect         = "org.gradle.java.installations.auto-detect"
+	GradleAutoDownload       = "org.gradle.java.installations.auto-download"
+)
+
+// GradlePropertiesPath 返回用户级gradle.properties路径
+// 设置了GRADLE_USER_HOME时使用该目录，否则为 ~/.gradle/gradle.properties
+func GradlePropertiesPath() (string, error) {
+	if home := os.Getenv("GRADLE_USER_HOME"); home != "" {
+		return filepath.Join(home, "gradle.properties"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户目录失败: %v", err)
+	}
+	return filepath.Join(home, ".gradle", "gradle.properties"), nil
+}
+
+// Property 表示一个需要写入properties文件的键值对，Remove为true时从文件中删除该属性
+type Property struct {
+	Key    string
+	Value  string
+	Remove bool
+}
+
+// GradleInstallationProperties 返回写入gradle.properties的工具链属性
+// disableAuto为true时同时关闭Gradle的自动检测和自动下载；
+// 否则restoreAuto为true（这两项是本工具之前写入的）时删除它们以恢复Gradle的默认行为，为false时不修改用户自己的设置
+func GradleInstallationProperties(jdks []jdk.Installation, disableAuto, restoreAuto bool) []Property {
+	paths := make([]string, 0, len(jdks))
+	for _, install := range jdks {
+		paths = append(paths, install.Path)
+	}
+
+	props := []Property{{Key: GradleInstallationsPaths, Value: strings.Join(paths, ",")}}
+	if disableAuto || restoreAuto {
+		props = append(props,
+			Property{Key: GradleAutoDetect, Value: "false", Remove: !disableAuto},
+			Property{Key: GradleAutoDownload, Value: "false", Remove: !disableAuto},
+		)
+	}
+	return props
+}
+
+// SyncGradleProperties 将属性写入gradle.properties，已有文件会先备份
+// 返回备份文件路径，原文件不存在或内容没有变化时为空字符串
+func SyncGradleProperties(path string, props []Property) (string, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("读取gradle.properties失败: %v", err)
+	}
+
+	data := UpdateProperties(existing, props)
+	if bytes.Equal(data, existing) {
+		return "", nil
+	}
+
+	backup, err := fsutil.BackupFile(path)
+	if err != nil {
+		return "", fmt.Errorf("备份gradle.properties失败: %v", err)
+	}
+	if err := fsutil.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("写入gradle.properties失败: %v", err)
+	}
+	return backup, nil
+}
+
+// UpdateProperties 在properties内容中设置属性，保留其他属性、注释和空行
+// 已存在的属性在原位置替换（重复定义只保留第一处），不存在的属性追加到文件末尾
+// 标记为Remove的属性连同续行一起删除
+func UpdateProperties(existing []byte, props []Property) []byte {
+	values := make(map[string]Property, len(props))
+	for _, prop := range props {
+		values[prop.Key] = prop
+	}
+
+	newline := "\n"
+	if bytes.Contains(existing, []byte("\r\n")) {
+		newline = "\r\n"
+	}
+
+	var out []string
+	written := make(map[string]bool)
+	lines := strings.Split(strings.ReplaceAll(string(existing), "\r\n", "\n"), "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		key := propertyKey(line)
+		prop, managed := values[key]
+		if !managed {
+			out = append(out, line)
+			continue
+		}
+
+		// 跳过以反斜杠结尾的续行
+		for isContinued(lines[i]) && i+1 < len(lines) {
+			i++
+		}
+		if !written[key] && !prop.Remove {
+			out = append(out, key+"="+escapePropertyValue(prop.Value))
+		}
+		written[key] = true
+	}
+
+	for _, prop := range props {
+		if !written[prop.Key] && !prop.Remove {
+			out = append(out, prop.Key+"="+escapePropertyValue(prop.Value))
+			written[prop.Key] = true
+		}
+	}
+
+	return []byte(strings.Join(out, newline) + newline)
+}
+
+// propertyKey 返回properties行中的属性名，注释或空行返回空字符串
+func propertyKey(line string) string {
+	trimmed := strings.TrimLeft(line, " \t\f")
+	if trimmed == "" || trimmed[0] == '#' || trimmed[0] == '!' {
+		return ""
+	}
+	end := strings.IndexAny(trimmed, "=: \t\f")
+	if end < 0 {
+		return trimmed
+	}
+	return trimmed[:end]
+}
+
+// isContinued 判断properties行是否以未转义的反斜杠结尾
+func isContinued(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+// escapePropertyValue 按properties格式转义属性值
+// 反斜杠需要转义，非ASCII字符使用\uXXXX（Gradle按ISO-8859-1读取该文件）
+func escapePropertyValue(value string) string {
+	var b strings.Builder
+	for i, r := range value {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case i == 0 && r == ' ':
+			b.WriteString(`\ `)
+		case r > 0x7e:
+			for _, unit := range utf16Units(r) {
+				fmt.Fprintf(&b, `\u%04x`, unit)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// utf16Units 将字符转换为UTF-16编码单元
+func utf16Units(r rune) []rune {
+	if r < 0x10000 {
+		return []rune{r}
+	}
+	r -= 0x10000
+	return []rune{0xd800 + (r>>10)&0x3ff, 0xdc00 + r&0x3ff}
+}
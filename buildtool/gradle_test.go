@@ -0,0 +1,60 @@
+package buildtool
+
+import (
+	"strings"
+	"switch/jdk"
+	"testing"
+)
+
+// 测试更新gradle.properties时保留注释和其他属性
+func TestUpdateProperties(t *testing.T) {
+	existing := "# 用户级Gradle配置\n" +
+		"org.gradle.jvmargs=-Xmx2g\n" +
+		"org.gradle.java.installations.paths=/old/a,\\\n" +
+		"    /old/b\n" +
+		"org.gradle.parallel = true\n"
+
+	jdks := []jdk.Installation{{Key: "8", Path: `C:\Java\jdk8`}, {Key: "17", Path: "/opt/jdk-17"}}
+	out := string(UpdateProperties([]byte(existing), GradleInstallationProperties(jdks, true, false)))
+
+	expected := "# 用户级Gradle配置\n" +
+		"org.gradle.jvmargs=-Xmx2g\n" +
+		"org.gradle.java.installations.paths=C:\\\\Java\\\\jdk8,/opt/jdk-17\n" +
+		"org.gradle.parallel = true\n" +
+		"org.gradle.java.installations.auto-detect=false\n" +
+		"org.gradle.java.installations.auto-download=false\n"
+	if out != expected {
+		t.Errorf("更新结果错误:\n期望:\n%s\n得到:\n%s", expected, out)
+	}
+
+	// 再次更新结果应保持不变
+	again := string(UpdateProperties([]byte(out), GradleInstallationProperties(jdks, true, false)))
+	if again != out {
+		t.Errorf("重复更新结果不一致:\n%s", again)
+	}
+
+	// 不带 -gradle-no-auto 且这两项不是本工具写入的，应保持用户的设置
+	if kept := string(UpdateProperties([]byte(out), GradleInstallationProperties(jdks, false, false))); kept != out {
+		t.Errorf("用户设置的属性不应被修改:\n%s", kept)
+	}
+
+	// 不带 -gradle-no-auto 时应删除本工具之前写入的两个属性
+	restored := string(UpdateProperties([]byte(out), GradleInstallationProperties(jdks, false, true)))
+	expected = "# 用户级Gradle配置\n" +
+		"org.gradle.jvmargs=-Xmx2g\n" +
+		"org.gradle.java.installations.paths=C:\\\\Java\\\\jdk8,/opt/jdk-17\n" +
+		"org.gradle.parallel = true\n"
+	if restored != expected {
+		t.Errorf("删除属性结果错误:\n期望:\n%s\n得到:\n%s", expected, restored)
+	}
+}
+
+// 测试非ASCII路径的转义
+func TestEscapePropertyValue(t *testing.T) {
+	if got := escapePropertyValue(`D:\开发\jdk`); got != `D:\\\u5f00\u53d1\\jdk` {
+		t.Errorf("转义结果错误: %s", got)
+	}
+	if !strings.HasPrefix(escapePropertyValue(" x"), `\ `) {
+		t.Error("开头的空格应被转义")
+	}
+}
@@ -0,0 +1,153 @@
+package buildtool
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"switch/fsutil"
+	"switch/jdk"
+	"switch/xmlutil"
+)
+
+// toolchainIDPrefix 本工具生成的toolchain在provides/id中使用的前缀，用于合并时识别
+const toolchainIDPrefix = "jdk-switch-"
+
+// MavenToolchainsPath 返回默认的Maven toolchains.xml路径 (~/.m2/toolchains.xml)
+func MavenToolchainsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户目录失败: %v", err)
+	}
+	return filepath.Join(home, ".m2", "toolchains.xml"), nil
+}
+
+// SyncMavenToolchains 将JDK列表同步到toolchains.xml，已有文件会先备份
+// 返回备份文件路径，原文件不存在时为空字符串
+func SyncMavenToolchains(path string, jdks []jdk.Installation) (string, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("读取toolchains.xml失败: %v", err)
+	}
+
+	data, err := RenderMavenToolchains(existing, jdks)
+	if err != nil {
+		return "", err
+	}
+	if bytes.Equal(data, existing) {
+		return "", nil
+	}
+
+	backup, err := fsutil.BackupFile(path)
+	if err != nil {
+		return "", fmt.Errorf("备份toolchains.xml失败: %v", err)
+	}
+	if err := fsutil.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("写入toolchains.xml失败: %v", err)
+	}
+	return backup, nil
+}
+
+// RenderMavenToolchains 将JDK列表合并进已有的toolchains.xml内容
+// 之前由本工具生成的条目以及jdkHome与配置相同的条目会被替换，其他内容保持原样
+func RenderMavenToolchains(existing []byte, jdks []jdk.Installation) ([]byte, error) {
+	var blocks strings.Builder
+	for _, install := range jdks {
+		blocks.WriteString(renderToolchain(install))
+	}
+
+	if len(bytes.TrimSpace(existing)) == 0 {
+		var out strings.Builder
+		out.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+		out.WriteString("<toolchains xmlns=\"http://maven.apache.org/TOOLCHAINS/1.1.0\"\n")
+		out.WriteString("            xmlns:xsi=\"http://www.w3.org/2001/XMLSchema-instance\"\n")
+		out.WriteString("            xsi:schemaLocation=\"http://maven.apache.org/TOOLCHAINS/1.1.0 https://maven.apache.org/xsd/toolchains-1.1.0.xsd\">\n")
+		out.WriteString(blocks.String())
+		out.WriteString("</toolchains>\n")
+		return []byte(out.String()), nil
+	}
+
+	homes := make(map[string]bool)
+	for _, install := range jdks {
+		homes[fsutil.NormalizePath(install.Path)] = true
+	}
+
+	isRoot := func(e xml.StartElement) bool { return e.Name.Local == "toolchains" }
+	managed, closing, err := xmlutil.RemoveChildren(existing, isRoot, "toolchain", func(tc mavenToolchain) bool {
+		return tc.managed(homes)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("解析toolchains.xml失败: %v", err)
+	}
+	if closing < 0 {
+		return nil, fmt.Errorf("toolchains.xml格式错误: 缺少 </toolchains>")
+	}
+	// 新条目插入到 </toolchains> 所在行之前
+	return xmlutil.Splice(existing, managed, closing, blocks.String()), nil
+}
+
+// mavenToolchain toolchains.xml中单个toolchain条目，只解析合并时需要的字段
+type mavenToolchain struct {
+	Type     string `xml:"type"`
+	Provides struct {
+		ID string `xml:"id"`
+	} `xml:"provides"`
+	Configuration struct {
+		JDKHome string `xml:"jdkHome"`
+	} `xml:"configuration"`
+}
+
+// managed 判断条目是否应被本工具替换
+func (tc mavenToolchain) managed(homes map[string]bool) bool {
+	if tc.Type != "jdk" {
+		return false
+	}
+	if strings.HasPrefix(tc.Provides.ID, toolchainIDPrefix) {
+		return true
+	}
+	return homes[fsutil.NormalizePath(tc.Configuration.JDKHome)]
+}
+
+// renderToolchain 生成单个JDK的toolchain条目
+func renderToolchain(install jdk.Installation) string {
+	var b strings.Builder
+	b.WriteString("  <toolchain>\n")
+	b.WriteString("    <type>jdk</type>\n")
+	b.WriteString("    <provides>\n")
+	fmt.Fprintf(&b, "      <version>%s</version>\n", xmlutil.Escape(toolchainVersion(install)))
+	if vendor := install.Vendor(); vendor != "" {
+		fmt.Fprintf(&b, "      <vendor>%s</vendor>\n", xmlutil.Escape(vendor))
+	}
+	fmt.Fprintf(&b, "      <id>%s</id>\n", xmlutil.Escape(toolchainID(install)))
+	b.WriteString("    </provides>\n")
+	b.WriteString("    <configuration>\n")
+	fmt.Fprintf(&b, "      <jdkHome>%s</jdkHome>\n", xmlutil.Escape(install.Path))
+	b.WriteString("    </configuration>\n")
+	b.WriteString("  </toolchain>\n")
+	return b.String()
+}
+
+// toolchainVersion 返回provides/version使用的版本：Java 8及以前为1.N，之后为主版本号
+// maven-toolchains-plugin通常按 <version>17</version> 或 <version>1.8</version> 匹配
+func toolchainVersion(install jdk.Installation) string {
+	major := install.Major()
+	switch {
+	case major <= 0:
+		return install.Version()
+	case major <= 8:
+		return fmt.Sprintf("1.%d", major)
+	default:
+		return fmt.Sprintf("%d", major)
+	}
+}
+
+// toolchainID 返回provides/id，版本名称与完整版本号不同时附加完整版本号，便于按确切版本选择
+func toolchainID(install jdk.Installation) string {
+	id := toolchainIDPrefix + install.Key
+	if full := install.Version(); full != install.Key {
+		id += "-" + full
+	}
+	return id
+}
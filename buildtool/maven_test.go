@@ -0,0 +1,119 @@
+package buildtool
+
+import (
+	"strings"
+	"switch/jdk"
+	"testing"
+)
+
+const existingToolchains = `<?xml version="1.0" encoding="UTF-8"?>
+<toolchains>
+  <!-- 手工维护的工具链 -->
+  <!--
+  <toolchain>
+    <type>jdk</type>
+    <provides>
+      <id>jdk-switch-old</id>
+    </provides>
+    <configuration>
+      <jdkHome>/opt/commented-jdk</jdkHome>
+    </configuration>
+  </toolchain>
+  -->
+  <toolchain>
+    <type>netbeans</type>
+    <provides>
+      <version>12</version>
+    </provides>
+    <configuration>
+      <installDir>/opt/netbeans</installDir>
+    </configuration>
+  </toolchain>
+  <toolchain>
+    <type>jdk</type>
+    <provides>
+      <version>11</version>
+    </provides>
+    <configuration>
+      <jdkHome>/opt/old-jdk-11</jdkHome>
+    </configuration>
+  </toolchain>
+  <toolchain xml:space="preserve">
+    <type>jdk</type>
+    <provides>
+      <version>1.8</version>
+      <id>jdk-switch-8</id>
+    </provides>
+    <configuration>
+      <jdkHome>/opt/removed-jdk8</jdkHome>
+    </configuration>
+  </toolchain>
+  <toolchain>
+    <type>jdk</type>
+    <provides>
+      <version>17</version>
+    </provides>
+    <configuration>
+      <jdkHome>/opt/jdk-17</jdkHome>
+    </configuration>
+  </toolchain>
+</toolchains>
+`
+
+// 测试合并toolchains.xml时保留无关条目并替换本工具管理的条目
+func TestRenderMavenToolchainsMerge(t *testing.T) {
+	jdks := []jdk.Installation{
+		{Key: "17", Path: "/opt/jdk-17", Release: &jdk.Release{JavaVersion: "17.0.2", Implementor: "Eclipse Adoptium"}},
+		{Key: "21", Path: "/opt/jdk-21"},
+	}
+
+	data, err := RenderMavenToolchains([]byte(existingToolchains), jdks)
+	if err != nil {
+		t.Fatalf("RenderMavenToolchains 错误: %v", err)
+	}
+	out := string(data)
+
+	for _, keep := range []string{"手工维护的工具链", "/opt/commented-jdk", "/opt/netbeans", "/opt/old-jdk-11"} {
+		if !strings.Contains(out, keep) {
+			t.Errorf("合并后丢失了无关内容 %q:\n%s", keep, out)
+		}
+	}
+	if strings.Contains(out, "/opt/removed-jdk8") {
+		t.Errorf("之前生成的条目应被删除:\n%s", out)
+	}
+	if strings.Count(out, "/opt/jdk-17") != 1 {
+		t.Errorf("jdkHome相同的条目应被替换为一条:\n%s", out)
+	}
+	for _, want := range []string{"<version>17</version>", "<id>jdk-switch-17-17.0.2</id>", "<vendor>Eclipse Adoptium</vendor>", "<version>21</version>", "<id>jdk-switch-21</id>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("缺少 %q:\n%s", want, out)
+		}
+	}
+
+	// 再次合并结果应保持不变
+	again, err := RenderMavenToolchains(data, jdks)
+	if err != nil {
+		t.Fatalf("再次合并错误: %v", err)
+	}
+	if string(again) != out {
+		t.Errorf("重复合并结果不一致:\n%s", again)
+	}
+}
+
+// 测试没有已有文件时生成完整的toolchains.xml
+func TestRenderMavenToolchainsNew(t *testing.T) {
+	data, err := RenderMavenToolchains(nil, []jdk.Installation{{Key: "8", Path: `C:\Java\jdk1.8 & co`}})
+	if err != nil {
+		t.Fatalf("RenderMavenToolchains 错误: %v", err)
+	}
+	out := string(data)
+	if !strings.HasPrefix(out, "<?xml") || !strings.HasSuffix(out, "</toolchains>\n") {
+		t.Errorf("生成的文件不完整:\n%s", out)
+	}
+	if !strings.Contains(out, "<version>1.8</version>") {
+		t.Errorf("Java 8应使用1.8作为版本:\n%s", out)
+	}
+	if !strings.Contains(out, `<jdkHome>C:\Java\jdk1.8 &amp; co</jdkHome>`) {
+		t.Errorf("jdkHome没有正确转义:\n%s", out)
+	}
+}
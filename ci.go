@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"switch/ci"
+	"switch/config"
+	"switch/jdk"
+)
+
+// useInCI 在CI流水线中使用指定的JDK版本（或配置方案），与setup-java相同地设置JAVA_HOME、JAVA_HOME_<主版本>_<架构>和PATH
+// 只写入CI提供的环境文件和dotenv文件，不修改注册表、shell配置文件和本程序的配置
+func useInCI(query, arch, dotenv string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("加载配置失败: %v", err)
+	}
+
+	version, profile := query, ""
+	if p := cfg.Profiles[query]; p != nil && arch == "" {
+		version, profile = p.JDK, query
+	} else {
+		target, err := resolveVersion(cfg, query, arch)
+		if err != nil {
+			return err
+		}
+		version = target
+	}
+
+	jdkPath, err := cfg.GetJDKPath(version)
+	if err != nil {
+		return err
+	}
+	if !jdk.ValidateJDKPath(jdkPath) {
+		return fmt.Errorf("无效的JDK路径: %s", jdkPath)
+	}
+	extraEnv, addPath, err := cfg.SwitchEnv(version, profile)
+	if err != nil {
+		return err
+	}
+
+	install := jdk.Installation{Key: version, Path: jdkPath}
+	if release, err := jdk.ReadRelease(jdkPath); err == nil {
+		install.Release = release
+	}
+	major := install.Major()
+	if major == 0 {
+		return fmt.Errorf("无法确定 %s 的主版本号", version)
+	}
+	jdkArch := jdk.HostArch()
+	if info, err := jdk.DetectArch(jdkPath); err == nil {
+		jdkArch = info.Arch
+	}
+
+	vars := []ci.Var{
+		{Name: "JAVA_HOME", Value: jdkPath},
+		{Name: ci.HomeVar(major, jdkArch), Value: jdkPath},
+	}
+	names := make([]string, 0, len(extraEnv))
+	for name := range extraEnv {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		vars = append(vars, ci.Var{Name: name, Value: extraEnv[name]})
+	}
+	env := &ci.Env{
+		Vars: vars,
+		Path: append([]string{filepath.Join(jdkPath, "bin")}, addPath...),
+		Outputs: []ci.Var{
+			{Name: "path", Value: jdkPath},
+			{Name: "version", Value: install.Version()},
+			{Name: "major", Value: fmt.Sprint(major)},
+			{Name: "arch", Value: jdkArch},
+			{Name: "name", Value: version},
+		},
+	}
+
+	if dotenv == "" && ci.GitLab(os.Getenv) {
+		dotenv = ci.DefaultDotenv
+	}
+	if !ci.GitHubActions(os.Getenv) && dotenv == "" {
+		return fmt.Errorf("未检测到GitHub Actions或GitLab CI，可以使用 -ci-dotenv <文件> 指定dotenv文件")
+	}
+
+	fmt.Printf("使用JDK %s (%s): %s\n", version, install.Version(), jdkPath)
+	if ci.GitHubActions(os.Getenv) {
+		files, err := ci.WriteGitHub(env, os.Getenv)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			fmt.Printf("已写入 %s\n", file)
+		}
+	}
+	if dotenv != "" {
+		// dotenv没有步骤输出，输出写成 JDK_SWITCH_CI_ 开头的变量（JDK_SWITCH_VERSION已被shim使用）；dotenv也不能修改PATH
+		for _, output := range env.Outputs {
+			vars = append(vars, ci.Var{Name: "JDK_SWITCH_CI_" + strings.ToUpper(strings.ReplaceAll(output.Name, "-", "_")), Value: output.Value})
+		}
+		if err := ci.WriteDotenv(dotenv, vars); err != nil {
+			return err
+		}
+		fmt.Printf("已写入 %s，请在作业的 artifacts:reports:dotenv 中声明该文件，并在后续作业中将 $JAVA_HOME/bin 加入PATH\n", dotenv)
+	}
+	return nil
+}
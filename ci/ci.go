@@ -0,0 +1,200 @@
+// Package ci 在CI流水线中使用本机已有的JDK：GitHub Actions通过 $GITHUB_ENV、$GITHUB_PATH 和 $GITHUB_OUTPUT 文件设置后续步骤的环境，
+// GitLab CI通过dotenv报告文件传给后续作业。只写这些文件，不修改注册表和shell配置文件
+package ci
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"switch/fsutil"
+)
+
+// Var 一个环境变量或输出
+type Var struct {
+	Name  string
+	Value string
+}
+
+// Env 要在CI中设置的环境
+type Env struct {
+	// Vars 环境变量，按写入顺序
+	Vars []Var
+	// Path 添加到PATH最前面的目录，排在前面的优先
+	Path []string
+	// Outputs 步骤的输出，例如 path、version
+	Outputs []Var
+}
+
+// DefaultDotenv GitLab CI中未指定dotenv文件时使用的文件名
+const DefaultDotenv = "jdk-switch.env"
+
+// varName 可以写入环境文件的变量名称
+var varName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// outputName 可以写入 $GITHUB_OUTPUT 的输出名称
+var outputName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// HomeVar 返回与setup-java相同的按版本和架构命名的变量名称，例如 JAVA_HOME_17_X64
+func HomeVar(major int, arch string) string {
+	return fmt.Sprintf("JAVA_HOME_%d_%s", major, strings.ToUpper(arch))
+}
+
+// GitHubActions 判断是否运行在GitHub Actions中
+func GitHubActions(getenv func(string) string) bool {
+	return getenv("GITHUB_ENV") != ""
+}
+
+// GitLab 判断是否运行在GitLab CI中
+func GitLab(getenv func(string) string) bool {
+	return getenv("GITLAB_CI") == "true"
+}
+
+// WriteGitHub 将环境变量追加到 $GITHUB_ENV，目录追加到 $GITHUB_PATH，输出追加到 $GITHUB_OUTPUT，返回写入的文件
+// 这些设置从下一个步骤开始生效
+func WriteGitHub(env *Env, getenv func(string) string) ([]string, error) {
+	envFile, pathFile := getenv("GITHUB_ENV"), getenv("GITHUB_PATH")
+	if envFile == "" || pathFile == "" {
+		return nil, fmt.Errorf("未设置 GITHUB_ENV 或 GITHUB_PATH，当前不在GitHub Actions中")
+	}
+
+	var files []string
+	content, err := keyValues(env.Vars, varName)
+	if err != nil {
+		return nil, err
+	}
+	if err := appendFile(envFile, content); err != nil {
+		return nil, err
+	}
+	files = append(files, envFile)
+
+	// runner把后添加的目录放在更前面，所以倒序写入
+	var b strings.Builder
+	for i := len(env.Path) - 1; i >= 0; i-- {
+		if env.Path[i] == "" || strings.ContainsAny(env.Path[i], "\r\n") {
+			return nil, fmt.Errorf("无效的PATH条目: %q", env.Path[i])
+		}
+		b.WriteString(env.Path[i] + "\n")
+	}
+	if err := appendFile(pathFile, b.String()); err != nil {
+		return nil, err
+	}
+	files = append(files, pathFile)
+
+	// 旧版本的runner没有 $GITHUB_OUTPUT
+	if outputFile := getenv("GITHUB_OUTPUT"); outputFile != "" && len(env.Outputs) > 0 {
+		content, err := keyValues(env.Outputs, outputName)
+		if err != nil {
+			return nil, err
+		}
+		if err := appendFile(outputFile, content); err != nil {
+			return nil, err
+		}
+		files = append(files, outputFile)
+	}
+	return files, nil
+}
+
+// keyValues 生成 $GITHUB_ENV 和 $GITHUB_OUTPUT 使用的 NAME=value 格式，多行的值使用 NAME<<分隔符 格式
+func keyValues(vars []Var, valid *regexp.Regexp) (string, error) {
+	var b strings.Builder
+	for _, v := range vars {
+		if !valid.MatchString(v.Name) {
+			return "", fmt.Errorf("名称 %q 无效", v.Name)
+		}
+		if !strings.ContainsAny(v.Value, "\r\n") {
+			fmt.Fprintf(&b, "%s=%s\n", v.Name, v.Value)
+			continue
+		}
+		delimiter, err := newDelimiter()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s<<%s\n%s\n%s\n", v.Name, delimiter, v.Value, delimiter)
+	}
+	return b.String(), nil
+}
+
+// newDelimiter 生成多行值使用的随机分隔符，与actions/toolkit的格式相同
+func newDelimiter() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成分隔符失败: %v", err)
+	}
+	return "ghadelimiter_" + hex.EncodeToString(buf), nil
+}
+
+// appendFile 追加写入文件，文件由runner创建，其他步骤也会写入，所以不能整体替换
+func appendFile(path, content string) error {
+	if content == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("打开 %s 失败: %v", path, err)
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return fmt.Errorf("写入 %s 失败: %v", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("写入 %s 失败: %v", path, err)
+	}
+	return nil
+}
+
+// WriteDotenv 将环境变量写入GitLab的dotenv报告文件，文件中已有的同名变量被替换，其他行保持不变
+// dotenv不能修改后续作业的PATH，也不支持多行的值
+func WriteDotenv(path string, vars []Var) error {
+	var lines []string
+	replaced := make(map[string]bool)
+	index := make(map[string]int)
+	for i, v := range vars {
+		if !varName.MatchString(v.Name) {
+			return fmt.Errorf("名称 %q 无效", v.Name)
+		}
+		if strings.ContainsAny(v.Value, "\r\n") {
+			return fmt.Errorf("dotenv文件不支持多行的值: %s", v.Name)
+		}
+		index[v.Name] = i
+	}
+
+	if f, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := scanner.Text()
+			name, _, _ := strings.Cut(line, "=")
+			if i, ok := index[strings.TrimSpace(name)]; ok {
+				if replaced[vars[i].Name] {
+					continue
+				}
+				line = vars[i].Name + "=" + vars[i].Value
+				replaced[vars[i].Name] = true
+			}
+			if strings.TrimSpace(line) != "" {
+				lines = append(lines, line)
+			}
+		}
+		err := scanner.Err()
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("读取 %s 失败: %v", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("读取 %s 失败: %v", path, err)
+	}
+
+	for _, v := range vars {
+		if !replaced[v.Name] {
+			lines = append(lines, v.Name+"="+v.Value)
+			replaced[v.Name] = true
+		}
+	}
+	if err := fsutil.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("写入 %s 失败: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package ci
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestWriteGitHub(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"GITHUB_ENV":    filepath.Join(dir, "env"),
+		"GITHUB_PATH":   filepath.Join(dir, "path"),
+		"GITHUB_OUTPUT": filepath.Join(dir, "output"),
+	}
+	// runner创建的文件中可能已有其他步骤写入的内容
+	if err := os.WriteFile(files["GITHUB_ENV"], []byte("OTHER=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	env := &Env{
+		Vars: []Var{
+			{"JAVA_HOME", "/opt/jdk-17"},
+			{HomeVar(17, "x64"), "/opt/jdk-17"},
+			{"JAVA_TOOL_OPTIONS", "-Da=1\n-Db=2"},
+		},
+		Path:    []string{"/opt/jdk-17/bin", "/opt/tools"},
+		Outputs: []Var{{"path", "/opt/jdk-17"}, {"java-version", "17.0.2"}},
+	}
+	written, err := WriteGitHub(env, func(name string) string { return files[name] })
+	if err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if len(written) != 3 {
+		t.Errorf("写入的文件数量错误: %v", written)
+	}
+
+	data, _ := os.ReadFile(files["GITHUB_ENV"])
+	pattern := regexp.MustCompile(`^OTHER=1\nJAVA_HOME=/opt/jdk-17\nJAVA_HOME_17_X64=/opt/jdk-17\nJAVA_TOOL_OPTIONS<<(ghadelimiter_[0-9a-f]+)\n-Da=1\n-Db=2\n(ghadelimiter_[0-9a-f]+)\n$`)
+	m := pattern.FindStringSubmatch(string(data))
+	if m == nil || m[1] != m[2] {
+		t.Errorf("GITHUB_ENV内容错误:\n%s", data)
+	}
+	if data, _ := os.ReadFile(files["GITHUB_PATH"]); string(data) != "/opt/tools\n/opt/jdk-17/bin\n" {
+		t.Errorf("GITHUB_PATH内容错误:\n%s", data)
+	}
+	if data, _ := os.ReadFile(files["GITHUB_OUTPUT"]); string(data) != "path=/opt/jdk-17\njava-version=17.0.2\n" {
+		t.Errorf("GITHUB_OUTPUT内容错误:\n%s", data)
+	}
+
+	if _, err := WriteGitHub(env, func(string) string { return "" }); err == nil {
+		t.Error("没有GITHUB_ENV时应返回错误")
+	}
+	env.Vars = []Var{{"BAD=NAME", "x"}}
+	if _, err := WriteGitHub(env, func(name string) string { return files[name] }); err == nil {
+		t.Error("无效的变量名称应返回错误")
+	}
+}
+
+func TestWriteDotenv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultDotenv)
+	if err := os.WriteFile(path, []byte("BUILD_ID=7\nJAVA_HOME=/old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	vars := []Var{{"JAVA_HOME", `C:\Program Files\Java\jdk-17`}, {"JAVA_HOME_17_X64", `C:\Program Files\Java\jdk-17`}}
+	if err := WriteDotenv(path, vars); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	want := "BUILD_ID=7\nJAVA_HOME=C:\\Program Files\\Java\\jdk-17\nJAVA_HOME_17_X64=C:\\Program Files\\Java\\jdk-17\n"
+	if data, _ := os.ReadFile(path); string(data) != want {
+		t.Errorf("dotenv内容错误:\n%s", data)
+	}
+	if err := WriteDotenv(path, []Var{{"A", "x\ny"}}); err == nil {
+		t.Error("多行的值应返回错误")
+	}
+	if data, _ := os.ReadFile(path); !strings.HasPrefix(string(data), "BUILD_ID=7\n") {
+		t.Errorf("出错时不应修改文件:\n%s", data)
+	}
+}
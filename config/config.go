@@ -6,27 +6,114 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"switch/fsutil"
 )
 
 const (
 	DefaultDir  = "C:\\jdk-switch"
 	DefaultFile = "config.json"
+	// HomeEnv 用于覆盖默认配置目录的环境变量
+	HomeEnv = "JDK_SWITCH_HOME"
 )
 
+// 由切换逻辑固定管理的环境变量，不能在附加环境变量中重复定义
+var reservedEnvNames = []string{"JAVA_HOME", "PATH", "CLASSPATH"}
+
+// profileReservedEnvNames 配置方案中不能设置的环境变量，CLASSPATH允许由配置方案覆盖
+var profileReservedEnvNames = []string{"JAVA_HOME", "PATH"}
+
 type Config struct {
-	JDKPaths       map[string]string `json:"jdk_paths"`
-	CurrentVersion string            `json:"current_version"`
+	JDKPaths map[string]string `json:"jdk_paths"`
+	// CurrentVersion 当前版本，用 -remove -force 删除当前版本后为空，直到再次切换
+	CurrentVersion string `json:"current_version"`
+	// JDKEnv 每个JDK版本额外设置的环境变量，值中可使用 ${jdk} 和 ${version} 占位符
+	JDKEnv map[string]map[string]string `json:"jdk_env,omitempty"`
+	// ManagedEnv 上一次切换时由本工具设置的附加环境变量名称，用于下次切换时清理
+	ManagedEnv []string `json:"managed_env,omitempty"`
+	// Hooks 所有切换都会执行的全局钩子
+	Hooks *Hooks `json:"hooks,omitempty"`
+	// JDKHooks 切换到指定JDK版本时额外执行的钩子
+	JDKHooks map[string]*Hooks `json:"jdk_hooks,omitempty"`
+	// SyncMavenToolchains 为true时每次切换后自动同步Maven的toolchains.xml
+	SyncMavenToolchains bool `json:"sync_maven_toolchains,omitempty"`
+	// MavenToolchainsFile toolchains.xml路径，为空时使用 ~/.m2/toolchains.xml
+	MavenToolchainsFile string `json:"maven_toolchains_file,omitempty"`
+	// VerifyAfterSwitch 为true时每次切换后运行 java -version 和 javac -version 验证
+	VerifyAfterSwitch bool `json:"verify_after_switch,omitempty"`
+	// RollbackOnVerifyFailure 为true时验证失败会切换回原来的版本
+	RollbackOnVerifyFailure bool `json:"rollback_on_verify_failure,omitempty"`
+	// Profiles 命名的配置方案，可以通过 -set <方案名称> 切换
+	Profiles map[string]*Profile `json:"profiles,omitempty"`
+	// CurrentProfile 当前使用的配置方案，直接切换JDK版本时为空
+	CurrentProfile string `json:"current_profile,omitempty"`
+	// ManagedPath 上一次切换时由配置方案添加到PATH的条目，用于下次切换时删除
+	ManagedPath []string `json:"managed_path,omitempty"`
+	// LinkMode 为true时JAVA_HOME和PATH固定指向配置目录下的current链接，切换时只修改链接的指向
+	LinkMode bool `json:"link_mode,omitempty"`
+	// VersionHomes 为true时为每个主版本设置一个系统环境变量，例如 JAVA_17_HOME，配置变化后自动同步
+	VersionHomes bool `json:"version_homes,omitempty"`
+	// VersionHomeName 主版本环境变量的名称模板，${major} 为主版本号，为空时使用 JAVA_${major}_HOME
+	VersionHomeName string `json:"version_home_name,omitempty"`
+	// ManagedVersionHomes 上一次同步时设置的主版本环境变量及其值，用于删除不再需要的变量
+	ManagedVersionHomes map[string]string `json:"managed_version_homes,omitempty"`
+	// GradleNoAutoFile 上一次由 -gradle-no-auto 关闭自动检测和自动下载的gradle.properties路径，
+	// 之后不带该参数执行 -gradle 时只删除该文件中由本工具写入的这两项
+	GradleNoAutoFile string `json:"gradle_no_auto_file,omitempty"`
+}
+
+// DefaultVersionHomeName 默认的主版本环境变量名称模板
+const DefaultVersionHomeName = "JAVA_${major}_HOME"
+
+// Profile 配置方案：一个JDK加上附加环境变量和PATH条目
+// 环境变量和PATH条目中可使用 ${jdk} 和 ${version} 占位符
+type Profile struct {
+	// JDK 使用的JDK版本名称
+	JDK string `json:"jdk"`
+	// Env 附加环境变量，与该JDK的 jdk_env 合并，同名时以配置方案为准
+	Env map[string]string `json:"env,omitempty"`
+	// Path 添加到PATH开头（JDK的bin目录之后）的条目
+	Path []string `json:"path,omitempty"`
+}
+
+// Hook 描述一个在切换前后执行的命令
+type Hook struct {
+	// Command 通过系统命令解释器执行的命令行（Windows为cmd /C，其他平台为sh -c）
+	Command string `json:"command"`
+	// Timeout 超时时间（秒），为0时使用默认超时
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// Hooks 切换前(pre)和切换后(post)执行的钩子列表
+type Hooks struct {
+	Pre  []Hook `json:"pre,omitempty"`
+	Post []Hook `json:"post,omitempty"`
+}
+
+// Dir 返回配置目录，设置了环境变量 JDK_SWITCH_HOME 时使用该目录
+func Dir() string {
+	if dir := os.Getenv(HomeEnv); dir != "" {
+		return dir
+	}
+	return DefaultDir
+}
+
+// Path 返回配置文件的完整路径
+func Path() string {
+	return filepath.Join(Dir(), DefaultFile)
 }
 
 // InitDefaultConfig 初始化默认配置
 func InitDefaultConfig() error {
 	// 创建配置目录
-	if err := os.MkdirAll(DefaultDir, 0755); err != nil {
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
 		return fmt.Errorf("创建配置目录失败: %v", err)
 	}
 
 	// 检查配置文件是否存在
-	configPath := filepath.Join(DefaultDir, DefaultFile)
+	configPath := Path()
 	if _, err := os.Stat(configPath); !os.IsNotExist(err) {
 		return fmt.Errorf("配置文件已存在: %s", configPath)
 	}
@@ -46,7 +133,7 @@ func InitDefaultConfig() error {
 }
 
 func LoadConfig() (*Config, error) {
-	configPath := filepath.Join(DefaultDir, DefaultFile)
+	configPath := Path()
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -65,25 +152,115 @@ func LoadConfig() (*Config, error) {
 		return nil, errors.New("配置文件中没有JDK路径信息")
 	}
 
-	if config.CurrentVersion == "" {
-		// 如果没有设置当前版本，使用第一个可用的版本
-		for version := range config.JDKPaths {
-			config.CurrentVersion = version
-			break
+	// 验证附加环境变量
+	for version, env := range config.JDKEnv {
+		if _, exists := config.JDKPaths[version]; !exists {
+			return nil, fmt.Errorf("jdk_env 中的JDK版本 %s 不存在", version)
+		}
+		for name := range env {
+			if isReservedEnvName(name) {
+				return nil, fmt.Errorf("jdk_env 中不能设置 %s (JDK版本 %s)", name, version)
+			}
+		}
+	}
+
+	// 验证钩子
+	for version := range config.JDKHooks {
+		if _, exists := config.JDKPaths[version]; !exists {
+			return nil, fmt.Errorf("jdk_hooks 中的JDK版本 %s 不存在", version)
+		}
+	}
+
+	// 验证配置方案
+	for name, profile := range config.Profiles {
+		if profile == nil {
+			return nil, fmt.Errorf("配置方案 %s 为空", name)
+		}
+		if _, exists := config.JDKPaths[name]; exists {
+			return nil, fmt.Errorf("配置方案 %s 与JDK版本名称重复", name)
+		}
+		if _, exists := config.JDKPaths[profile.JDK]; !exists {
+			return nil, fmt.Errorf("配置方案 %s 使用的JDK版本 %s 不存在", name, profile.JDK)
+		}
+		for envName := range profile.Env {
+			if isProfileReservedEnvName(envName) {
+				return nil, fmt.Errorf("配置方案 %s 中不能设置 %s", name, envName)
+			}
+		}
+	}
+
+	// 验证主版本环境变量名称模板，开启时附加环境变量不能与主版本环境变量重名
+	if err := ValidateVersionHomeName(config.VersionHomeName); err != nil {
+		return nil, err
+	}
+	if config.VersionHomes {
+		for version, env := range config.JDKEnv {
+			for name := range env {
+				if config.IsVersionHomeVar(name) {
+					return nil, fmt.Errorf("jdk_env 中的 %s 与主版本环境变量重名 (JDK版本 %s)", name, version)
+				}
+			}
+		}
+		for profileName, profile := range config.Profiles {
+			for name := range profile.Env {
+				if config.IsVersionHomeVar(name) {
+					return nil, fmt.Errorf("配置方案 %s 中的 %s 与主版本环境变量重名", profileName, name)
+				}
+			}
 		}
 	}
 
 	return &config, nil
 }
 
+// SaveConfig 在配置文件锁的保护下保存配置，原配置文件会先备份为 config.json.bak
 func (c *Config) SaveConfig() error {
+	unlock, err := Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return c.save()
+}
+
+// Update 在配置文件锁的保护下重新读取配置、执行修改并保存
+// 用于修改配置的命令，避免覆盖其他进程同时写入的内容
+func Update(modify func(c *Config) error) (*Config, error) {
+	unlock, err := Lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := modify(cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.save(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// save 备份并写入配置文件，调用方需持有配置文件锁
+func (c *Config) save() error {
 	data, err := json.MarshalIndent(c, "", "    ")
 	if err != nil {
 		return fmt.Errorf("序列化配置失败: %v", err)
 	}
 
-	configPath := filepath.Join(DefaultDir, DefaultFile)
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	configPath := Path()
+	if old, err := os.ReadFile(configPath); err == nil {
+		if err := os.WriteFile(configPath+".bak", old, 0644); err != nil {
+			return fmt.Errorf("备份配置文件失败: %v", err)
+		}
+	}
+
+	if err := fsutil.WriteFile(configPath, data, 0644); err != nil {
 		return fmt.Errorf("保存配置文件失败: %v", err)
 	}
 
@@ -105,3 +282,341 @@ func (c *Config) UpdateCurrentVersion(version string) error {
 	c.CurrentVersion = version
 	return nil
 }
+
+// NameTaken 判断名称是否已被JDK条目或配置方案使用，生成默认名称时用于避免冲突
+func (c *Config) NameTaken(name string) bool {
+	_, isJDK := c.JDKPaths[name]
+	_, isProfile := c.Profiles[name]
+	return isJDK || isProfile
+}
+
+// AddJDK 添加JDK条目，版本名称已存在或路径已被其他条目使用时返回错误
+func (c *Config) AddJDK(version, path string) error {
+	if strings.TrimSpace(version) == "" {
+		return errors.New("JDK版本名称不能为空")
+	}
+	if _, exists := c.JDKPaths[version]; exists {
+		return fmt.Errorf("JDK版本 %s 已存在", version)
+	}
+	if _, exists := c.Profiles[version]; exists {
+		return fmt.Errorf("名称 %s 已被配置方案使用", version)
+	}
+	if existing := c.FindVersionByPath(path); existing != "" {
+		return fmt.Errorf("路径 %s 已配置为JDK版本 %s", path, existing)
+	}
+	if c.JDKPaths == nil {
+		c.JDKPaths = make(map[string]string)
+	}
+	c.JDKPaths[version] = path
+	return nil
+}
+
+// RemoveJDK 删除JDK条目及其附加环境变量和钩子
+// 删除当前版本需要force为true，删除后当前版本会被清空；不允许删除最后一个条目
+func (c *Config) RemoveJDK(version string, force bool) error {
+	if _, exists := c.JDKPaths[version]; !exists {
+		return fmt.Errorf("JDK版本 %s 不存在", version)
+	}
+	if len(c.JDKPaths) == 1 {
+		return fmt.Errorf("JDK版本 %s 是最后一个条目，不能删除", version)
+	}
+	for name, profile := range c.Profiles {
+		if profile != nil && profile.JDK == version {
+			return fmt.Errorf("JDK版本 %s 被配置方案 %s 使用，请先修改该配置方案", version, name)
+		}
+	}
+	if version == c.CurrentVersion {
+		if !force {
+			return fmt.Errorf("JDK版本 %s 是当前版本，如需删除请使用 -force", version)
+		}
+		c.CurrentVersion = ""
+	}
+
+	delete(c.JDKPaths, version)
+	delete(c.JDKEnv, version)
+	delete(c.JDKHooks, version)
+	return nil
+}
+
+// RenameJDK 修改JDK版本名称，附加环境变量、钩子和当前版本一并更新
+func (c *Config) RenameJDK(oldVersion, newVersion string) error {
+	path, exists := c.JDKPaths[oldVersion]
+	if !exists {
+		return fmt.Errorf("JDK版本 %s 不存在", oldVersion)
+	}
+	if strings.TrimSpace(newVersion) == "" {
+		return errors.New("JDK版本名称不能为空")
+	}
+	if _, exists := c.JDKPaths[newVersion]; exists {
+		return fmt.Errorf("JDK版本 %s 已存在", newVersion)
+	}
+	if _, exists := c.Profiles[newVersion]; exists {
+		return fmt.Errorf("名称 %s 已被配置方案使用", newVersion)
+	}
+
+	delete(c.JDKPaths, oldVersion)
+	c.JDKPaths[newVersion] = path
+	if env, exists := c.JDKEnv[oldVersion]; exists {
+		delete(c.JDKEnv, oldVersion)
+		c.JDKEnv[newVersion] = env
+	}
+	if hooks, exists := c.JDKHooks[oldVersion]; exists {
+		delete(c.JDKHooks, oldVersion)
+		c.JDKHooks[newVersion] = hooks
+	}
+	for _, profile := range c.Profiles {
+		if profile != nil && profile.JDK == oldVersion {
+			profile.JDK = newVersion
+		}
+	}
+	if c.CurrentVersion == oldVersion {
+		c.CurrentVersion = newVersion
+	}
+	return nil
+}
+
+// SetJDKPath 修改已有JDK条目的路径
+func (c *Config) SetJDKPath(version, path string) error {
+	if _, exists := c.JDKPaths[version]; !exists {
+		return fmt.Errorf("JDK版本 %s 不存在", version)
+	}
+	if existing := c.FindVersionByPath(path); existing != "" && existing != version {
+		return fmt.Errorf("路径 %s 已配置为JDK版本 %s", path, existing)
+	}
+	c.JDKPaths[version] = path
+	return nil
+}
+
+// FindVersionByPath 返回使用指定路径的JDK版本名称，不存在时返回空字符串
+// 比较时忽略末尾的路径分隔符，只在Windows上忽略大小写
+func (c *Config) FindVersionByPath(path string) string {
+	target := fsutil.NormalizePath(path)
+	for version, p := range c.JDKPaths {
+		if fsutil.NormalizePath(p) == target {
+			return version
+		}
+	}
+	return ""
+}
+
+// GetJDKEnv 返回指定版本的附加环境变量，占位符 ${jdk} 和 ${version} 会被展开
+func (c *Config) GetJDKEnv(version string) (map[string]string, error) {
+	path, err := c.GetJDKPath(version)
+	if err != nil {
+		return nil, err
+	}
+
+	replacer := strings.NewReplacer("${jdk}", path, "${version}", version)
+	env := make(map[string]string, len(c.JDKEnv[version]))
+	for name, value := range c.JDKEnv[version] {
+		env[name] = replacer.Replace(value)
+	}
+	return env, nil
+}
+
+// SwitchEnv 返回切换到指定版本（或配置方案）时需要设置的附加环境变量和PATH条目
+// profile为空时只使用该版本的 jdk_env；否则合并配置方案的环境变量，占位符均已展开
+func (c *Config) SwitchEnv(version, profile string) (map[string]string, []string, error) {
+	env, err := c.GetJDKEnv(version)
+	if err != nil || profile == "" {
+		return env, nil, err
+	}
+
+	p := c.Profiles[profile]
+	if p == nil {
+		return nil, nil, fmt.Errorf("配置方案 %s 不存在", profile)
+	}
+	replacer := strings.NewReplacer("${jdk}", c.JDKPaths[version], "${version}", version)
+	for name, value := range p.Env {
+		env[name] = replacer.Replace(value)
+	}
+	var paths []string
+	for _, entry := range p.Path {
+		paths = append(paths, replacer.Replace(entry))
+	}
+	return env, paths, nil
+}
+
+// UnsetEnvNames 返回切换到指定版本时需要清除的附加环境变量名称
+// 包括其他JDK定义或上次切换设置过、但目标版本没有定义的变量，结果按名称排序
+func (c *Config) UnsetEnvNames(version string) []string {
+	return c.UnsetEnvNamesFor(c.JDKEnv[version])
+}
+
+// UnsetEnvNamesFor 返回目标附加环境变量为target时需要清除的附加环境变量名称
+// 候选包括所有JDK和配置方案定义过的变量以及上次切换设置过的变量，结果按名称排序
+func (c *Config) UnsetEnvNamesFor(target map[string]string) []string {
+	seen := make(map[string]bool)
+	var names []string
+
+	add := func(name string) {
+		if _, defined := target[name]; defined || seen[name] || isReservedEnvName(name) {
+			return
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	for _, env := range c.JDKEnv {
+		for name := range env {
+			add(name)
+		}
+	}
+	for _, profile := range c.Profiles {
+		if profile != nil {
+			for name := range profile.Env {
+				add(name)
+			}
+		}
+	}
+	for _, name := range c.ManagedEnv {
+		add(name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// ValidateVersionHomeName 检查主版本环境变量名称模板，空字符串表示使用默认模板
+func ValidateVersionHomeName(template string) error {
+	if template == "" {
+		return nil
+	}
+	if !strings.Contains(template, "${major}") {
+		return fmt.Errorf("version_home_name 必须包含 ${major}: %s", template)
+	}
+	name := strings.ReplaceAll(template, "${major}", "17")
+	for i, r := range name {
+		if !(r == '_' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || i > 0 && r >= '0' && r <= '9') {
+			return fmt.Errorf("version_home_name 不是有效的环境变量名称: %s", template)
+		}
+	}
+	if isReservedEnvName(name) {
+		return fmt.Errorf("version_home_name 不能使用 %s", template)
+	}
+	return nil
+}
+
+// VersionHomeVar 返回主版本对应的环境变量名称，例如 JAVA_17_HOME
+func (c *Config) VersionHomeVar(major int) string {
+	template := c.VersionHomeName
+	if template == "" {
+		template = DefaultVersionHomeName
+	}
+	return strings.ReplaceAll(template, "${major}", strconv.Itoa(major))
+}
+
+// IsVersionHomeVar 判断环境变量名称是否符合主版本环境变量的名称模板（不区分大小写）
+func (c *Config) IsVersionHomeVar(name string) bool {
+	template := c.VersionHomeName
+	if template == "" {
+		template = DefaultVersionHomeName
+	}
+	prefix, suffix, _ := strings.Cut(template, "${major}")
+	if len(name) <= len(prefix)+len(suffix) ||
+		!strings.EqualFold(name[:len(prefix)], prefix) || !strings.EqualFold(name[len(name)-len(suffix):], suffix) {
+		return false
+	}
+	_, err := strconv.Atoi(name[len(prefix) : len(name)-len(suffix)])
+	return err == nil
+}
+
+// VersionHomeNames 返回上一次同步时设置的主版本环境变量名称，按名称排序，用于备份
+func (c *Config) VersionHomeNames() []string {
+	names := make([]string, 0, len(c.ManagedVersionHomes))
+	for name := range c.ManagedVersionHomes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExtraEnvNames 返回配置中所有附加环境变量名称（含配置方案和上次切换设置过的），用于备份
+func (c *Config) ExtraEnvNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, env := range c.JDKEnv {
+		for name := range env {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	for _, profile := range c.Profiles {
+		if profile == nil {
+			continue
+		}
+		for name := range profile.Env {
+			// CLASSPATH总会被备份，这里不再重复
+			if !seen[name] && !isReservedEnvName(name) {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	for _, name := range c.ManagedEnv {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PreHooks 返回切换到指定版本前需要执行的钩子，全局钩子在前
+func (c *Config) PreHooks(version string) []Hook {
+	var hooks []Hook
+	if c.Hooks != nil {
+		hooks = append(hooks, c.Hooks.Pre...)
+	}
+	if h := c.JDKHooks[version]; h != nil {
+		hooks = append(hooks, h.Pre...)
+	}
+	return hooks
+}
+
+// PostHooks 返回切换到指定版本后需要执行的钩子，全局钩子在前
+func (c *Config) PostHooks(version string) []Hook {
+	var hooks []Hook
+	if c.Hooks != nil {
+		hooks = append(hooks, c.Hooks.Post...)
+	}
+	if h := c.JDKHooks[version]; h != nil {
+		hooks = append(hooks, h.Post...)
+	}
+	return hooks
+}
+
+// ManagedEnvNames 返回切换后需要记录到 managed_env 的附加环境变量名称（不含固定管理的变量），按名称排序
+func ManagedEnvNames(env map[string]string) []string {
+	var names []string
+	for name := range env {
+		if !isReservedEnvName(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isProfileReservedEnvName 判断环境变量是否不能在配置方案中设置（不区分大小写）
+func isProfileReservedEnvName(name string) bool {
+	for _, reserved := range profileReservedEnvNames {
+		if strings.EqualFold(name, reserved) {
+			return true
+		}
+	}
+	return false
+}
+
+// isReservedEnvName 判断环境变量是否由切换逻辑固定管理（不区分大小写）
+func isReservedEnvName(name string) bool {
+	for _, reserved := range reservedEnvNames {
+		if strings.EqualFold(name, reserved) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 // 测试前的准备工作和测试后的清理工作
@@ -184,3 +187,354 @@ func TestUpdateCurrentVersion(t *testing.T) {
 		t.Error("更新到无效版本应该返回错误")
 	}
 }
+
+// 测试附加环境变量的占位符展开和清理列表
+func TestJDKEnv(t *testing.T) {
+	_, testConfig, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	testConfig.JDKEnv = map[string]map[string]string{
+		"8":  {"JRE_HOME": "${jdk}\\jre", "MAVEN_OPTS": "-Xmx1g"},
+		"17": {"JDK_HOME": "${jdk}", "JDK_VERSION_NAME": "jdk-${version}"},
+	}
+	testConfig.ManagedEnv = []string{"OLD_VAR"}
+
+	env, err := testConfig.GetJDKEnv("17")
+	if err != nil {
+		t.Fatalf("GetJDKEnv(17) 错误: %v", err)
+	}
+	if env["JDK_HOME"] != "C:\\Test\\JDK17" {
+		t.Errorf("期望 JDK_HOME 为 C:\\Test\\JDK17, 得到 %s", env["JDK_HOME"])
+	}
+	if env["JDK_VERSION_NAME"] != "jdk-17" {
+		t.Errorf("期望 JDK_VERSION_NAME 为 jdk-17, 得到 %s", env["JDK_VERSION_NAME"])
+	}
+
+	// 没有定义附加环境变量的版本应返回空集合
+	env, err = testConfig.GetJDKEnv("11")
+	if err != nil {
+		t.Fatalf("GetJDKEnv(11) 错误: %v", err)
+	}
+	if len(env) != 0 {
+		t.Errorf("JDK 11 不应有附加环境变量, 得到 %v", env)
+	}
+
+	// 切换到17时应清除8定义的变量和上次设置的变量
+	unset := testConfig.UnsetEnvNames("17")
+	expected := []string{"JRE_HOME", "MAVEN_OPTS", "OLD_VAR"}
+	if len(unset) != len(expected) {
+		t.Fatalf("期望清除 %v, 得到 %v", expected, unset)
+	}
+	for i := range expected {
+		if unset[i] != expected[i] {
+			t.Errorf("期望清除 %v, 得到 %v", expected, unset)
+			break
+		}
+	}
+
+	if _, err := testConfig.GetJDKEnv("999"); err == nil {
+		t.Error("获取无效版本的附加环境变量应该返回错误")
+	}
+}
+
+// 测试配置方案的环境变量、PATH条目以及与JDK条目的关联
+func TestProfiles(t *testing.T) {
+	_, testConfig, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	testConfig.JDKEnv = map[string]map[string]string{
+		"8":  {"JRE_HOME": "${jdk}\\jre", "MAVEN_OPTS": "-Xmx1g"},
+		"17": {"JDK_HOME": "${jdk}"},
+	}
+	testConfig.Profiles = map[string]*Profile{
+		"legacy": {
+			JDK:  "8",
+			Env:  map[string]string{"MAVEN_OPTS": "-Xmx2g", "CLASSPATH": ".;${jdk}\\lib\\tools.jar"},
+			Path: []string{"C:\\tools\\maven-3.6\\bin"},
+		},
+		"modern": {
+			JDK: "17",
+			Env: map[string]string{"JAVA_TOOL_OPTIONS": "-Djavax.net.ssl.trustStore=C:\\certs\\ca.jks"},
+		},
+	}
+
+	env, paths, err := testConfig.SwitchEnv("8", "legacy")
+	if err != nil {
+		t.Fatalf("SwitchEnv 错误: %v", err)
+	}
+	if env["MAVEN_OPTS"] != "-Xmx2g" || env["JRE_HOME"] != "C:\\Test\\JDK8\\jre" {
+		t.Errorf("配置方案应覆盖并合并jdk_env: %v", env)
+	}
+	if env["CLASSPATH"] != ".;C:\\Test\\JDK8\\lib\\tools.jar" {
+		t.Errorf("CLASSPATH占位符未展开: %s", env["CLASSPATH"])
+	}
+	if len(paths) != 1 || paths[0] != "C:\\tools\\maven-3.6\\bin" {
+		t.Errorf("PATH条目不正确: %v", paths)
+	}
+
+	// CLASSPATH由切换逻辑固定管理，不记录到managed_env，也不会被清除
+	testConfig.ManagedEnv = ManagedEnvNames(env)
+	if strings.Join(testConfig.ManagedEnv, ",") != "JRE_HOME,MAVEN_OPTS" {
+		t.Errorf("managed_env 不正确: %v", testConfig.ManagedEnv)
+	}
+	env, _, _ = testConfig.SwitchEnv("17", "modern")
+	if unset := strings.Join(testConfig.UnsetEnvNamesFor(env), ","); unset != "JRE_HOME,MAVEN_OPTS" {
+		t.Errorf("切换到modern时应清除legacy的变量, 得到 %s", unset)
+	}
+
+	if _, _, err := testConfig.SwitchEnv("8", "missing"); err == nil {
+		t.Error("不存在的配置方案应该返回错误")
+	}
+
+	// 被配置方案使用的JDK不能删除，重命名时配置方案一并更新
+	if err := testConfig.RemoveJDK("17", true); err == nil {
+		t.Error("删除被配置方案使用的JDK应该返回错误")
+	}
+	if err := testConfig.RenameJDK("17", "17-temurin"); err != nil {
+		t.Fatalf("RenameJDK 错误: %v", err)
+	}
+	if testConfig.Profiles["modern"].JDK != "17-temurin" {
+		t.Errorf("重命名后配置方案应指向新名称, 得到 %s", testConfig.Profiles["modern"].JDK)
+	}
+	if err := testConfig.AddJDK("legacy", "C:\\Test\\Other"); err == nil {
+		t.Error("添加与配置方案同名的JDK应该返回错误")
+	}
+}
+
+// 测试主版本环境变量的名称模板
+func TestVersionHomeName(t *testing.T) {
+	_, testConfig, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	if name := testConfig.VersionHomeVar(17); name != "JAVA_17_HOME" {
+		t.Errorf("默认名称错误: %s", name)
+	}
+	if !testConfig.IsVersionHomeVar("java_8_home") || testConfig.IsVersionHomeVar("JAVA_HOME") || testConfig.IsVersionHomeVar("JAVA_X_HOME") {
+		t.Error("默认模板的名称判断错误")
+	}
+
+	testConfig.VersionHomeName = "JDK${major}"
+	if name := testConfig.VersionHomeVar(8); name != "JDK8" {
+		t.Errorf("自定义名称错误: %s", name)
+	}
+	if !testConfig.IsVersionHomeVar("JDK21") || testConfig.IsVersionHomeVar("JDK_HOME") {
+		t.Error("自定义模板的名称判断错误")
+	}
+
+	testConfig.ManagedVersionHomes = map[string]string{"JDK8": "C:\\Test\\JDK8", "JDK17": "C:\\Test\\JDK17"}
+	if names := strings.Join(testConfig.VersionHomeNames(), ","); names != "JDK17,JDK8" {
+		t.Errorf("备份的变量名称错误: %s", names)
+	}
+
+	for _, template := range []string{"JAVA_HOME", "JAVA-${major}", "${major}_HOME"} {
+		if err := ValidateVersionHomeName(template); err == nil {
+			t.Errorf("模板 %s 应返回错误", template)
+		}
+	}
+	if err := ValidateVersionHomeName("JAVA_HOME_${major}"); err != nil {
+		t.Errorf("有效的模板返回错误: %v", err)
+	}
+}
+
+// 测试添加、删除、重命名JDK条目和修改路径
+func TestManageJDKEntries(t *testing.T) {
+	_, testConfig, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	testConfig.JDKEnv = map[string]map[string]string{"17": {"JDK_HOME": "${jdk}"}}
+	testConfig.Profiles = map[string]*Profile{"web": {JDK: "21"}}
+
+	if !testConfig.NameTaken("17") || !testConfig.NameTaken("web") || testConfig.NameTaken("25") {
+		t.Error("NameTaken 应同时检查JDK条目和配置方案")
+	}
+
+	if err := testConfig.AddJDK("21", "C:\\Test\\JDK21"); err != nil {
+		t.Fatalf("AddJDK 错误: %v", err)
+	}
+	if err := testConfig.AddJDK("21", "C:\\Test\\Other"); err == nil {
+		t.Error("添加已存在的版本应该返回错误")
+	}
+	if err := testConfig.AddJDK("jdk17", "C:\\Test\\JDK17\\"); err == nil {
+		t.Error("添加已配置的路径应该返回错误")
+	}
+	// 只有Windows上路径不区分大小写
+	if found := testConfig.FindVersionByPath("c:\\test\\jdk17"); (found == "17") != (runtime.GOOS == "windows") {
+		t.Errorf("按大小写不同的路径查找结果错误: %q", found)
+	}
+
+	if err := testConfig.RenameJDK("17", "temurin-17"); err != nil {
+		t.Fatalf("RenameJDK 错误: %v", err)
+	}
+	if _, exists := testConfig.JDKEnv["temurin-17"]; !exists {
+		t.Error("重命名后附加环境变量应随之移动")
+	}
+	if err := testConfig.RenameJDK("8", "11"); err == nil {
+		t.Error("重命名为已存在的版本应该返回错误")
+	}
+	if err := testConfig.RenameJDK("8", "jdk8"); err != nil || testConfig.CurrentVersion != "jdk8" {
+		t.Errorf("重命名当前版本后当前版本应更新: %v, %s", err, testConfig.CurrentVersion)
+	}
+
+	if err := testConfig.SetJDKPath("11", "C:\\Test\\JDK21"); err == nil {
+		t.Error("修改为其他条目使用的路径应该返回错误")
+	}
+	if err := testConfig.SetJDKPath("11", "D:\\JDK11"); err != nil || testConfig.JDKPaths["11"] != "D:\\JDK11" {
+		t.Errorf("SetJDKPath 错误: %v", err)
+	}
+
+	if err := testConfig.RemoveJDK("jdk8", false); err == nil {
+		t.Error("不使用force删除当前版本应该返回错误")
+	}
+	if err := testConfig.RemoveJDK("jdk8", true); err != nil || testConfig.CurrentVersion != "" {
+		t.Errorf("强制删除当前版本错误: %v, %s", err, testConfig.CurrentVersion)
+	}
+	if err := testConfig.RemoveJDK("temurin-17", false); err != nil {
+		t.Fatalf("RemoveJDK 错误: %v", err)
+	}
+	if _, exists := testConfig.JDKEnv["temurin-17"]; exists {
+		t.Error("删除后附加环境变量应一并删除")
+	}
+	if err := testConfig.RemoveJDK("11", false); err != nil {
+		t.Fatalf("RemoveJDK 错误: %v", err)
+	}
+	if err := testConfig.RemoveJDK("21", true); err == nil {
+		t.Error("删除最后一个条目应该返回错误")
+	}
+}
+
+// 测试在配置文件锁保护下更新配置
+func TestUpdateWithLock(t *testing.T) {
+	tempDir, testConfig, cleanup := setupTestConfig(t)
+	defer cleanup()
+	t.Setenv(HomeEnv, tempDir)
+
+	if err := testConfig.SaveConfig(); err != nil {
+		t.Fatalf("SaveConfig 错误: %v", err)
+	}
+
+	updated, err := Update(func(c *Config) error {
+		return c.AddJDK("21", "C:\\Test\\JDK21")
+	})
+	if err != nil {
+		t.Fatalf("Update 错误: %v", err)
+	}
+	if updated.JDKPaths["21"] != "C:\\Test\\JDK21" {
+		t.Errorf("Update 结果错误: %v", updated.JDKPaths)
+	}
+
+	// 保存前的配置应被备份
+	backup, err := os.ReadFile(filepath.Join(tempDir, DefaultFile+".bak"))
+	if err != nil {
+		t.Fatalf("读取配置备份错误: %v", err)
+	}
+	if strings.Contains(string(backup), "JDK21") {
+		t.Error("备份应为修改前的配置")
+	}
+
+	// 持有锁时其他写入需要等待，锁释放后锁文件应被删除
+	unlock, err := Lock()
+	if err != nil {
+		t.Fatalf("Lock 错误: %v", err)
+	}
+	done := make(chan error)
+	go func() {
+		_, err := Update(func(c *Config) error { return c.UpdateCurrentVersion("21") })
+		done <- err
+	}()
+	select {
+	case <-done:
+		t.Fatal("持有锁时Update不应完成")
+	case <-time.After(200 * time.Millisecond):
+	}
+	unlock()
+	if err := <-done; err != nil {
+		t.Fatalf("释放锁后Update错误: %v", err)
+	}
+	if _, err := os.Stat(lockPath()); !os.IsNotExist(err) {
+		t.Error("锁文件应已删除")
+	}
+
+	loaded, err := LoadConfig()
+	if err != nil || loaded.CurrentVersion != "21" {
+		t.Errorf("期望当前版本 21: %v", err)
+	}
+
+	// 强制删除当前版本后重新读取配置，当前版本应保持为空而不是任选一个
+	if _, err := Update(func(c *Config) error { return c.RemoveJDK("21", true) }); err != nil {
+		t.Fatalf("删除当前版本错误: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if loaded, err := LoadConfig(); err != nil || loaded.CurrentVersion != "" {
+			t.Fatalf("删除后当前版本应为空: %v, %q", err, loaded.CurrentVersion)
+		}
+	}
+}
+
+// 测试切换锁与配置文件锁互不影响，持有切换锁时仍可以保存配置
+func TestLockSwitch(t *testing.T) {
+	tempDir, testConfig, cleanup := setupTestConfig(t)
+	defer cleanup()
+	t.Setenv(HomeEnv, tempDir)
+	if err := testConfig.SaveConfig(); err != nil {
+		t.Fatalf("SaveConfig 错误: %v", err)
+	}
+
+	unlock, err := LockSwitch()
+	if err != nil {
+		t.Fatalf("LockSwitch 错误: %v", err)
+	}
+	if _, err := Update(func(c *Config) error { return nil }); err != nil {
+		t.Fatalf("持有切换锁时Update错误: %v", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		unlock, err := LockSwitch()
+		if err == nil {
+			unlock()
+		}
+		done <- err
+	}()
+	select {
+	case <-done:
+		t.Fatal("持有切换锁时其他切换不应获得锁")
+	case <-time.After(200 * time.Millisecond):
+	}
+	unlock()
+	if err := <-done; err != nil {
+		t.Fatalf("释放切换锁后获取错误: %v", err)
+	}
+}
+
+// 测试持有锁期间刷新锁文件的修改时间，不会被其他进程当作残留清除
+func TestLockRefresh(t *testing.T) {
+	t.Setenv(HomeEnv, t.TempDir())
+	interval := lockRefreshInterval
+	lockRefreshInterval = 20 * time.Millisecond
+	defer func() { lockRefreshInterval = interval }()
+
+	unlock, err := LockSwitch()
+	if err != nil {
+		t.Fatalf("LockSwitch 错误: %v", err)
+	}
+	defer unlock()
+
+	// 模拟一次耗时很长的切换
+	old := time.Now().Add(-staleLockAge - time.Minute)
+	if err := os.Chtimes(switchLockPath(), old, old); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	info, err := os.Stat(switchLockPath())
+	if err != nil {
+		t.Fatalf("锁文件应存在: %v", err)
+	}
+	if time.Since(info.ModTime()) > staleLockAge {
+		t.Error("持有锁期间应刷新锁文件的修改时间")
+	}
+
+	unlock()
+	if _, err := os.Stat(switchLockPath()); !os.IsNotExist(err) {
+		t.Errorf("释放后锁文件应被删除: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// lockTimeout 等待配置文件锁的最长时间
+	lockTimeout = 10 * time.Second
+	// switchLockTimeout 等待切换锁的最长时间，切换需要修改注册表并广播，比保存配置慢
+	switchLockTimeout = time.Minute
+	// lockRetryInterval 获取锁失败后的重试间隔
+	lockRetryInterval = 50 * time.Millisecond
+	// staleLockAge 超过该时间未刷新的锁文件视为进程异常退出后的残留，会被清除
+	staleLockAge = 2 * time.Minute
+)
+
+// lockRefreshInterval 持有锁期间刷新锁文件修改时间的间隔，远小于staleLockAge，
+// 切换耗时再长，持有中的锁也不会被其他进程当作残留清除
+var lockRefreshInterval = 30 * time.Second
+
+// lockPath 返回配置文件锁的路径
+func lockPath() string {
+	return Path() + ".lock"
+}
+
+// switchLockPath 返回切换锁的路径
+func switchLockPath() string {
+	return filepath.Join(Dir(), "switch.lock")
+}
+
+// Lock 获取配置文件锁，防止多个进程同时修改config.json
+// 返回释放锁的函数，调用方应在写入完成后调用
+func Lock() (func(), error) {
+	return acquire(lockPath(), "配置文件锁", "修改配置", lockTimeout)
+}
+
+// LockSwitch 获取切换锁，防止多个进程（如命令行和 -serve）同时修改系统环境变量
+// 与配置文件锁使用不同的文件，持有切换锁时仍可以通过Update保存配置
+func LockSwitch() (func(), error) {
+	return acquire(switchLockPath(), "切换锁", "切换JDK", switchLockTimeout)
+}
+
+// acquire 通过独占创建锁文件获取锁，锁文件已存在时重试直到超时
+func acquire(path, name, action string, timeout time.Duration) (func(), error) {
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
+		return nil, fmt.Errorf("创建配置目录失败: %v", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			file.WriteString(strconv.Itoa(os.Getpid()))
+			file.Close()
+			return holdLock(path), nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("创建%s失败: %v", name, err)
+		}
+
+		// 清除残留的锁文件
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(path)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("等待%s超时，可能有其他jdk-switch进程正在%s (%s)", name, action, path)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// holdLock 在后台定期刷新锁文件的修改时间，返回停止刷新并删除锁文件的函数
+func holdLock(path string) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(lockRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				now := time.Now()
+				os.Chtimes(path, now, now)
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-done
+			os.Remove(path)
+		})
+	}
+}
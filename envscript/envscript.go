@@ -0,0 +1,135 @@
+// Package envscript 生成设置JAVA_HOME、PATH和附加环境变量的脚本（bat、ps1、sh、fish），
+// 加载脚本后的PATH与切换时的计算方式相同：删除Java相关条目，把JDK的bin目录和配置方案的条目放在最前面
+package envscript
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"switch/fsutil"
+)
+
+// Path 脚本中使用的路径，Relative为true时Value相对于脚本所在目录
+type Path struct {
+	Value    string
+	Relative bool
+}
+
+// Env 脚本要设置的环境
+type Env struct {
+	// Version 版本名称，写在脚本开头的注释中
+	Version  string
+	JavaHome Path
+	// Vars 附加环境变量
+	Vars map[string]string
+	// Prepend 放在PATH最前面的条目：JDK的bin目录和配置方案的条目
+	Prepend []Path
+	// Remove 需要从PATH中删除的条目（上一个配置方案添加的条目、current链接的bin目录）
+	Remove []string
+	// ShimsDir PATH中有shim目录时保留在最前面
+	ShimsDir string
+}
+
+// Formats 支持的脚本格式，按生成顺序
+var Formats = []string{"bat", "ps1", "sh", "fish"}
+
+// renderers 各格式的生成函数
+var renderers = map[string]func(*Env) (string, error){
+	"bat":  renderBat,
+	"ps1":  renderPowerShell,
+	"sh":   renderShell,
+	"fish": renderFish,
+}
+
+// varName 脚本中可以使用的环境变量名称
+var varName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// FileName 返回格式对应的脚本文件名，如 env.ps1
+func FileName(format string) string {
+	return "env." + format
+}
+
+// Render 生成指定格式的脚本内容
+func Render(format string, env *Env) (string, error) {
+	render, ok := renderers[format]
+	if !ok {
+		return "", fmt.Errorf("不支持的脚本格式: %s（支持 %s）", format, strings.Join(Formats, ", "))
+	}
+	for name, value := range env.Vars {
+		if !varName.MatchString(name) {
+			return "", fmt.Errorf("环境变量名称 %q 不能用于脚本", name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return "", fmt.Errorf("环境变量 %s 的值包含换行", name)
+		}
+	}
+	return render(env)
+}
+
+// Write 在dir中生成各格式的脚本，返回生成的文件路径
+func Write(dir string, formats []string, env *Env) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %v", err)
+	}
+	var files []string
+	for _, format := range formats {
+		content, err := Render(format, env)
+		if err != nil {
+			return nil, err
+		}
+		file := filepath.Join(dir, FileName(format))
+		if err := fsutil.WriteFile(file, []byte(content), 0644); err != nil {
+			return nil, fmt.Errorf("写入 %s 失败: %v", file, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// MakeRelative 将path转换为相对于dir的路径，无法转换（如位于不同盘符）时保持原样
+func MakeRelative(path, dir string) Path {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return Path{Value: path}
+	}
+	rel, err := filepath.Rel(absDir, path)
+	if err != nil || !filepath.IsAbs(path) {
+		return Path{Value: path}
+	}
+	return Path{Value: rel, Relative: true}
+}
+
+// sortedVars 按名称排序的附加环境变量名称
+func (e *Env) sortedVars() []string {
+	names := make([]string, 0, len(e.Vars))
+	for name := range e.Vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// hasRelative 判断脚本中是否有相对路径，有时需要先确定脚本所在目录
+func (e *Env) hasRelative() bool {
+	if e.JavaHome.Relative {
+		return true
+	}
+	for _, p := range e.Prepend {
+		if p.Relative {
+			return true
+		}
+	}
+	return false
+}
+
+// toSlash Unix shell中使用的路径：反斜杠换成斜杠，Windows的盘符路径 C:\x 转换为 /c/x（Git Bash、MSYS2）
+func toSlash(path string) string {
+	path = strings.ReplaceAll(path, `\`, "/")
+	if len(path) >= 2 && path[1] == ':' {
+		path = "/" + strings.ToLower(path[:1]) + path[2:]
+	}
+	return path
+}
@@ -0,0 +1,100 @@
+package envscript
+
+import (
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func testEnv() *Env {
+	return &Env{
+		Version:  "17",
+		JavaHome: Path{Value: "/opt/jdk-17"},
+		Vars:     map[string]string{"MAVEN_OPTS": "-Dname='x y' 100%"},
+		Prepend:  []Path{{Value: "/opt/jdk-17/bin"}, {Value: "/opt/tools"}},
+		Remove:   []string{"/old/tool"},
+		ShimsDir: "/home/u/.jdk-switch/shims",
+	}
+}
+
+func TestRender(t *testing.T) {
+	env := testEnv()
+	tests := map[string][]string{
+		"bat":  {`set "JAVA_HOME=/opt/jdk-17"`, `set "MAVEN_OPTS=-Dname='x y' 100%%"`, `if /i "!_JS_E!"=="/old/tool"`, "endlocal & set \"PATH=%_JS_NEW%\"\r\n"},
+		"ps1":  {`$env:JAVA_HOME = '/opt/jdk-17'`, `$env:MAVEN_OPTS = '-Dname=''x y'' 100%'`, `$jsPrepend = @('/opt/jdk-17/bin', '/opt/tools')`},
+		"sh":   {`export JAVA_HOME='/opt/jdk-17'`, `export MAVEN_OPTS='-Dname='\''x y'\'' 100%'`, `export PATH="$_js_front"'/opt/jdk-17/bin':'/opt/tools'"$_js_keep"`},
+		"fish": {`set -gx JAVA_HOME '/opt/jdk-17'`, `set -gx MAVEN_OPTS '-Dname=\'x y\' 100%'`, `set -l _js_remove '/old/tool'`},
+	}
+	for format, wants := range tests {
+		out, err := Render(format, env)
+		if err != nil {
+			t.Fatalf("%s: 生成失败: %v", format, err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s: 缺少 %q\n%s", format, want, out)
+			}
+		}
+	}
+
+	if _, err := Render("zsh", env); err == nil {
+		t.Error("不支持的格式应返回错误")
+	}
+	env.Vars["BAD-NAME"] = "x"
+	if _, err := Render("sh", env); err == nil {
+		t.Error("无效的变量名称应返回错误")
+	}
+	delete(env.Vars, "BAD-NAME")
+	env.Vars["QUOTE"] = `a"b`
+	if _, err := Render("bat", env); err == nil {
+		t.Error("bat脚本中的值包含双引号时应返回错误")
+	}
+}
+
+func TestRelative(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("使用Unix路径测试")
+	}
+	p := MakeRelative("/work/repo/.jdk/17", "/work/repo/scripts")
+	if !p.Relative || p.Value != filepath.FromSlash("../.jdk/17") {
+		t.Errorf("相对路径错误: %+v", p)
+	}
+	if p := MakeRelative("relative/path", "/work"); p.Relative {
+		t.Errorf("相对路径不应再次转换: %+v", p)
+	}
+
+	if got := toSlash(`C:\Program Files\Java\jdk-17\bin`); got != "/c/Program Files/Java/jdk-17/bin" {
+		t.Errorf("盘符路径转换错误: %s", got)
+	}
+}
+
+// 在sh中加载脚本，检查PATH的计算结果与切换时的规则一致
+func TestShellScript(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil || runtime.GOOS == "windows" {
+		t.Skip("没有可用的sh")
+	}
+	out, err := Render("sh", testEnv())
+	if err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+	path := "/home/u/.jdk-switch/shims:/usr/lib/java/bin:/opt/jdk-11/bin:/old/tool:/opt/tools::/usr/bin:/tmp/*"
+	script := out + `printf '%s\n%s\n%s' "$PATH" "$JAVA_HOME" "$MAVEN_OPTS"`
+	cmd := exec.Command(sh, "-c", script)
+	cmd.Env = []string{"PATH=" + path}
+	result, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("运行脚本失败: %v\n%s", err, result)
+	}
+	lines := strings.Split(string(result), "\n")
+	want := []string{
+		"/home/u/.jdk-switch/shims:/opt/jdk-17/bin:/opt/tools:/usr/bin:/tmp/*",
+		"/opt/jdk-17",
+		"-Dname='x y' 100%",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("加载结果错误:\n%s", result)
+	}
+}
@@ -0,0 +1,270 @@
+package envscript
+
+import (
+	"fmt"
+	"strings"
+)
+
+// header 脚本开头的说明，usage为加载脚本的方式
+func header(comment string, env *Env, usage string) string {
+	return fmt.Sprintf("%s 由 jdk-switch -export 生成: JDK %s\n%s 加载方式: %s\n", comment, env.Version, comment, usage)
+}
+
+// batQuote 转义 set "NAME=value" 中的值，引号内只需处理百分号
+func batQuote(value string) (string, error) {
+	if strings.Contains(value, `"`) {
+		return "", fmt.Errorf("bat脚本中的值不能包含双引号: %s", value)
+	}
+	return strings.ReplaceAll(value, "%", "%%"), nil
+}
+
+// batPath 路径在bat中的写法，相对路径以脚本所在目录 %~dp0 开头
+func batPath(p Path) (string, error) {
+	value, err := batQuote(p.Value)
+	if err != nil {
+		return "", err
+	}
+	if p.Relative {
+		return "%~dp0" + value, nil
+	}
+	return value, nil
+}
+
+func renderBat(env *Env) (string, error) {
+	var b strings.Builder
+	b.WriteString("@echo off\r\n")
+	b.WriteString(strings.ReplaceAll(header("rem", env, "call env.bat"), "\n", "\r\n"))
+
+	javaHome, err := batPath(env.JavaHome)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&b, "set \"JAVA_HOME=%s\"\r\n", javaHome)
+	for _, name := range env.sortedVars() {
+		value, err := batQuote(env.Vars[name])
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "set \"%s=%s\"\r\n", name, value)
+	}
+
+	var prepend []string
+	for _, p := range env.Prepend {
+		entry, err := batPath(p)
+		if err != nil {
+			return "", err
+		}
+		prepend = append(prepend, entry)
+	}
+
+	// 逐个检查PATH条目；cmd的字符串替换不区分大小写，替换前后不同即表示包含该子串
+	b.WriteString("setlocal EnableDelayedExpansion\r\n")
+	b.WriteString("set \"_JS_KEEP=\"\r\n")
+	b.WriteString("set \"_JS_SHIMS=\"\r\n")
+	b.WriteString("for %%p in (\"%PATH:;=\";\"%\") do (\r\n")
+	b.WriteString("    set \"_JS_E=%%~p\"\r\n")
+	b.WriteString("    set \"_JS_DROP=\"\r\n")
+	b.WriteString("    if not defined _JS_E set \"_JS_DROP=1\"\r\n")
+	b.WriteString("    if defined _JS_E (\r\n")
+	b.WriteString("        set \"_JS_T=!_JS_E:/=\\!\"\r\n")
+	b.WriteString("        if not \"!_JS_T:\\java\\=!\"==\"!_JS_T!\" set \"_JS_DROP=1\"\r\n")
+	b.WriteString("        if not \"!_JS_T:\\jdk=!\"==\"!_JS_T!\" set \"_JS_DROP=1\"\r\n")
+	var literal []string
+	for _, entry := range env.Remove {
+		quoted, err := batQuote(entry)
+		if err != nil {
+			return "", err
+		}
+		literal = append(literal, quoted)
+	}
+	literal = append(literal, prepend...)
+	for _, entry := range literal {
+		fmt.Fprintf(&b, "        if /i \"!_JS_E!\"==\"%s\" set \"_JS_DROP=1\"\r\n", entry)
+	}
+	if env.ShimsDir != "" {
+		shims, err := batQuote(env.ShimsDir)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "        if /i \"!_JS_E!\"==\"%s\" (set \"_JS_SHIMS=%s;\" & set \"_JS_DROP=1\")\r\n", shims, shims)
+	}
+	b.WriteString("    )\r\n")
+	b.WriteString("    if not defined _JS_DROP set \"_JS_KEEP=!_JS_KEEP!;!_JS_E!\"\r\n")
+	b.WriteString(")\r\n")
+	fmt.Fprintf(&b, "set \"_JS_NEW=!_JS_SHIMS!%s!_JS_KEEP!\"\r\n", strings.Join(prepend, ";"))
+	b.WriteString("endlocal & set \"PATH=%_JS_NEW%\"\r\n")
+	return b.String(), nil
+}
+
+// psQuote 单引号字符串，单引号写两次
+func psQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// psPath 路径在PowerShell中的写法，相对路径基于 $PSScriptRoot
+func psPath(p Path) string {
+	if p.Relative {
+		return "(Join-Path $PSScriptRoot " + psQuote(p.Value) + ")"
+	}
+	return psQuote(p.Value)
+}
+
+// psArray PowerShell数组字面量
+func psArray(values []string) string {
+	return "@(" + strings.Join(values, ", ") + ")"
+}
+
+func renderPowerShell(env *Env) (string, error) {
+	var b strings.Builder
+	b.WriteString(header("#", env, ". .\\env.ps1"))
+	fmt.Fprintf(&b, "$env:JAVA_HOME = %s\n", psPath(env.JavaHome))
+	for _, name := range env.sortedVars() {
+		fmt.Fprintf(&b, "$env:%s = %s\n", name, psQuote(env.Vars[name]))
+	}
+
+	var prepend, remove []string
+	for _, p := range env.Prepend {
+		prepend = append(prepend, psPath(p))
+	}
+	for _, entry := range env.Remove {
+		remove = append(remove, psQuote(entry))
+	}
+	fmt.Fprintf(&b, "$jsPrepend = %s\n", psArray(prepend))
+	fmt.Fprintf(&b, "$jsRemove = %s\n", psArray(remove))
+	fmt.Fprintf(&b, "$jsShims = %s\n", psQuote(env.ShimsDir))
+	b.WriteString(`$jsSep = [IO.Path]::PathSeparator
+$jsKeep = @()
+$jsFront = @()
+foreach ($jsEntry in ($env:PATH -split [regex]::Escape($jsSep))) {
+    $jsEntry = $jsEntry.Trim()
+    $jsNorm = $jsEntry.Replace('/', '\')
+    if ($jsEntry -eq '') { continue }
+    if ($jsShims -ne '' -and $jsEntry -eq $jsShims) { $jsFront = @($jsShims); continue }
+    if ($jsNorm -like '*\java\*' -or $jsNorm -like '*\jdk*') { continue }
+    if ($jsRemove -contains $jsEntry -or $jsPrepend -contains $jsEntry) { continue }
+    $jsKeep += $jsEntry
+}
+$env:PATH = ($jsFront + $jsPrepend + $jsKeep) -join $jsSep
+Remove-Variable jsPrepend, jsRemove, jsShims, jsSep, jsKeep, jsFront, jsEntry, jsNorm -ErrorAction SilentlyContinue
+`)
+	return b.String(), nil
+}
+
+// shQuote 单引号字符串，单引号写作 '\”
+func shQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// shPath 路径在sh中的写法，相对路径基于脚本所在目录 $_js_dir
+func shPath(p Path, pathEntry bool) string {
+	if p.Relative {
+		return `"$_js_dir"/` + shQuote(toSlash(p.Value))
+	}
+	if pathEntry {
+		return shQuote(toSlash(p.Value))
+	}
+	return shQuote(p.Value)
+}
+
+// shJavaPattern 与Java相关的PATH条目，规则与切换时相同，不区分大小写
+const shJavaPattern = `*/[Jj][Aa][Vv][Aa]/*|*/[Jj][Dd][Kk]*|*\\[Jj][Aa][Vv][Aa]\\*|*\\[Jj][Dd][Kk]*`
+
+func renderShell(env *Env) (string, error) {
+	var b strings.Builder
+	b.WriteString(header("#", env, ". ./env.sh"))
+	if env.hasRelative() {
+		b.WriteString("_js_dir=$(CDPATH= cd -- \"$(dirname -- \"${BASH_SOURCE:-$0}\")\" && pwd)\n")
+	}
+	fmt.Fprintf(&b, "export JAVA_HOME=%s\n", shPath(env.JavaHome, false))
+	for _, name := range env.sortedVars() {
+		fmt.Fprintf(&b, "export %s=%s\n", name, shQuote(env.Vars[name]))
+	}
+
+	var prepend, literal []string
+	for _, p := range env.Prepend {
+		prepend = append(prepend, shPath(p, true))
+	}
+	for _, entry := range env.Remove {
+		literal = append(literal, shQuote(toSlash(entry)))
+	}
+	literal = append(literal, prepend...)
+
+	b.WriteString("_js_keep=\n_js_front=\n")
+	// 按 : 拆分PATH时关闭通配符展开，之后恢复调用者的设置
+	b.WriteString("case $- in *f*) _js_noglob=1 ;; *) _js_noglob= ;; esac\n")
+	b.WriteString("set -f\n")
+	b.WriteString("if [ -n \"${IFS+x}\" ]; then _js_ifs=$IFS; else unset _js_ifs; fi\nIFS=:\n")
+	b.WriteString("for _js_entry in $PATH; do\n    case $_js_entry in\n        '') ;;\n")
+	if env.ShimsDir != "" {
+		fmt.Fprintf(&b, "        %s) _js_front=$_js_entry: ;;\n", shQuote(toSlash(env.ShimsDir)))
+	}
+	fmt.Fprintf(&b, "        %s) ;;\n", shJavaPattern)
+	fmt.Fprintf(&b, "        %s) ;;\n", strings.Join(literal, "|"))
+	b.WriteString("        *) _js_keep=$_js_keep:$_js_entry ;;\n    esac\ndone\n")
+	b.WriteString("if [ -n \"${_js_ifs+x}\" ]; then IFS=$_js_ifs; else unset IFS; fi\n")
+	b.WriteString("[ -n \"$_js_noglob\" ] || set +f\n")
+	fmt.Fprintf(&b, "export PATH=\"$_js_front\"%s\"$_js_keep\"\n", strings.Join(prepend, `:`))
+	b.WriteString("unset _js_keep _js_front _js_noglob _js_ifs _js_entry")
+	if env.hasRelative() {
+		b.WriteString(" _js_dir")
+	}
+	b.WriteString("\n")
+	return b.String(), nil
+}
+
+// fishQuote 单引号字符串，反斜杠和单引号需要转义
+func fishQuote(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// fishPath 路径在fish中的写法，相对路径基于脚本所在目录 $_js_dir
+func fishPath(p Path, pathEntry bool) string {
+	if p.Relative {
+		return `$_js_dir/` + fishQuote(toSlash(p.Value))
+	}
+	if pathEntry {
+		return fishQuote(toSlash(p.Value))
+	}
+	return fishQuote(p.Value)
+}
+
+func renderFish(env *Env) (string, error) {
+	var b strings.Builder
+	b.WriteString(header("#", env, "source env.fish"))
+	if env.hasRelative() {
+		b.WriteString("set -l _js_dir (builtin realpath (dirname (status --current-filename)))\n")
+	}
+	fmt.Fprintf(&b, "set -gx JAVA_HOME %s\n", fishPath(env.JavaHome, false))
+	for _, name := range env.sortedVars() {
+		fmt.Fprintf(&b, "set -gx %s %s\n", name, fishQuote(env.Vars[name]))
+	}
+
+	var prepend, remove []string
+	for _, p := range env.Prepend {
+		prepend = append(prepend, fishPath(p, true))
+	}
+	for _, entry := range env.Remove {
+		remove = append(remove, fishQuote(toSlash(entry)))
+	}
+	fmt.Fprintf(&b, "set -l _js_prepend %s\n", strings.Join(prepend, " "))
+	fmt.Fprintf(&b, "set -l _js_remove %s\n", strings.Join(remove, " "))
+	fmt.Fprintf(&b, "set -l _js_shims %s\n", fishQuote(toSlash(env.ShimsDir)))
+	b.WriteString(`set -l _js_keep
+set -l _js_front
+for _js_entry in $PATH
+    if test -z "$_js_entry"
+        continue
+    else if test -n "$_js_shims"; and test "$_js_entry" = "$_js_shims"
+        set _js_front $_js_shims
+    else if string match -qir '[/\\\\](java[/\\\\]|jdk)' -- $_js_entry
+        continue
+    else if contains -- $_js_entry $_js_remove $_js_prepend
+        continue
+    else
+        set -a _js_keep $_js_entry
+    end
+end
+set -gx PATH $_js_front $_js_prepend $_js_keep
+`)
+	return b.String(), nil
+}
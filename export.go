@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"switch/config"
+	"switch/envscript"
+	"switch/jdk"
+)
+
+// exportScripts 为指定版本（或配置方案）生成设置环境变量的脚本
+// formats为逗号分隔的格式列表，为空时生成所有格式；relative为true时路径相对于脚本所在目录
+func exportScripts(cfg *config.Config, query, dir, formats string, relative bool) error {
+	version, profile := query, ""
+	if p := cfg.Profiles[query]; p != nil {
+		version, profile = p.JDK, query
+	} else {
+		target, err := resolveVersion(cfg, query, "")
+		if err != nil {
+			return err
+		}
+		version = target
+	}
+
+	jdkPath, err := cfg.GetJDKPath(version)
+	if err != nil {
+		return err
+	}
+	if !jdk.ValidateJDKPath(jdkPath) {
+		return fmt.Errorf("无效的JDK路径: %s", jdkPath)
+	}
+	extraEnv, addPath, err := cfg.SwitchEnv(version, profile)
+	if err != nil {
+		return err
+	}
+
+	list := envscript.Formats
+	if formats != "" {
+		list = nil
+		for _, format := range strings.Split(formats, ",") {
+			if format = strings.TrimPrefix(strings.TrimSpace(format), "."); format != "" {
+				list = append(list, format)
+			}
+		}
+	}
+	if dir == "" {
+		dir = "."
+	}
+
+	// 与切换时相同：删除上一个配置方案添加的条目和current链接的bin目录
+	path := func(p string) envscript.Path {
+		if relative {
+			return envscript.MakeRelative(p, dir)
+		}
+		return envscript.Path{Value: p}
+	}
+	env := &envscript.Env{
+		Version:  query,
+		JavaHome: path(jdkPath),
+		Vars:     extraEnv,
+		Prepend:  []envscript.Path{path(filepath.Join(jdkPath, "bin"))},
+		Remove:   append(append([]string{}, cfg.ManagedPath...), filepath.Join(jdk.CurrentLinkPath(), "bin")),
+		ShimsDir: jdk.ShimsDir(),
+	}
+	for _, entry := range addPath {
+		env.Prepend = append(env.Prepend, path(entry))
+	}
+
+	files, err := envscript.Write(dir, list, env)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		fmt.Printf("已生成 %s\n", file)
+	}
+	if relative && !env.JavaHome.Relative {
+		fmt.Printf("警告: %s 无法转换为相对路径，脚本中使用绝对路径\n", jdkPath)
+	}
+	return nil
+}
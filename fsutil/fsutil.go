@@ -0,0 +1,81 @@
+package fsutil
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// BackupFile 将已存在的文件复制为 原文件名.年月日_时分秒.bak，返回备份文件路径
+// 文件不存在时不做任何操作，返回空字符串
+func BackupFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("读取文件失败: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("读取文件信息失败: %v", err)
+	}
+
+	backupPath := fmt.Sprintf("%s.%s.bak", path, time.Now().Format("20060102_150405"))
+	if err := os.WriteFile(backupPath, data, info.Mode().Perm()); err != nil {
+		return "", fmt.Errorf("创建备份文件失败: %v", err)
+	}
+	return backupPath, nil
+}
+
+// WriteFile 先写入同目录下的临时文件再重命名，避免写入中断时留下不完整的文件
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %v", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("写入临时文件失败: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("写入临时文件失败: %v", err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("设置文件权限失败: %v", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("替换文件失败: %v", err)
+	}
+	return nil
+}
+
+// NormalizePath 规范化路径用于比较：统一使用正斜杠并去掉多余的分隔符，Windows上不区分大小写
+func NormalizePath(p string) string {
+	p = strings.ReplaceAll(strings.TrimSpace(p), `\`, "/")
+	if p == "" {
+		return ""
+	}
+	p = path.Clean(p)
+	if runtime.GOOS == "windows" {
+		p = strings.ToLower(p)
+	}
+	return p
+}
@@ -0,0 +1,228 @@
+// Package history 记录每次切换和备份的历史，用于查询和撤销
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"switch/config"
+	"time"
+)
+
+// 操作类型
+const (
+	ActionSwitch = "switch"
+	ActionBackup = "backup"
+	ActionUndo   = "undo"
+)
+
+// 操作结果
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailed  = "failed"
+)
+
+// ScopeSystem 修改的是系统级环境变量
+const ScopeSystem = "system"
+
+// Entry 一条历史记录
+type Entry struct {
+	// ID 记录的唯一标识，由时间生成
+	ID     string    `json:"id"`
+	Time   time.Time `json:"time"`
+	User   string    `json:"user"`
+	Host   string    `json:"host"`
+	Action string    `json:"action"`
+	Scope  string    `json:"scope"`
+	From   string    `json:"from,omitempty"`
+	To     string    `json:"to,omitempty"`
+	// FromProfile 和 Profile 为切换前后的配置方案，直接切换JDK版本时为空
+	FromProfile string `json:"from_profile,omitempty"`
+	Profile     string `json:"profile,omitempty"`
+	// BackupID 操作前所做环境变量备份的标识，撤销时据此恢复
+	BackupID string `json:"backup_id,omitempty"`
+	// Undoes 撤销操作对应的切换记录ID
+	Undoes   string `json:"undoes,omitempty"`
+	Outcome  string `json:"outcome"`
+	Error    string `json:"error,omitempty"`
+	Duration int64  `json:"duration_ms"`
+}
+
+// Path 返回历史记录文件路径
+func Path() string {
+	return filepath.Join(config.Dir(), "history.jsonl")
+}
+
+// Append 追加一条历史记录，未填写的时间、ID、用户和主机名会自动补全
+func Append(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if entry.ID == "" {
+		entry.ID = entry.Time.Format("20060102_150405.000000000")
+	}
+	if entry.User == "" {
+		entry.User = currentUser()
+	}
+	if entry.Host == "" {
+		entry.Host, _ = os.Hostname()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("序列化历史记录失败: %v", err)
+	}
+
+	if err := os.MkdirAll(config.Dir(), 0755); err != nil {
+		return fmt.Errorf("创建配置目录失败: %v", err)
+	}
+	f, err := os.OpenFile(Path(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("打开历史记录文件失败: %v", err)
+	}
+	defer f.Close()
+
+	// 每条记录一次写入一整行，多个进程同时追加时不会交错
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("写入历史记录失败: %v", err)
+	}
+	return nil
+}
+
+// Read 读取全部历史记录，最早的在前；文件不存在时返回空列表
+// 无法解析的行（例如写入时被中断留下的半行）会被跳过
+func Read() ([]Entry, error) {
+	f, err := os.Open(Path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("打开历史记录文件失败: %v", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取历史记录失败: %v", err)
+	}
+	return entries, nil
+}
+
+// Filter 查询条件，零值表示不限制
+type Filter struct {
+	// Version 切换前或切换后的版本
+	Version string
+	Action  string
+	Since   time.Time
+	// Limit 只返回最近的若干条
+	Limit int
+}
+
+// Query 按条件筛选历史记录，保持原有顺序
+func Query(entries []Entry, filter Filter) []Entry {
+	var result []Entry
+	for _, entry := range entries {
+		if filter.Version != "" && entry.From != filter.Version && entry.To != filter.Version {
+			continue
+		}
+		if filter.Action != "" && entry.Action != filter.Action {
+			continue
+		}
+		if !filter.Since.IsZero() && entry.Time.Before(filter.Since) {
+			continue
+		}
+		result = append(result, entry)
+	}
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[len(result)-filter.Limit:]
+	}
+	return result
+}
+
+// LastUndoable 返回最近一次记录了备份且尚未被撤销的切换，多次撤销会依次回到更早的状态
+// 失败的切换如果已经修改了环境变量（记录了备份）同样可以撤销
+func LastUndoable(entries []Entry) (Entry, error) {
+	undone := make(map[string]bool)
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		switch entry.Action {
+		case ActionUndo:
+			if entry.Outcome == OutcomeSuccess {
+				undone[entry.Undoes] = true
+			}
+		case ActionSwitch:
+			if undone[entry.ID] || entry.BackupID == "" {
+				continue
+			}
+			return entry, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("没有可以撤销的切换记录")
+}
+
+// Describe 返回历史记录的单行描述
+func (e Entry) Describe() string {
+	outcome := "成功"
+	if e.Outcome != OutcomeSuccess {
+		outcome = "失败"
+	}
+
+	var what string
+	switch e.Action {
+	case ActionSwitch:
+		what = fmt.Sprintf("切换 %s -> %s", withProfile(orNone(e.From), e.FromProfile), withProfile(e.To, e.Profile))
+	case ActionUndo:
+		what = fmt.Sprintf("撤销 %s -> %s", withProfile(orNone(e.From), e.FromProfile), withProfile(orNone(e.To), e.Profile))
+	case ActionBackup:
+		what = "备份"
+	default:
+		what = e.Action
+	}
+
+	line := fmt.Sprintf("%s  %s@%s  %-7s %s  %s  %dms", e.Time.Format("2006-01-02 15:04:05"), e.User, e.Host, e.Scope, what, outcome, e.Duration)
+	if e.BackupID != "" {
+		line += "  备份: " + e.BackupID
+	}
+	if e.Error != "" {
+		line += "  错误: " + e.Error
+	}
+	return line
+}
+
+func orNone(version string) string {
+	if version == "" {
+		return "(无)"
+	}
+	return version
+}
+
+// withProfile 在版本后附加配置方案名称
+func withProfile(version, profile string) string {
+	if profile == "" {
+		return version
+	}
+	return fmt.Sprintf("%s(%s)", profile, version)
+}
+
+// currentUser 返回当前用户名，无法获取时使用环境变量
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	if name := os.Getenv("USERNAME"); name != "" {
+		return name
+	}
+	return os.Getenv("USER")
+}
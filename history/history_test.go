@@ -0,0 +1,117 @@
+package history
+
+import (
+	"os"
+	"strings"
+	"switch/config"
+	"testing"
+	"time"
+)
+
+// 测试追加和读取历史记录
+func TestAppendAndRead(t *testing.T) {
+	t.Setenv(config.HomeEnv, t.TempDir())
+
+	entries, err := Read()
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("没有历史记录文件时应返回空列表: %v %v", entries, err)
+	}
+
+	if err := Append(Entry{Action: ActionSwitch, Scope: ScopeSystem, From: "8", To: "17", BackupID: "20240101_100000", Outcome: OutcomeSuccess}); err != nil {
+		t.Fatalf("追加历史记录失败: %v", err)
+	}
+	if err := Append(Entry{Action: ActionBackup, Scope: ScopeSystem, BackupID: "20240101_100100", Outcome: OutcomeSuccess}); err != nil {
+		t.Fatalf("追加历史记录失败: %v", err)
+	}
+
+	// 模拟写入被中断留下的半行
+	f, err := os.OpenFile(Path(), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("打开历史记录文件失败: %v", err)
+	}
+	f.WriteString(`{"id":"broken`)
+	f.Close()
+
+	entries, err = Read()
+	if err != nil {
+		t.Fatalf("读取历史记录失败: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("期望2条记录，得到 %d", len(entries))
+	}
+	first := entries[0]
+	if first.ID == "" || first.Time.IsZero() || first.User == "" && first.Host == "" {
+		t.Errorf("ID、时间、用户和主机名应自动补全: %+v", first)
+	}
+	if first.From != "8" || first.To != "17" || first.BackupID != "20240101_100000" {
+		t.Errorf("记录内容不正确: %+v", first)
+	}
+	if !strings.Contains(first.Describe(), "切换 8 -> 17") {
+		t.Errorf("描述不正确: %s", first.Describe())
+	}
+}
+
+// 测试按条件查询
+func TestQuery(t *testing.T) {
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{ID: "1", Time: base, Action: ActionSwitch, From: "8", To: "11"},
+		{ID: "2", Time: base.Add(time.Hour), Action: ActionBackup},
+		{ID: "3", Time: base.Add(2 * time.Hour), Action: ActionSwitch, From: "11", To: "17"},
+		{ID: "4", Time: base.Add(3 * time.Hour), Action: ActionSwitch, From: "17", To: "21"},
+	}
+
+	ids := func(entries []Entry) string {
+		var result []string
+		for _, e := range entries {
+			result = append(result, e.ID)
+		}
+		return strings.Join(result, ",")
+	}
+
+	tests := []struct {
+		filter Filter
+		want   string
+	}{
+		{Filter{}, "1,2,3,4"},
+		{Filter{Version: "11"}, "1,3"},
+		{Filter{Action: ActionBackup}, "2"},
+		{Filter{Since: base.Add(90 * time.Minute)}, "3,4"},
+		{Filter{Action: ActionSwitch, Limit: 2}, "3,4"},
+	}
+	for _, tt := range tests {
+		if got := ids(Query(entries, tt.filter)); got != tt.want {
+			t.Errorf("%+v: 得到 %s，期望 %s", tt.filter, got, tt.want)
+		}
+	}
+}
+
+// 测试撤销目标的选择：跳过没有备份和已撤销的切换
+func TestLastUndoable(t *testing.T) {
+	entries := []Entry{
+		{ID: "1", Action: ActionSwitch, From: "8", To: "11", BackupID: "b1", Outcome: OutcomeSuccess},
+		{ID: "2", Action: ActionSwitch, From: "11", To: "17", BackupID: "b2", Outcome: OutcomeSuccess},
+		{ID: "3", Action: ActionSwitch, From: "17", To: "21", Outcome: OutcomeFailed},
+	}
+
+	entry, err := LastUndoable(entries)
+	if err != nil || entry.ID != "2" {
+		t.Fatalf("应撤销记录2，得到 %+v %v", entry, err)
+	}
+
+	entries = append(entries, Entry{ID: "4", Action: ActionUndo, Undoes: "2", Outcome: OutcomeSuccess})
+	if entry, err = LastUndoable(entries); err != nil || entry.ID != "1" {
+		t.Fatalf("撤销记录2后应撤销记录1，得到 %+v %v", entry, err)
+	}
+
+	// 失败的撤销不算数
+	entries = append(entries, Entry{ID: "5", Action: ActionUndo, Undoes: "1", Outcome: OutcomeFailed})
+	if entry, err = LastUndoable(entries); err != nil || entry.ID != "1" {
+		t.Fatalf("撤销失败后仍应撤销记录1，得到 %+v %v", entry, err)
+	}
+
+	entries = append(entries, Entry{ID: "6", Action: ActionUndo, Undoes: "1", Outcome: OutcomeSuccess})
+	if _, err = LastUndoable(entries); err == nil {
+		t.Errorf("全部撤销后应返回错误")
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"switch/config"
+	"switch/jdk"
+)
+
+// setVersionHomes 开启或关闭主版本环境变量（如 JAVA_17_HOME），name不为空时同时修改名称模板
+func setVersionHomes(mode, name string) error {
+	var enabled bool
+	switch strings.ToLower(mode) {
+	case "on":
+		enabled = true
+	case "off":
+		enabled = false
+	default:
+		return fmt.Errorf("用法: -version-homes on|off")
+	}
+	if err := config.ValidateVersionHomeName(name); err != nil {
+		return err
+	}
+	if enabled && runtime.GOOS != "windows" {
+		return fmt.Errorf("当前只支持Windows系统")
+	}
+
+	updated, err := config.Update(func(c *config.Config) error {
+		c.VersionHomes = enabled
+		if name != "" {
+			c.VersionHomeName = name
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := syncVersionHomes(updated); err != nil {
+		return err
+	}
+	if !enabled {
+		fmt.Println("已关闭主版本环境变量")
+		return nil
+	}
+
+	homes := jdk.VersionHomes(updated)
+	names := make([]string, 0, len(homes))
+	for name := range homes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Println("已开启主版本环境变量，添加、删除、重命名JDK或修改JDK路径后会自动同步:")
+	for _, name := range names {
+		fmt.Printf("  %s=%s\n", name, homes[name])
+	}
+	return nil
+}
+
+// syncVersionHomesAfterChange 修改JDK条目后同步主版本环境变量，失败时只提示，不影响已保存的修改
+func syncVersionHomesAfterChange(cfg *config.Config) {
+	if _, err := syncVersionHomes(cfg); err != nil {
+		fmt.Printf("警告: 同步主版本环境变量失败: %v\n", err)
+	}
+}
+
+// versionHomesStale 判断记录的主版本环境变量是否与当前JDK条目不一致，例如直接编辑过config.json
+// 只比较配置，不读取注册表
+func versionHomesStale(cfg *config.Config) bool {
+	want := map[string]string{}
+	if cfg.VersionHomes {
+		want = jdk.VersionHomes(cfg)
+	}
+	return !sameVersionHomes(want, cfg.ManagedVersionHomes)
+}
+
+// syncVersionHomes 同步主版本环境变量，系统环境变量有变化时输出修改的变量，记录的变量有变化时保存到配置中
+// 在修改JDK条目的函数（命令行和交互模式共用）、-version-homes之后调用，切换时如果记录与JDK条目不一致也会调用；
+// 只读的命令不会修改注册表
+func syncVersionHomes(cfg *config.Config) (*config.Config, error) {
+	want, changed, err := jdk.SyncVersionHomes(cfg)
+	if err != nil {
+		return cfg, err
+	}
+	for _, name := range changed {
+		if value, ok := want[name]; ok {
+			fmt.Printf("已设置系统环境变量 %s=%s\n", name, value)
+		} else {
+			fmt.Printf("已删除系统环境变量 %s\n", name)
+		}
+	}
+	if sameVersionHomes(want, cfg.ManagedVersionHomes) {
+		return cfg, nil
+	}
+	return config.Update(func(c *config.Config) error {
+		c.ManagedVersionHomes = want
+		if len(want) == 0 {
+			c.ManagedVersionHomes = nil
+		}
+		return nil
+	})
+}
+
+// sameVersionHomes 比较两组主版本环境变量是否相同
+func sameVersionHomes(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, value := range a {
+		if other, ok := b[name]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"switch/config"
+	"time"
+)
+
+// 钩子执行阶段
+const (
+	PhasePre  = "pre"
+	PhasePost = "post"
+)
+
+// DefaultTimeout 钩子未配置超时时间时使用的默认值
+const DefaultTimeout = 60 * time.Second
+
+// Context 描述一次切换的信息，会以环境变量的形式传递给钩子
+type Context struct {
+	Phase       string
+	OldVersion  string
+	NewVersion  string
+	OldJavaHome string
+	NewJavaHome string
+}
+
+// Environ 返回传递给钩子进程的环境变量（在当前进程环境变量基础上追加）
+func (c Context) Environ() []string {
+	return append(os.Environ(),
+		"JDK_SWITCH_PHASE="+c.Phase,
+		"JDK_SWITCH_OLD_VERSION="+c.OldVersion,
+		"JDK_SWITCH_NEW_VERSION="+c.NewVersion,
+		"JDK_SWITCH_OLD_JAVA_HOME="+c.OldJavaHome,
+		"JDK_SWITCH_NEW_JAVA_HOME="+c.NewJavaHome,
+	)
+}
+
+// Run 按顺序执行钩子，命令输出和执行结果写入log
+// 任一钩子失败（退出码非0或超时）时立即返回错误，后续钩子不再执行
+func Run(hooks []config.Hook, ctx Context, log io.Writer) error {
+	for _, h := range hooks {
+		if err := runOne(h, ctx, log); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runOne 执行单个钩子
+func runOne(h config.Hook, ctx Context, log io.Writer) error {
+	timeout := DefaultTimeout
+	if h.Timeout > 0 {
+		timeout = time.Duration(h.Timeout) * time.Second
+	}
+
+	execCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := shellCommand(execCtx, h.Command)
+	cmd.Env = ctx.Environ()
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	// 超时后命令解释器被结束，但其子进程可能仍占用输出管道，最多再等待一秒
+	cmd.WaitDelay = time.Second
+
+	fmt.Printf("执行%s钩子: %s\n", phaseName(ctx.Phase), h.Command)
+	fmt.Fprintf(log, "[%s] %s钩子: %s\n", time.Now().Format("2006-01-02 15:04:05"), phaseName(ctx.Phase), h.Command)
+
+	start := time.Now()
+	err := cmd.Run()
+	duration := time.Since(start)
+
+	log.Write(output.Bytes())
+	if output.Len() > 0 && output.Bytes()[output.Len()-1] != '\n' {
+		fmt.Fprintln(log)
+	}
+
+	if errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+		fmt.Fprintf(log, "钩子超时 (%s)\n", timeout)
+		return fmt.Errorf("钩子 %q 执行超时 (%s)", h.Command, timeout)
+	}
+	if err != nil {
+		fmt.Fprintf(log, "钩子失败 (耗时 %s): %v\n", duration, err)
+		return fmt.Errorf("钩子 %q 执行失败: %v", h.Command, err)
+	}
+
+	fmt.Fprintf(log, "钩子完成 (耗时 %s)\n", duration)
+	return nil
+}
+
+// shellCommand 使用系统命令解释器创建命令
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// phaseName 返回阶段的中文名称
+func phaseName(phase string) string {
+	if phase == PhasePre {
+		return "切换前"
+	}
+	return "切换后"
+}
@@ -0,0 +1,71 @@
+package hook
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"switch/config"
+	"testing"
+)
+
+// 钩子测试使用sh命令，Windows上跳过
+func skipOnWindows(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("钩子测试使用sh命令，跳过Windows平台")
+	}
+}
+
+// 测试钩子能拿到切换信息，输出写入日志
+func TestRunPassesEnvironment(t *testing.T) {
+	skipOnWindows(t)
+
+	ctx := Context{
+		Phase:       PhasePre,
+		OldVersion:  "8",
+		NewVersion:  "17",
+		OldJavaHome: "/opt/jdk8",
+		NewJavaHome: "/opt/jdk17",
+	}
+	hooks := []config.Hook{
+		{Command: `echo "$JDK_SWITCH_PHASE $JDK_SWITCH_OLD_VERSION->$JDK_SWITCH_NEW_VERSION $JDK_SWITCH_NEW_JAVA_HOME"`},
+	}
+
+	var log bytes.Buffer
+	if err := Run(hooks, ctx, &log); err != nil {
+		t.Fatalf("Run 错误: %v", err)
+	}
+	if !strings.Contains(log.String(), "pre 8->17 /opt/jdk17") {
+		t.Errorf("日志中没有钩子输出: %s", log.String())
+	}
+}
+
+// 测试钩子失败时返回错误并停止执行后续钩子
+func TestRunStopsOnFailure(t *testing.T) {
+	skipOnWindows(t)
+
+	hooks := []config.Hook{
+		{Command: "echo first; exit 3"},
+		{Command: "echo second"},
+	}
+
+	var log bytes.Buffer
+	if err := Run(hooks, Context{Phase: PhasePre}, &log); err == nil {
+		t.Fatal("钩子失败时应该返回错误")
+	}
+	if strings.Contains(log.String(), "second") {
+		t.Errorf("失败后不应执行后续钩子: %s", log.String())
+	}
+}
+
+// 测试钩子超时
+func TestRunTimeout(t *testing.T) {
+	skipOnWindows(t)
+
+	hooks := []config.Hook{{Command: "sleep 5", Timeout: 1}}
+
+	var log bytes.Buffer
+	err := Run(hooks, Context{Phase: PhasePost}, &log)
+	if err == nil || !strings.Contains(err.Error(), "超时") {
+		t.Fatalf("期望超时错误, 得到 %v", err)
+	}
+}
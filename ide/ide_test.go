@@ -0,0 +1,126 @@
+package ide
+
+import (
+	"encoding/json"
+	"strings"
+	"switch/jdk"
+	"testing"
+)
+
+// 测试合并IntelliJ的jdk.table.xml
+func TestRenderIntelliJJDKTable(t *testing.T) {
+	existing := `<application>
+  <component name="ProjectJdkTable">
+    <jdk version="2">
+      <name value="corretto-11" />
+      <type value="JavaSDK" />
+      <homePath value="C:/Users/dev/.jdks/corretto-11" />
+    </jdk>
+    <jdk version="2">
+      <name value="17" />
+      <type value="JavaSDK" />
+      <homePath value="C:/old/jdk-17" />
+    </jdk>
+  </component>
+</application>
+`
+	jdks := []jdk.Installation{
+		{Key: "17", Path: `C:\Java\jdk-17`, Release: &jdk.Release{JavaVersion: "17.0.2"}},
+	}
+
+	data, err := RenderIntelliJJDKTable([]byte(existing), jdks)
+	if err != nil {
+		t.Fatalf("RenderIntelliJJDKTable 错误: %v", err)
+	}
+	out := string(data)
+	if !strings.Contains(out, "corretto-11") {
+		t.Errorf("无关的JDK定义应被保留:\n%s", out)
+	}
+	if strings.Contains(out, "C:/old/jdk-17") {
+		t.Errorf("同名的JDK定义应被替换:\n%s", out)
+	}
+	if !strings.Contains(out, `<homePath value="C:/Java/jdk-17" />`) || !strings.Contains(out, `java version &#34;17.0.2&#34;`) {
+		t.Errorf("缺少新的JDK定义:\n%s", out)
+	}
+
+	again, err := RenderIntelliJJDKTable(data, jdks)
+	if err != nil || string(again) != out {
+		t.Errorf("重复合并结果不一致: %v\n%s", err, again)
+	}
+}
+
+// 测试合并VS Code的settings.json
+func TestRenderVSCodeSettings(t *testing.T) {
+	existing := `{
+    // 编辑器设置
+    "editor.fontSize": 14,
+    "java.configuration.runtimes": [
+        { "name": "JavaSE-11", "path": "/opt/jdk-11", "sources": "/opt/jdk-11/lib/src.zip", "default": true },
+        { "name": "JavaSE-17", "path": "/old/jdk-17" },
+    ],
+}`
+	jdks := []jdk.Installation{
+		{Key: "8", Path: "/opt/jdk8", Release: &jdk.Release{JavaVersion: "1.8.0_301"}},
+		{Key: "17", Path: "/opt/jdk-17", Release: &jdk.Release{JavaVersion: "17.0.2"}},
+		{Key: "temurin-17", Path: "/opt/temurin-17", Release: &jdk.Release{JavaVersion: "17.0.9"}},
+	}
+
+	data, err := RenderVSCodeSettings([]byte(existing), jdks, "temurin-17")
+	if err != nil {
+		t.Fatalf("RenderVSCodeSettings 错误: %v", err)
+	}
+
+	var settings struct {
+		FontSize int `json:"editor.fontSize"`
+		Runtimes []struct {
+			Name    string `json:"name"`
+			Path    string `json:"path"`
+			Default bool   `json:"default"`
+			Sources string `json:"sources"`
+		} `json:"java.configuration.runtimes"`
+	}
+	if err := json.Unmarshal(stripJSONC(data), &settings); err != nil {
+		t.Fatalf("结果不是合法JSON: %v\n%s", err, data)
+	}
+	if !strings.HasPrefix(string(data), "{\n    // 编辑器设置\n    \"editor.fontSize\": 14,\n") || !strings.HasSuffix(string(data), "],\n}") {
+		t.Errorf("其他设置、注释和格式应保持原样:\n%s", data)
+	}
+
+	got := make(map[string]string)
+	for _, rt := range settings.Runtimes {
+		got[rt.Name] = rt.Path
+		if rt.Default != (rt.Name == "JavaSE-17") {
+			t.Errorf("只有当前版本应为default: %+v", rt)
+		}
+		if rt.Name == "JavaSE-11" && rt.Sources == "" {
+			t.Errorf("已有条目的其他字段应被保留: %+v", rt)
+		}
+	}
+	expected := map[string]string{"JavaSE-11": "/opt/jdk-11", "JavaSE-1.8": "/opt/jdk8", "JavaSE-17": "/opt/temurin-17"}
+	if len(got) != len(expected) {
+		t.Fatalf("期望 %v, 得到 %v", expected, got)
+	}
+	for name, path := range expected {
+		if got[name] != path {
+			t.Errorf("%s 期望路径 %s, 得到 %s", name, path, got[name])
+		}
+	}
+}
+
+// 测试settings.json中没有运行时设置时追加到末尾
+func TestRenderVSCodeSettingsAppend(t *testing.T) {
+	jdks := []jdk.Installation{{Key: "17", Path: "/opt/jdk-17", Release: &jdk.Release{JavaVersion: "17.0.2"}}}
+	cases := map[string]string{
+		"{\n  \"a\": 1 // 注释\n}\n": "{\n  \"a\": 1, // 注释\n  \"java.configuration.runtimes\": [\n    {\n      \"name\": \"JavaSE-17\",\n      \"path\": \"/opt/jdk-17\",\n      \"default\": true\n    }\n  ]\n}\n",
+		"{}":                       "{\n    \"java.configuration.runtimes\": [\n        {\n            \"name\": \"JavaSE-17\",\n            \"path\": \"/opt/jdk-17\",\n            \"default\": true\n        }\n    ]\n}",
+	}
+	for existing, want := range cases {
+		data, err := RenderVSCodeSettings([]byte(existing), jdks, "17")
+		if err != nil {
+			t.Fatalf("RenderVSCodeSettings(%q) 错误: %v", existing, err)
+		}
+		if string(data) != want {
+			t.Errorf("RenderVSCodeSettings(%q):\n期望:\n%s\n得到:\n%s", existing, want, data)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package ide
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"switch/config"
+	"switch/fsutil"
+	"switch/jdk"
+	"switch/xmlutil"
+)
+
+// jdkTableFile IntelliJ IDEA保存JDK定义的文件名
+const jdkTableFile = "jdk.table.xml"
+
+// IntelliJJDKTablePath 返回IDE配置目录下的jdk.table.xml路径
+// configDir可以是IDE配置目录（如 %APPDATA%\JetBrains\IntelliJIdea2024.1），也可以是其中的options目录
+func IntelliJJDKTablePath(configDir string) string {
+	if strings.EqualFold(filepath.Base(filepath.Clean(configDir)), "options") {
+		return filepath.Join(configDir, jdkTableFile)
+	}
+	return filepath.Join(configDir, "options", jdkTableFile)
+}
+
+// ExportIntelliJ 将配置中的JDK写入IntelliJ IDEA的jdk.table.xml，已有文件会先备份
+// 返回写入的文件路径和备份文件路径（原文件不存在或内容没有变化时备份路径为空）
+func ExportIntelliJ(cfg *config.Config, configDir string) (string, string, error) {
+	path := IntelliJJDKTablePath(configDir)
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return path, "", fmt.Errorf("读取jdk.table.xml失败: %v", err)
+	}
+
+	data, err := RenderIntelliJJDKTable(existing, jdk.Installations(cfg.JDKPaths))
+	if err != nil {
+		return path, "", err
+	}
+	if bytes.Equal(data, existing) {
+		return path, "", nil
+	}
+
+	backup, err := fsutil.BackupFile(path)
+	if err != nil {
+		return path, "", fmt.Errorf("备份jdk.table.xml失败: %v", err)
+	}
+	if err := fsutil.WriteFile(path, data, 0644); err != nil {
+		return path, "", fmt.Errorf("写入jdk.table.xml失败: %v", err)
+	}
+	return path, backup, nil
+}
+
+// RenderIntelliJJDKTable 将JDK列表合并进已有的jdk.table.xml内容
+// 名称或homePath与配置相同的JDK定义会被替换，其他内容保持原样
+func RenderIntelliJJDKTable(existing []byte, jdks []jdk.Installation) ([]byte, error) {
+	var blocks strings.Builder
+	names := make(map[string]bool)
+	homes := make(map[string]bool)
+	for _, install := range jdks {
+		blocks.WriteString(renderIntelliJJDK(install))
+		names[install.Key] = true
+		homes[fsutil.NormalizePath(install.Path)] = true
+	}
+
+	const componentStart = `<component name="ProjectJdkTable">`
+	if len(bytes.TrimSpace(existing)) == 0 {
+		return []byte("<application>\n  " + componentStart + "\n" + blocks.String() + "  </component>\n</application>\n"), nil
+	}
+
+	isTable := func(e xml.StartElement) bool {
+		if e.Name.Local != "component" {
+			return false
+		}
+		for _, attr := range e.Attr {
+			if attr.Name.Local == "name" {
+				return attr.Value == "ProjectJdkTable"
+			}
+		}
+		return false
+	}
+	replaced, closing, err := xmlutil.RemoveChildren(existing, isTable, "jdk", func(entry intelliJJDK) bool {
+		return names[entry.Name.Value] || homes[fsutil.NormalizePath(entry.HomePath.Value)]
+	})
+	if err != nil {
+		return nil, fmt.Errorf("解析jdk.table.xml失败: %v", err)
+	}
+	if closing >= 0 {
+		// 新条目放在组件结束标签所在行之前
+		return xmlutil.Splice(existing, replaced, closing, blocks.String()), nil
+	}
+
+	// 没有JDK表时在</application>前添加
+	closing, err = xmlutil.Closing(existing, func(e xml.StartElement) bool { return e.Name.Local == "application" })
+	if err != nil {
+		return nil, fmt.Errorf("解析jdk.table.xml失败: %v", err)
+	}
+	if closing < 0 {
+		return nil, fmt.Errorf("jdk.table.xml格式错误: 缺少 </application>")
+	}
+	component := "  " + componentStart + "\n" + blocks.String() + "  </component>\n"
+	return xmlutil.Splice(existing, nil, closing, component), nil
+}
+
+// intelliJJDK jdk.table.xml中单个JDK定义，只解析合并时需要的字段
+type intelliJJDK struct {
+	Name struct {
+		Value string `xml:"value,attr"`
+	} `xml:"name"`
+	HomePath struct {
+		Value string `xml:"value,attr"`
+	} `xml:"homePath"`
+}
+
+// renderIntelliJJDK 生成单个JDK的定义，类路径等由IDE打开时自动补全
+func renderIntelliJJDK(install jdk.Installation) string {
+	// IntelliJ在所有平台上都使用正斜杠保存路径
+	home := strings.ReplaceAll(install.Path, `\`, "/")
+	var b strings.Builder
+	b.WriteString("    <jdk version=\"2\">\n")
+	fmt.Fprintf(&b, "      <name value=\"%s\" />\n", xmlutil.Escape(install.Key))
+	b.WriteString("      <type value=\"JavaSDK\" />\n")
+	fmt.Fprintf(&b, "      <version value=\"%s\" />\n", xmlutil.Escape(fmt.Sprintf("java version \"%s\"", install.Version())))
+	fmt.Fprintf(&b, "      <homePath value=\"%s\" />\n", xmlutil.Escape(home))
+	b.WriteString("      <roots>\n")
+	b.WriteString("        <annotationsPath>\n          <root type=\"composite\" />\n        </annotationsPath>\n")
+	b.WriteString("        <classPath>\n          <root type=\"composite\" />\n        </classPath>\n")
+	b.WriteString("        <javadocPath>\n          <root type=\"composite\" />\n        </javadocPath>\n")
+	b.WriteString("        <sourcePath>\n          <root type=\"composite\" />\n        </sourcePath>\n")
+	b.WriteString("      </roots>\n")
+	b.WriteString("      <additional />\n")
+	b.WriteString("    </jdk>\n")
+	return b.String()
+}
@@ -0,0 +1,443 @@
+package ide
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"switch/config"
+	"switch/fsutil"
+	"switch/jdk"
+)
+
+// vscodeRuntimesKey VS Code Java扩展中配置JDK列表的设置项
+const vscodeRuntimesKey = "java.configuration.runtimes"
+
+// VSCodeSettingsPath 返回VS Code用户级settings.json的默认路径
+func VSCodeSettingsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户配置目录失败: %v", err)
+	}
+	return filepath.Join(dir, "Code", "User", "settings.json"), nil
+}
+
+// vscodeRuntime java.configuration.runtimes中的单个条目
+type vscodeRuntime struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Default bool   `json:"default,omitempty"`
+	// Extra 保留条目中的其他字段（如sources、javadoc）
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// ExportVSCode 将配置中的JDK写入VS Code的java.configuration.runtimes，已有文件会先备份
+// 当前版本(current_version)对应的条目设为default。返回备份文件路径
+func ExportVSCode(cfg *config.Config, settingsPath string) (string, error) {
+	existing, err := os.ReadFile(settingsPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("读取settings.json失败: %v", err)
+	}
+
+	data, err := RenderVSCodeSettings(existing, jdk.Installations(cfg.JDKPaths), cfg.CurrentVersion)
+	if err != nil {
+		return "", err
+	}
+	if bytes.Equal(data, existing) {
+		return "", nil
+	}
+
+	backup, err := fsutil.BackupFile(settingsPath)
+	if err != nil {
+		return "", fmt.Errorf("备份settings.json失败: %v", err)
+	}
+	if err := fsutil.WriteFile(settingsPath, data, 0644); err != nil {
+		return "", fmt.Errorf("写入settings.json失败: %v", err)
+	}
+	return backup, nil
+}
+
+// RenderVSCodeSettings 将JDK列表合并进已有的settings.json内容
+// 名称(JavaSE-N)或路径相同的条目会被替换，其他条目保留；只改写java.configuration.runtimes的值，
+// 文件中的其他设置、注释和格式保持原样，没有该设置时追加到末尾
+// VS Code要求每个名称只出现一次，同一主版本有多个JDK时优先使用当前版本，否则使用排序靠前的JDK
+func RenderVSCodeSettings(existing []byte, jdks []jdk.Installation, current string) ([]byte, error) {
+	if len(bytes.TrimSpace(existing)) == 0 {
+		existing = []byte("{\n}\n")
+	}
+	member, err := findMember(existing, vscodeRuntimesKey)
+	if err != nil {
+		return nil, fmt.Errorf("解析settings.json失败: %v", err)
+	}
+
+	// 每个运行时名称选出一个JDK
+	chosen := make(map[string]jdk.Installation)
+	var order []string
+	for _, install := range jdks {
+		name := RuntimeName(install.Major())
+		if name == "" {
+			continue
+		}
+		if _, exists := chosen[name]; !exists {
+			order = append(order, name)
+		} else if install.Key != current {
+			continue
+		}
+		chosen[name] = install
+	}
+
+	ours := make(map[string]bool)
+	for _, install := range chosen {
+		ours[fsutil.NormalizePath(install.Path)] = true
+	}
+
+	var runtimes []vscodeRuntime
+	if member.found {
+		var items []map[string]json.RawMessage
+		if err := json.Unmarshal(stripJSONC(existing[member.valueStart:member.valueEnd]), &items); err != nil {
+			return nil, fmt.Errorf("解析 %s 失败: %v", vscodeRuntimesKey, err)
+		}
+		for _, item := range items {
+			rt := decodeRuntime(item)
+			if _, replaced := chosen[rt.Name]; replaced || ours[fsutil.NormalizePath(rt.Path)] {
+				continue
+			}
+			// 默认运行时由当前版本决定
+			rt.Default = false
+			runtimes = append(runtimes, rt)
+		}
+	}
+
+	for _, name := range order {
+		install := chosen[name]
+		runtimes = append(runtimes, vscodeRuntime{
+			Name:    name,
+			Path:    install.Path,
+			Default: install.Key == current,
+		})
+	}
+
+	// 追加时设置项使用一级缩进
+	indent := member.indent(existing)
+	unit := indent
+	if unit == "" {
+		unit = "    "
+	}
+	if !member.found {
+		indent = unit
+	}
+	value, err := encodeRuntimes(runtimes, indent, unit)
+	if err != nil {
+		return nil, fmt.Errorf("序列化 %s 失败: %v", vscodeRuntimesKey, err)
+	}
+
+	var out bytes.Buffer
+	if member.found {
+		out.Write(existing[:member.valueStart])
+		out.Write(value)
+		out.Write(existing[member.valueEnd:])
+		return out.Bytes(), nil
+	}
+
+	// 在顶层对象的结束括号所在行之前追加，最后一个成员后没有逗号时补上
+	at := member.closing
+	lineStart := bytes.LastIndexByte(existing[:at], '\n') + 1
+	ownLine := lineStart > member.last && len(bytes.TrimSpace(existing[lineStart:at])) == 0
+	if ownLine {
+		at = lineStart
+	}
+	if member.last >= 0 && !member.comma {
+		out.Write(existing[:member.last])
+		out.WriteByte(',')
+		out.Write(existing[member.last:at])
+	} else {
+		out.Write(existing[:at])
+	}
+	if !ownLine {
+		out.WriteByte('\n')
+	}
+	fmt.Fprintf(&out, "%s%q: %s\n", indent, vscodeRuntimesKey, value)
+	out.Write(existing[at:])
+	return out.Bytes(), nil
+}
+
+// RuntimeName 返回主版本号对应的VS Code运行时名称，如 JavaSE-17、JavaSE-1.8，无法识别时返回空字符串
+func RuntimeName(major int) string {
+	switch {
+	case major <= 0:
+		return ""
+	case major <= 8:
+		return "JavaSE-1." + strconv.Itoa(major)
+	default:
+		return "JavaSE-" + strconv.Itoa(major)
+	}
+}
+
+// decodeRuntime 从JSON对象解析运行时条目，保留未知字段
+func decodeRuntime(item map[string]json.RawMessage) vscodeRuntime {
+	var rt vscodeRuntime
+	json.Unmarshal(item["name"], &rt.Name)
+	json.Unmarshal(item["path"], &rt.Path)
+	json.Unmarshal(item["default"], &rt.Default)
+	rt.Extra = make(map[string]json.RawMessage)
+	for key, value := range item {
+		if key != "name" && key != "path" && key != "default" {
+			rt.Extra[key] = value
+		}
+	}
+	return rt
+}
+
+// encodeRuntimes 将运行时条目编码为JSON数组，字段按name、path、其他字段、default的顺序排列
+// indent为设置项所在行的缩进，unit为每级缩进
+func encodeRuntimes(runtimes []vscodeRuntime, indent, unit string) ([]byte, error) {
+	var compact bytes.Buffer
+	compact.WriteByte('[')
+	for i, rt := range runtimes {
+		if i > 0 {
+			compact.WriteByte(',')
+		}
+		name, _ := json.Marshal(rt.Name)
+		path, _ := json.Marshal(rt.Path)
+		fmt.Fprintf(&compact, `{"name":%s,"path":%s`, name, path)
+		keys := make([]string, 0, len(rt.Extra))
+		for key := range rt.Extra {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			encoded, _ := json.Marshal(key)
+			fmt.Fprintf(&compact, ",%s:%s", encoded, rt.Extra[key])
+		}
+		if rt.Default {
+			compact.WriteString(`,"default":true`)
+		}
+		compact.WriteByte('}')
+	}
+	compact.WriteByte(']')
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact.Bytes(), indent, unit); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// jsoncMember settings.json顶层对象中某个成员在原内容中的位置
+type jsoncMember struct {
+	found      bool
+	keyStart   int
+	valueStart int
+	valueEnd   int
+	// first 第一个成员键的位置，last 最后一个成员值之后的位置，没有成员时都为-1
+	first, last int
+	// comma 最后一个成员后是否已有逗号
+	comma bool
+	// closing 顶层对象结束括号的位置
+	closing int
+}
+
+// indent 返回该成员（不存在时为第一个成员）所在行的缩进，没有成员时返回空字符串
+func (m *jsoncMember) indent(data []byte) string {
+	pos := m.first
+	if m.found {
+		pos = m.keyStart
+	}
+	if pos < 0 {
+		return ""
+	}
+	lineStart := bytes.LastIndexByte(data[:pos], '\n') + 1
+	prefix := data[lineStart:pos]
+	if len(bytes.TrimLeft(prefix, " \t")) != 0 {
+		return ""
+	}
+	return string(prefix)
+}
+
+// findMember 在JSONC内容的顶层对象中查找名为key的成员，允许注释和多余的逗号
+func findMember(data []byte, key string) (*jsoncMember, error) {
+	i := skipJSONCSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return nil, fmt.Errorf("顶层不是JSON对象")
+	}
+	m := &jsoncMember{first: -1, last: -1}
+	i = skipJSONCSpace(data, i+1)
+	for {
+		if i >= len(data) {
+			return nil, fmt.Errorf("内容不完整")
+		}
+		if data[i] == '}' {
+			m.closing = i
+			return m, nil
+		}
+		if data[i] != '"' {
+			return nil, fmt.Errorf("位置 %d 处应为设置名称", i)
+		}
+		keyStart := i
+		keyEnd, err := skipJSONValue(data, i)
+		if err != nil {
+			return nil, err
+		}
+		var name string
+		if err := json.Unmarshal(data[keyStart:keyEnd], &name); err != nil {
+			return nil, fmt.Errorf("位置 %d 处的设置名称无效: %v", keyStart, err)
+		}
+		i = skipJSONCSpace(data, keyEnd)
+		if i >= len(data) || data[i] != ':' {
+			return nil, fmt.Errorf("位置 %d 处缺少冒号", i)
+		}
+		valueStart := skipJSONCSpace(data, i+1)
+		valueEnd, err := skipJSONValue(data, valueStart)
+		if err != nil {
+			return nil, err
+		}
+		if m.first < 0 {
+			m.first = keyStart
+		}
+		// 重复的设置以最后一处为准，与VS Code一致
+		if name == key {
+			m.found, m.keyStart, m.valueStart, m.valueEnd = true, keyStart, valueStart, valueEnd
+		}
+		m.last, m.comma = valueEnd, false
+
+		i = skipJSONCSpace(data, valueEnd)
+		if i < len(data) && data[i] == ',' {
+			m.comma = true
+			i = skipJSONCSpace(data, i+1)
+		} else if i < len(data) && data[i] != '}' {
+			return nil, fmt.Errorf("位置 %d 处缺少逗号", i)
+		}
+	}
+}
+
+// skipJSONCSpace 跳过空白和注释，返回下一个有效字符的位置
+func skipJSONCSpace(data []byte, i int) int {
+	for i < len(data) {
+		switch {
+		case data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r':
+			i++
+		case bytes.HasPrefix(data[i:], []byte("//")):
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case bytes.HasPrefix(data[i:], []byte("/*")):
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return len(data)
+			}
+			i += end + 4
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// skipJSONValue 跳过从i开始的一个值（字符串、对象、数组或字面量），返回值之后的位置
+func skipJSONValue(data []byte, i int) (int, error) {
+	if i >= len(data) {
+		return 0, fmt.Errorf("内容不完整")
+	}
+	switch data[i] {
+	case '"':
+		for j := i + 1; j < len(data); j++ {
+			switch data[j] {
+			case '\\':
+				j++
+			case '"':
+				return j + 1, nil
+			}
+		}
+		return 0, fmt.Errorf("位置 %d 处的字符串没有结束", i)
+	case '{', '[':
+		depth := 0
+		for j := i; j < len(data); {
+			j = skipJSONCSpace(data, j)
+			if j >= len(data) {
+				break
+			}
+			switch data[j] {
+			case '"':
+				end, err := skipJSONValue(data, j)
+				if err != nil {
+					return 0, err
+				}
+				j = end
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return j + 1, nil
+				}
+			}
+			j++
+		}
+		return 0, fmt.Errorf("位置 %d 处的括号没有结束", i)
+	default:
+		j := i
+		for j < len(data) && !bytes.ContainsRune([]byte(",}] \t\r\n/"), rune(data[j])) {
+			j++
+		}
+		if j == i {
+			return 0, fmt.Errorf("位置 %d 处应为设置值", i)
+		}
+		return j, nil
+	}
+}
+
+// stripJSONC 去掉VS Code设置文件中允许的注释和结尾多余的逗号
+func stripJSONC(data []byte) []byte {
+	var out []byte
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				i = len(data)
+			} else {
+				i += end + 3
+			}
+		case c == ']' || c == '}':
+			// 删除右括号前的多余逗号
+			j := len(out) - 1
+			for j >= 0 && (out[j] == ' ' || out[j] == '\t' || out[j] == '\n' || out[j] == '\r') {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,239 @@
+// Package importer 从其他JDK管理工具的安装目录和Windows注册表中查找已安装的JDK
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"switch/config"
+	"switch/jdk"
+)
+
+// Env 扫描时使用的用户目录和环境变量，测试中可以替换
+type Env struct {
+	Home   string
+	Getenv func(string) string
+}
+
+// DefaultEnv 返回当前用户的扫描环境
+func DefaultEnv() Env {
+	home, _ := os.UserHomeDir()
+	return Env{Home: home, Getenv: os.Getenv}
+}
+
+// Candidate 找到的一个JDK安装
+type Candidate struct {
+	// Source 来源名称，如 sdkman、jabba
+	Source string
+	// ID 来源工具自己的标识，如 17.0.9-tem、zulu@1.17.0，用作建议的版本名称
+	ID   string
+	Path string
+}
+
+// Source 一个导入来源
+type Source struct {
+	Name string
+	// Description 来源说明
+	Description string
+	Scan        func(env Env) ([]Candidate, error)
+}
+
+// Sources 所有支持的导入来源
+var Sources = []Source{
+	{"sdkman", "SDKMAN (~/.sdkman/candidates/java)", scanSDKMAN},
+	{"jabba", "jabba (~/.jabba/jdk)", scanJabba},
+	{"asdf", "asdf (~/.asdf/installs/java)", scanAsdf},
+	{"mise", "mise (~/.local/share/mise/installs/java)", scanMise},
+	{"intellij", "IntelliJ IDEA (~/.jdks)", scanIntelliJ},
+	{"registry", "Windows注册表 (HKLM\\SOFTWARE\\JavaSoft)", scanRegistry},
+}
+
+// Scan 扫描指定来源（为空时扫描全部来源），某个来源出错时继续扫描其他来源，并返回合并后的错误
+func Scan(env Env, names ...string) ([]Candidate, error) {
+	selected := Sources
+	if len(names) > 0 {
+		selected = nil
+		for _, name := range names {
+			source, ok := findSource(name)
+			if !ok {
+				return nil, fmt.Errorf("未知的导入来源: %s", name)
+			}
+			selected = append(selected, source)
+		}
+	}
+
+	var candidates []Candidate
+	var errs []error
+	for _, source := range selected {
+		found, err := source.Scan(env)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("扫描%s失败: %v", source.Name, err))
+		}
+		candidates = append(candidates, found...)
+	}
+	return candidates, errors.Join(errs...)
+}
+
+// findSource 按名称查找导入来源（不区分大小写）
+func findSource(name string) (Source, bool) {
+	for _, source := range Sources {
+		if strings.EqualFold(source.Name, name) {
+			return source, true
+		}
+	}
+	return Source{}, false
+}
+
+func scanSDKMAN(env Env) ([]Candidate, error) {
+	dir := env.Getenv("SDKMAN_DIR")
+	if dir == "" {
+		dir = filepath.Join(env.Home, ".sdkman")
+	}
+	return scanDir("sdkman", filepath.Join(dir, "candidates", "java"))
+}
+
+func scanJabba(env Env) ([]Candidate, error) {
+	dir := env.Getenv("JABBA_HOME")
+	if dir == "" {
+		dir = filepath.Join(env.Home, ".jabba")
+	}
+	return scanDir("jabba", filepath.Join(dir, "jdk"))
+}
+
+func scanAsdf(env Env) ([]Candidate, error) {
+	dir := env.Getenv("ASDF_DATA_DIR")
+	if dir == "" {
+		dir = filepath.Join(env.Home, ".asdf")
+	}
+	return scanDir("asdf", filepath.Join(dir, "installs", "java"))
+}
+
+func scanMise(env Env) ([]Candidate, error) {
+	dir := env.Getenv("MISE_DATA_DIR")
+	if dir == "" {
+		switch {
+		case env.Getenv("XDG_DATA_HOME") != "":
+			dir = filepath.Join(env.Getenv("XDG_DATA_HOME"), "mise")
+		case runtime.GOOS == "windows" && env.Getenv("LOCALAPPDATA") != "":
+			dir = filepath.Join(env.Getenv("LOCALAPPDATA"), "mise")
+		default:
+			dir = filepath.Join(env.Home, ".local", "share", "mise")
+		}
+	}
+	return scanDir("mise", filepath.Join(dir, "installs", "java"))
+}
+
+func scanIntelliJ(env Env) ([]Candidate, error) {
+	return scanDir("intellij", filepath.Join(env.Home, ".jdks"))
+}
+
+// scanDir 将目录下的每个子目录作为一个候选JDK，子目录名作为标识
+// 目录不存在时不视为错误；跳过 current、latest 等别名链接
+func scanDir(source, dir string) ([]Candidate, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var candidates []Candidate
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == "current" || name == "latest" || strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			continue
+		}
+		candidates = append(candidates, Candidate{Source: source, ID: name, Path: javaHome(path)})
+	}
+	return candidates, nil
+}
+
+// javaHome macOS上的JDK目录结构为 <目录>/Contents/Home
+func javaHome(path string) string {
+	home := filepath.Join(path, "Contents", "Home")
+	if info, err := os.Stat(home); err == nil && info.IsDir() {
+		return home
+	}
+	return path
+}
+
+// Action 导入计划中的一项：Skip为空时以Key为名称添加，否则说明跳过的原因
+type Action struct {
+	Candidate
+	Key  string
+	Skip string
+}
+
+// Plan 计算导入计划：跳过无效的JDK、已配置的路径和重复的路径，
+// 以来源标识作为版本名称，与已有名称冲突时追加 -2、-3 等后缀
+func Plan(cfg *config.Config, candidates []Candidate) []Action {
+	// 已配置的路径，符号链接解析后再比较
+	seenPaths := make(map[string]string)
+	for version, path := range cfg.JDKPaths {
+		seenPaths[canonicalPath(path)] = version
+	}
+	usedKeys := make(map[string]bool)
+
+	var actions []Action
+	for _, candidate := range candidates {
+		action := Action{Candidate: candidate}
+		canonical := canonicalPath(candidate.Path)
+
+		if existing, ok := seenPaths[canonical]; ok {
+			action.Skip = fmt.Sprintf("已配置为 %s", existing)
+		} else if report := jdk.ValidateInstallation(candidate.Path); report.Kind != jdk.KindJDK {
+			action.Skip = fmt.Sprintf("不是有效的JDK (%s)", report.Kind)
+		} else {
+			action.Key = uniqueKey(candidate.ID, func(key string) bool {
+				return cfg.NameTaken(key) || usedKeys[key]
+			})
+			usedKeys[action.Key] = true
+			seenPaths[canonical] = action.Key
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+// uniqueKey 返回不与已有名称冲突的名称
+func uniqueKey(key string, exists func(string) bool) string {
+	if !exists(key) {
+		return key
+	}
+	for i := 2; ; i++ {
+		if candidate := fmt.Sprintf("%s-%d", key, i); !exists(candidate) {
+			return candidate
+		}
+	}
+}
+
+// canonicalPath 解析符号链接并规范化路径，用于判断两个路径是否指向同一个JDK
+func canonicalPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	path = filepath.Clean(path)
+	if runtime.GOOS == "windows" {
+		path = strings.ToLower(path)
+	}
+	return path
+}
+
+// SourceNames 返回所有来源名称，按字母排序
+func SourceNames() []string {
+	var names []string
+	for _, source := range Sources {
+		names = append(names, source.Name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,152 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"switch/config"
+	"switch/jdk"
+	"testing"
+)
+
+// makeJDK 在指定目录创建一个最小的JDK结构，withJavac为false时为JRE
+func makeJDK(t *testing.T, dir string, withJavac bool) {
+	t.Helper()
+	names := []string{"java"}
+	if withJavac {
+		names = append(names, "javac")
+	}
+	for _, name := range names {
+		exe := filepath.Join(dir, "bin", jdk.ExecutableName(name))
+		if err := os.MkdirAll(filepath.Dir(exe), 0755); err != nil {
+			t.Fatalf("创建bin目录失败: %v", err)
+		}
+		if err := os.WriteFile(exe, []byte("fake"), 0755); err != nil {
+			t.Fatalf("创建%s失败: %v", name, err)
+		}
+	}
+	modules := filepath.Join(dir, "lib", "modules")
+	if err := os.MkdirAll(filepath.Dir(modules), 0755); err != nil {
+		t.Fatalf("创建lib目录失败: %v", err)
+	}
+	if err := os.WriteFile(modules, []byte("fake"), 0644); err != nil {
+		t.Fatalf("创建lib/modules失败: %v", err)
+	}
+}
+
+func testEnv(home string, vars map[string]string) Env {
+	return Env{Home: home, Getenv: func(name string) string { return vars[name] }}
+}
+
+func TestScan(t *testing.T) {
+	home := t.TempDir()
+	asdfDir := filepath.Join(t.TempDir(), "asdf")
+
+	makeJDK(t, filepath.Join(home, ".sdkman", "candidates", "java", "17.0.9-tem"), true)
+	makeJDK(t, filepath.Join(home, ".jabba", "jdk", "zulu@1.11.0"), true)
+	makeJDK(t, filepath.Join(home, ".jabba", "jdk", "adopt@1.8.0", "Contents", "Home"), true)
+	makeJDK(t, filepath.Join(asdfDir, "installs", "java", "temurin-21.0.1+12"), true)
+	makeJDK(t, filepath.Join(home, ".local", "share", "mise", "installs", "java", "corretto-21"), true)
+	makeJDK(t, filepath.Join(home, ".jdks", "openjdk-22"), true)
+
+	// SDKMAN的current是指向某个版本的链接，不应作为单独的条目
+	if runtime.GOOS != "windows" {
+		current := filepath.Join(home, ".sdkman", "candidates", "java", "current")
+		if err := os.Symlink("17.0.9-tem", current); err != nil {
+			t.Fatalf("创建链接失败: %v", err)
+		}
+	}
+
+	env := testEnv(home, map[string]string{"ASDF_DATA_DIR": asdfDir})
+	candidates, err := Scan(env)
+	if err != nil {
+		t.Fatalf("扫描失败: %v", err)
+	}
+
+	want := map[string]Candidate{
+		"17.0.9-tem":        {Source: "sdkman", Path: filepath.Join(home, ".sdkman", "candidates", "java", "17.0.9-tem")},
+		"zulu@1.11.0":       {Source: "jabba", Path: filepath.Join(home, ".jabba", "jdk", "zulu@1.11.0")},
+		"adopt@1.8.0":       {Source: "jabba", Path: filepath.Join(home, ".jabba", "jdk", "adopt@1.8.0", "Contents", "Home")},
+		"temurin-21.0.1+12": {Source: "asdf", Path: filepath.Join(asdfDir, "installs", "java", "temurin-21.0.1+12")},
+		"corretto-21":       {Source: "mise", Path: filepath.Join(home, ".local", "share", "mise", "installs", "java", "corretto-21")},
+		"openjdk-22":        {Source: "intellij", Path: filepath.Join(home, ".jdks", "openjdk-22")},
+	}
+	if len(candidates) != len(want) {
+		t.Fatalf("期望找到%d个JDK，实际: %+v", len(want), candidates)
+	}
+	for _, c := range candidates {
+		w, ok := want[c.ID]
+		if !ok {
+			t.Errorf("意外的候选: %+v", c)
+			continue
+		}
+		if c.Source != w.Source || c.Path != w.Path {
+			t.Errorf("%s: 期望 %s %s，实际 %s %s", c.ID, w.Source, w.Path, c.Source, c.Path)
+		}
+	}
+
+	// 指定来源时只扫描该来源
+	candidates, err = Scan(env, "intellij")
+	if err != nil || len(candidates) != 1 || candidates[0].ID != "openjdk-22" {
+		t.Errorf("只扫描intellij时结果错误: %+v, %v", candidates, err)
+	}
+	if _, err := Scan(env, "unknown"); err == nil {
+		t.Error("未知来源应返回错误")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing")
+	clash := filepath.Join(dir, "clash")
+	jre := filepath.Join(dir, "jre")
+	makeJDK(t, existing, true)
+	makeJDK(t, clash, true)
+	makeJDK(t, jre, false)
+
+	cfg := &config.Config{
+		JDKPaths: map[string]string{"17": existing},
+		Profiles: map[string]*config.Profile{"work": {JDK: "17"}},
+	}
+	candidates := []Candidate{
+		{Source: "sdkman", ID: "17.0.9-tem", Path: existing},
+		{Source: "intellij", ID: "17", Path: clash},
+		{Source: "asdf", ID: "17", Path: clash},
+		{Source: "jabba", ID: "work", Path: existing + "-missing"},
+		{Source: "registry", ID: "1.8", Path: jre},
+	}
+	actions := Plan(cfg, candidates)
+	if len(actions) != len(candidates) {
+		t.Fatalf("期望%d项，实际%d项", len(candidates), len(actions))
+	}
+
+	// 已配置的路径
+	if actions[0].Skip == "" {
+		t.Errorf("已配置的路径应被跳过: %+v", actions[0])
+	}
+	// 名称冲突时追加后缀
+	if actions[1].Skip != "" || actions[1].Key != "17-2" {
+		t.Errorf("期望以17-2导入，实际: %+v", actions[1])
+	}
+	// 同一次导入中重复的路径
+	if actions[2].Skip == "" {
+		t.Errorf("重复的路径应被跳过: %+v", actions[2])
+	}
+	// 不存在的目录和JRE都不导入
+	if actions[3].Skip == "" || actions[4].Skip == "" {
+		t.Errorf("无效的JDK应被跳过: %+v %+v", actions[3], actions[4])
+	}
+}
+
+func TestPlanKeyAvoidsProfile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "jdk")
+	makeJDK(t, dir, true)
+	cfg := &config.Config{
+		JDKPaths: map[string]string{},
+		Profiles: map[string]*config.Profile{"work": {JDK: "17"}},
+	}
+	actions := Plan(cfg, []Candidate{{Source: "jabba", ID: "work", Path: dir}})
+	if actions[0].Key != "work-2" {
+		t.Errorf("名称不应与配置方案冲突，实际: %s", actions[0].Key)
+	}
+}
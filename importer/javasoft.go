@@ -0,0 +1,57 @@
+//go:build windows
+// +build windows
+
+package importer
+
+import (
+	"path/filepath"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// javaSoftKeys JavaSoft下记录JDK和JRE安装位置的注册表键，旧版本（JDK 8及以前）使用较长的名称
+var javaSoftKeys = []string{
+	`SOFTWARE\JavaSoft\JDK`,
+	`SOFTWARE\JavaSoft\JRE`,
+	`SOFTWARE\JavaSoft\Java Development Kit`,
+	`SOFTWARE\JavaSoft\Java Runtime Environment`,
+}
+
+// scanRegistry 读取HKLM\SOFTWARE\JavaSoft下每个版本子键的JavaHome，同时查看64位和32位注册表视图
+func scanRegistry(env Env) ([]Candidate, error) {
+	var candidates []Candidate
+	seen := make(map[string]bool)
+	for _, view := range []uint32{registry.WOW64_64KEY, registry.WOW64_32KEY} {
+		for _, keyPath := range javaSoftKeys {
+			key, err := registry.OpenKey(registry.LOCAL_MACHINE, keyPath, registry.ENUMERATE_SUB_KEYS|registry.QUERY_VALUE|view)
+			if err != nil {
+				continue
+			}
+			versions, err := key.ReadSubKeyNames(-1)
+			key.Close()
+			if err != nil {
+				return candidates, err
+			}
+
+			for _, version := range versions {
+				sub, err := registry.OpenKey(registry.LOCAL_MACHINE, keyPath+`\`+version, registry.QUERY_VALUE|view)
+				if err != nil {
+					continue
+				}
+				home, _, err := sub.GetStringValue("JavaHome")
+				sub.Close()
+				if err != nil || home == "" {
+					continue
+				}
+				// 同一个JDK通常同时登记在 1.8 和 1.8.0_301 等多个子键下
+				home = filepath.Clean(home)
+				if seen[home] {
+					continue
+				}
+				seen[home] = true
+				candidates = append(candidates, Candidate{Source: "registry", ID: version, Path: home})
+			}
+		}
+	}
+	return candidates, nil
+}
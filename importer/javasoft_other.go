@@ -0,0 +1,9 @@
+//go:build !windows
+// +build !windows
+
+package importer
+
+// scanRegistry 非Windows平台没有JavaSoft注册表键
+func scanRegistry(env Env) ([]Candidate, error) {
+	return nil, nil
+}
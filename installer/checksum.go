@@ -0,0 +1,92 @@
+package installer
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// checksumSuffixes 自动查找的校验和文件扩展名
+var checksumSuffixes = []string{".sha256", ".sha256.txt", ".sha512", ".sha512.txt"}
+
+// FindChecksumFile 查找与压缩包同名的校验和文件，不存在时返回空字符串
+func FindChecksumFile(archive string) string {
+	for _, suffix := range checksumSuffixes {
+		if info, err := os.Stat(archive + suffix); err == nil && !info.IsDir() {
+			return archive + suffix
+		}
+	}
+	return ""
+}
+
+// VerifyChecksum 使用校验和文件验证压缩包，根据校验值长度判断是SHA-256还是SHA-512
+// 支持 sha256sum 输出格式（"校验值  文件名"，可以包含多个文件）和只有校验值的文件
+func VerifyChecksum(archive, checksumFile string) (string, error) {
+	expected, err := readChecksum(checksumFile, filepath.Base(archive))
+	if err != nil {
+		return "", err
+	}
+
+	var algorithm string
+	var h hash.Hash
+	switch len(expected) {
+	case sha256.Size * 2:
+		algorithm, h = "SHA-256", sha256.New()
+	case sha512.Size * 2:
+		algorithm, h = "SHA-512", sha512.New()
+	default:
+		return "", fmt.Errorf("无法识别的校验值: %s", expected)
+	}
+
+	f, err := os.Open(archive)
+	if err != nil {
+		return "", fmt.Errorf("打开压缩包失败: %v", err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("读取压缩包失败: %v", err)
+	}
+
+	if actual := hex.EncodeToString(h.Sum(nil)); actual != expected {
+		return "", fmt.Errorf("%s校验失败: 期望 %s，实际 %s", algorithm, expected, actual)
+	}
+	return algorithm, nil
+}
+
+// readChecksum 从校验和文件中读取指定文件的校验值
+func readChecksum(checksumFile, name string) (string, error) {
+	f, err := os.Open(checksumFile)
+	if err != nil {
+		return "", fmt.Errorf("打开校验和文件失败: %v", err)
+	}
+	defer f.Close()
+
+	var lines [][]string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			lines = append(lines, fields)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("读取校验和文件失败: %v", err)
+	}
+
+	for _, fields := range lines {
+		// 只有一行且不带文件名时直接使用
+		if len(lines) == 1 && len(fields) == 1 {
+			return strings.ToLower(fields[0]), nil
+		}
+		if len(fields) >= 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("校验和文件 %s 中没有 %s 的校验值", checksumFile, name)
+}
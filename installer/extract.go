@@ -0,0 +1,267 @@
+package installer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// archiveFormat 根据文件扩展名判断压缩包格式
+func archiveFormat(name string) (string, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return "zip", nil
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tar.gz", nil
+	}
+	return "", fmt.Errorf("不支持的压缩包格式: %s（支持 .zip、.tar.gz、.tgz）", filepath.Base(name))
+}
+
+// archiveBaseName 去掉压缩包扩展名后的文件名
+func archiveBaseName(name string) string {
+	base := filepath.Base(name)
+	lower := strings.ToLower(base)
+	for _, ext := range []string{".tar.gz", ".tgz", ".zip"} {
+		if strings.HasSuffix(lower, ext) {
+			return base[:len(base)-len(ext)]
+		}
+	}
+	return base
+}
+
+// extract 将压缩包解压到dest目录
+func extract(archive, dest string) error {
+	format, err := archiveFormat(archive)
+	if err != nil {
+		return err
+	}
+	if format == "zip" {
+		return extractZip(archive, dest)
+	}
+	return extractTarGz(archive, dest)
+}
+
+func extractZip(archive, dest string) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return fmt.Errorf("打开zip文件失败: %v", err)
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		target, err := safeJoin(dest, f.Name)
+		if err != nil {
+			return err
+		}
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			err = makeDir(dest, target)
+		case mode&os.ModeSymlink != 0:
+			var link []byte
+			link, err = readZipFile(f)
+			if err == nil {
+				err = makeSymlink(dest, target, string(link))
+			}
+		default:
+			var rc io.ReadCloser
+			rc, err = f.Open()
+			if err == nil {
+				err = writeFile(dest, target, rc, mode.Perm())
+				rc.Close()
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("解压 %s 失败: %v", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func extractTarGz(archive, dest string) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("打开压缩包失败: %v", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("读取gzip数据失败: %v", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取tar数据失败: %v", err)
+		}
+
+		target, err := safeJoin(dest, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = makeDir(dest, target)
+		case tar.TypeReg, tar.TypeRegA:
+			err = writeFile(dest, target, tr, header.FileInfo().Mode().Perm())
+		case tar.TypeSymlink:
+			err = makeSymlink(dest, target, header.Linkname)
+		case tar.TypeLink:
+			err = makeHardLink(dest, target, header.Linkname)
+		default:
+			// 设备文件、FIFO以及pax扩展头等与JDK无关的条目直接忽略
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("解压 %s 失败: %v", header.Name, err)
+		}
+	}
+}
+
+// safeJoin 将压缩包中的条目名称转换为dest下的路径，拒绝绝对路径和跳出dest的路径
+func safeJoin(dest, name string) (string, error) {
+	name = strings.ReplaceAll(name, "\\", "/")
+	if path.IsAbs(name) || filepath.IsAbs(name) || filepath.VolumeName(name) != "" || len(name) >= 2 && name[1] == ':' {
+		return "", fmt.Errorf("压缩包包含绝对路径: %s", name)
+	}
+	cleaned := path.Clean(name)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("压缩包包含越界路径: %s", name)
+	}
+	return filepath.Join(dest, filepath.FromSlash(cleaned)), nil
+}
+
+// checkParents 确认target的各级上级目录都不是符号链接，防止通过之前解压的链接写到dest之外
+func checkParents(dest, target string) error {
+	rel, err := filepath.Rel(dest, filepath.Dir(target))
+	if err != nil || rel == "." {
+		return err
+	}
+	current := dest
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("路径经过符号链接: %s", current)
+		}
+	}
+	return nil
+}
+
+func makeDir(dest, target string) error {
+	if err := checkParents(dest, target); err != nil {
+		return err
+	}
+	return os.MkdirAll(target, 0755)
+}
+
+func writeFile(dest, target string, r io.Reader, perm os.FileMode) error {
+	if err := checkParents(dest, target); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	// 不能通过已存在的符号链接写入
+	if info, err := os.Lstat(target); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		if err := os.Remove(target); err != nil {
+			return err
+		}
+	}
+	// 保证所有者可写，卸载时才能删除
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm|0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// makeSymlink 创建符号链接，只允许指向dest之内的相对路径
+func makeSymlink(dest, target, link string) error {
+	link = strings.ReplaceAll(link, "\\", "/")
+	if path.IsAbs(link) || filepath.IsAbs(link) || filepath.VolumeName(link) != "" {
+		return fmt.Errorf("符号链接指向绝对路径: %s", link)
+	}
+	rel, err := filepath.Rel(dest, target)
+	if err != nil {
+		return err
+	}
+	resolved := path.Clean(path.Join(path.Dir(filepath.ToSlash(rel)), link))
+	if resolved == ".." || strings.HasPrefix(resolved, "../") {
+		return fmt.Errorf("符号链接指向解压目录之外: %s", link)
+	}
+
+	if err := checkParents(dest, target); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	return os.Symlink(filepath.FromSlash(link), target)
+}
+
+// makeHardLink 创建硬链接，链接目标是压缩包中之前解压的文件；不支持硬链接时复制文件
+func makeHardLink(dest, target, link string) error {
+	source, err := safeJoin(dest, link)
+	if err != nil {
+		return err
+	}
+	if err := checkParents(dest, source); err != nil {
+		return err
+	}
+	if err := checkParents(dest, target); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	if err := os.Link(source, target); err == nil {
+		return nil
+	}
+
+	info, err := os.Lstat(source)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("硬链接目标不是普通文件: %s", link)
+	}
+	f, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return writeFile(dest, target, f, info.Mode().Perm())
+}
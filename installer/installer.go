@@ -0,0 +1,153 @@
+// Package installer 将本地的JDK压缩包解压到配置目录下的jdks目录，并管理这些安装
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"switch/config"
+	"switch/jdk"
+)
+
+// Dir 返回托管JDK的安装目录，位于配置文件所在目录下
+func Dir() string {
+	return filepath.Join(config.Dir(), "jdks")
+}
+
+// Result 安装结果
+type Result struct {
+	// Home JDK主目录，macOS的压缩包中为 <安装目录>/Contents/Home
+	Home string
+	// Root 安装目录，卸载时删除整个目录
+	Root string
+	// Checksum 验证使用的算法，没有校验和文件时为空
+	Checksum string
+}
+
+// Install 验证并解压JDK压缩包，checksumFile为空时自动查找同名的 .sha256、.sha512 文件
+// 压缩包只有一个顶层目录时以该目录名作为安装目录名，否则使用压缩包文件名
+func Install(archive, checksumFile string) (*Result, error) {
+	if _, err := archiveFormat(archive); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(archive); err != nil {
+		return nil, fmt.Errorf("无法访问压缩包: %v", err)
+	}
+
+	result := &Result{}
+	if checksumFile == "" {
+		checksumFile = FindChecksumFile(archive)
+	}
+	if checksumFile != "" {
+		algorithm, err := VerifyChecksum(archive, checksumFile)
+		if err != nil {
+			return nil, err
+		}
+		result.Checksum = algorithm
+	}
+
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
+		return nil, fmt.Errorf("创建安装目录失败: %v", err)
+	}
+	// 先解压到临时目录，检查通过后再移动到最终位置，失败时不会留下不完整的安装
+	tmp, err := os.MkdirTemp(Dir(), ".install-")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时目录失败: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := extract(archive, tmp); err != nil {
+		return nil, err
+	}
+
+	top, name, err := topLevelDir(tmp)
+	if err != nil {
+		return nil, err
+	}
+	if top == "" {
+		top, name = tmp, archiveBaseName(archive)
+	}
+
+	if report := jdk.ValidateInstallation(javaHome(top)); report.Kind != jdk.KindJDK {
+		return nil, fmt.Errorf("压缩包中不是有效的JDK (%s): %s", report.Kind, strings.Join(report.Problems(), "; "))
+	}
+
+	result.Root = filepath.Join(Dir(), name)
+	if _, err := os.Lstat(result.Root); err == nil {
+		return nil, fmt.Errorf("%s 已经安装: %s", name, result.Root)
+	}
+	if err := os.Rename(top, result.Root); err != nil {
+		return nil, fmt.Errorf("移动到安装目录失败: %v", err)
+	}
+	result.Home = javaHome(result.Root)
+	return result, nil
+}
+
+// topLevelDir 解压目录中只有一个顶层目录时返回该目录和目录名，否则返回空字符串
+// macOS打包时产生的 __MACOSX 目录会被忽略，直接以 Contents 开头的压缩包视为没有顶层目录
+func topLevelDir(dir string) (string, string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", "", fmt.Errorf("读取解压目录失败: %v", err)
+	}
+	var dirs []os.DirEntry
+	for _, entry := range entries {
+		if entry.Name() == "__MACOSX" {
+			continue
+		}
+		if !entry.IsDir() {
+			return "", "", nil
+		}
+		dirs = append(dirs, entry)
+	}
+	if len(dirs) != 1 || dirs[0].Name() == "Contents" {
+		return "", "", nil
+	}
+	return filepath.Join(dir, dirs[0].Name()), dirs[0].Name(), nil
+}
+
+// javaHome macOS上的JDK目录结构为 <目录>/Contents/Home
+func javaHome(path string) string {
+	home := filepath.Join(path, "Contents", "Home")
+	if info, err := os.Stat(home); err == nil && info.IsDir() {
+		return home
+	}
+	return path
+}
+
+// IsManaged 判断路径是否位于托管安装目录之内
+func IsManaged(path string) bool {
+	_, err := InstallRoot(path)
+	return err == nil
+}
+
+// InstallRoot 返回路径所属的安装目录，即jdks目录下的第一级子目录
+func InstallRoot(path string) (string, error) {
+	dir, err := filepath.Abs(Dir())
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(dir, abs)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s 不是通过 -install 安装的JDK", path)
+	}
+	first := strings.Split(rel, string(filepath.Separator))[0]
+	return filepath.Join(dir, first), nil
+}
+
+// Remove 删除托管安装的JDK目录，不在jdks目录下的路径会被拒绝
+func Remove(path string) error {
+	root, err := InstallRoot(path)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(root); err != nil {
+		return fmt.Errorf("删除安装目录失败: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package installer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"switch/config"
+	"switch/jdk"
+	"testing"
+)
+
+// entry 测试压缩包中的一个条目，link不为空时为符号链接
+type entry struct {
+	name string
+	body string
+	link string
+}
+
+// jdkEntries 返回一个最小JDK的条目，位于prefix目录下
+func jdkEntries(prefix string) []entry {
+	return []entry{
+		{name: prefix + "bin/" + jdk.ExecutableName("java"), body: "fake"},
+		{name: prefix + "bin/" + jdk.ExecutableName("javac"), body: "fake"},
+		{name: prefix + "lib/modules", body: "fake"},
+		{name: prefix + "release", body: "JAVA_VERSION=\"17.0.9\"\nIMPLEMENTOR=\"Eclipse Adoptium\"\n"},
+	}
+}
+
+func writeZip(t *testing.T, path string, entries []entry) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("创建zip失败: %v", err)
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		header.SetMode(0755)
+		body := e.body
+		if e.link != "" {
+			header.SetMode(os.ModeSymlink | 0777)
+			body = e.link
+		}
+		fw, err := w.CreateHeader(header)
+		if err != nil {
+			t.Fatalf("写入zip失败: %v", err)
+		}
+		fw.Write([]byte(body))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("写入zip失败: %v", err)
+	}
+}
+
+func writeTarGz(t *testing.T, path string, entries []entry) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("创建tar.gz失败: %v", err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: 0755, Size: int64(len(e.body)), Typeflag: tar.TypeReg}
+		if e.link != "" {
+			header.Typeflag, header.Linkname, header.Size = tar.TypeSymlink, e.link, 0
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatalf("写入tar失败: %v", err)
+		}
+		tw.Write([]byte(e.body))
+	}
+	tw.Close()
+	gz.Close()
+}
+
+func TestInstallAndRemove(t *testing.T) {
+	t.Setenv(config.HomeEnv, t.TempDir())
+	src := t.TempDir()
+
+	// 带顶层目录的tar.gz，使用同名的.sha256文件校验
+	archive := filepath.Join(src, "OpenJDK17U-jdk_x64_linux.tar.gz")
+	writeTarGz(t, archive, jdkEntries("jdk-17.0.9+9/"))
+	data, _ := os.ReadFile(archive)
+	sum := sha256.Sum256(data)
+	os.WriteFile(archive+".sha256", []byte(hex.EncodeToString(sum[:])+"  OpenJDK17U-jdk_x64_linux.tar.gz\n"), 0644)
+
+	result, err := Install(archive, "")
+	if err != nil {
+		t.Fatalf("安装失败: %v", err)
+	}
+	if result.Checksum != "SHA-256" {
+		t.Errorf("期望使用SHA-256校验，实际: %q", result.Checksum)
+	}
+	if want := filepath.Join(Dir(), "jdk-17.0.9+9"); result.Home != want || result.Root != want {
+		t.Errorf("期望安装到 %s，实际 %+v", want, result)
+	}
+	if !jdk.ValidateJDKPath(result.Home) || !IsManaged(result.Home) {
+		t.Errorf("安装结果无效: %+v", result)
+	}
+
+	// 重复安装
+	if _, err := Install(archive, ""); err == nil {
+		t.Error("重复安装应返回错误")
+	}
+
+	// 没有顶层目录的zip，以文件名作为目录名；macOS结构使用Contents/Home
+	flat := filepath.Join(src, "zulu17.zip")
+	writeZip(t, flat, jdkEntries("Contents/Home/"))
+	result, err = Install(flat, "")
+	if err != nil {
+		t.Fatalf("安装zip失败: %v", err)
+	}
+	if want := filepath.Join(Dir(), "zulu17"); result.Root != want || result.Home != filepath.Join(want, "Contents", "Home") {
+		t.Errorf("zip安装位置错误: %+v", result)
+	}
+
+	if err := Remove(result.Home); err != nil {
+		t.Fatalf("删除失败: %v", err)
+	}
+	if _, err := os.Stat(result.Root); !os.IsNotExist(err) {
+		t.Error("删除后安装目录仍然存在")
+	}
+	if err := Remove(src); err == nil {
+		t.Error("不应删除托管目录之外的路径")
+	}
+	if IsManaged(Dir()) {
+		t.Error("jdks目录本身不是一个安装")
+	}
+
+	// 临时目录应被清理
+	entries, _ := os.ReadDir(Dir())
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".install-") {
+			t.Errorf("残留临时目录: %s", e.Name())
+		}
+	}
+}
+
+func TestInstallRejects(t *testing.T) {
+	t.Setenv(config.HomeEnv, t.TempDir())
+	src := t.TempDir()
+
+	tests := []struct {
+		name    string
+		entries []entry
+	}{
+		{"traversal", append(jdkEntries("jdk/"), entry{name: "../evil", body: "x"})},
+		{"nested-traversal", append(jdkEntries("jdk/"), entry{name: "jdk/../../evil", body: "x"})},
+		{"absolute", append(jdkEntries("jdk/"), entry{name: "/tmp/evil", body: "x"})},
+		{"symlink-escape", append(jdkEntries("jdk/"), entry{name: "jdk/out", link: "../../outside"})},
+		{"symlink-absolute", append(jdkEntries("jdk/"), entry{name: "jdk/out", link: "/etc"})},
+		{"not-jdk", []entry{{name: "jdk/readme.txt", body: "x"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := filepath.Join(src, tt.name+".tar.gz")
+			writeTarGz(t, archive, tt.entries)
+			if _, err := Install(archive, ""); err == nil {
+				t.Error("期望安装失败")
+			}
+			if _, err := os.Stat(filepath.Join(filepath.Dir(Dir()), "evil")); err == nil {
+				t.Error("文件被写到了安装目录之外")
+			}
+		})
+	}
+
+	// 校验值不匹配
+	archive := filepath.Join(src, "jdk.zip")
+	writeZip(t, archive, jdkEntries("jdk/"))
+	checksum := filepath.Join(src, "sums.txt")
+	os.WriteFile(checksum, []byte(strings.Repeat("0", 64)+"  jdk.zip\n"), 0644)
+	if _, err := Install(archive, checksum); err == nil || !strings.Contains(err.Error(), "校验失败") {
+		t.Errorf("期望校验失败，实际: %v", err)
+	}
+
+	if _, err := Install(filepath.Join(src, "jdk.rar"), ""); err == nil {
+		t.Error("不支持的格式应返回错误")
+	}
+}
+
+func TestSymlinkParent(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "dest")
+	os.Mkdir(dest, 0755)
+	// 指向解压目录内部的链接允许创建，但之后不能经过它写入文件或创建链接
+	if err := makeSymlink(dest, filepath.Join(dest, "self"), "."); err != nil {
+		t.Fatalf("创建链接失败: %v", err)
+	}
+	target, _ := safeJoin(dest, "self/file")
+	if err := writeFile(dest, target, strings.NewReader("x"), 0644); err == nil {
+		t.Error("不应经过符号链接写入文件")
+	}
+	// self/up 按字面解析仍在dest之内，实际却指向dest的上级目录
+	if err := makeSymlink(dest, filepath.Join(dest, "self", "up"), ".."); err == nil {
+		t.Error("不应经过符号链接创建链接")
+	}
+}
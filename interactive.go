@@ -0,0 +1,314 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"switch/config"
+	"switch/jdk"
+	"switch/term"
+	"text/tabwriter"
+)
+
+// shellCommands 交互模式支持的命令，用于Tab补全
+var shellCommands = []string{"list", "use", "backup", "add", "remove", "history", "undo", "help", "quit"}
+
+// shell 交互模式的状态
+type shell struct {
+	cfg    *config.Config
+	opts   switchOptions
+	editor *term.Editor
+}
+
+// runInteractive 运行交互模式，直到输入quit、EOF或按下Ctrl-C
+func runInteractive(cfg *config.Config, opts switchOptions) {
+	s := &shell{cfg: cfg, opts: opts, editor: term.NewEditor(os.Stdin, os.Stdout)}
+	s.editor.Complete = s.complete
+
+	fmt.Println("JDK Switch Tool v" + version)
+	s.list()
+	fmt.Println("\n输入编号或版本切换JDK，输入 help 查看所有命令")
+
+	for {
+		fmt.Println()
+		line, err := s.editor.ReadLine("jdk-switch> ")
+		if err != nil {
+			if err != io.EOF && err != term.ErrInterrupted {
+				fmt.Printf("读取输入失败: %v\n", err)
+			}
+			return
+		}
+		if quit := s.execute(line); quit {
+			return
+		}
+	}
+}
+
+// execute 执行一行输入，返回true表示退出交互模式
+func (s *shell) execute(line string) bool {
+	args, err := splitArgs(line)
+	if err != nil {
+		fmt.Printf("错误: %v\n", err)
+		return false
+	}
+	if len(args) == 0 {
+		return false
+	}
+
+	cmd := strings.ToLower(args[0])
+	switch cmd {
+	case "q", "quit", "exit":
+		return true
+	case "h", "help", "?":
+		s.help()
+	case "l", "ls", "list":
+		s.list()
+	case "b", "backup":
+		if _, err := backupEnvironment(); err != nil {
+			fmt.Printf("备份环境变量失败: %v\n", err)
+		}
+	case "history":
+		version := ""
+		if len(args) > 1 {
+			version = args[1]
+		}
+		if err := showHistory(version, 20); err != nil {
+			fmt.Printf("读取历史记录失败: %v\n", err)
+		}
+	case "undo":
+		if err := undoLastSwitch(); err != nil {
+			fmt.Printf("撤销失败: %v\n", err)
+			return false
+		}
+		s.reload()
+	case "use", "set":
+		if len(args) != 2 {
+			fmt.Println("用法: use <编号|版本>")
+			return false
+		}
+		s.use(args[1])
+	case "add":
+		if len(args) < 2 || len(args) > 3 {
+			fmt.Println("用法: add <JDK路径> [名称]")
+			return false
+		}
+		name := ""
+		if len(args) == 3 {
+			name = args[2]
+		}
+		if err := addJDK(args[1], name); err != nil {
+			fmt.Printf("添加JDK失败: %v\n", err)
+			return false
+		}
+		s.reload()
+	case "rm", "remove":
+		force := len(args) == 3 && (args[2] == "-f" || args[2] == "force")
+		if len(args) < 2 || len(args) > 3 || len(args) == 3 && !force {
+			fmt.Println("用法: remove <编号|版本> [-f]")
+			return false
+		}
+		key, err := s.selectJDK(args[1])
+		if err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return false
+		}
+		if err := removeJDK(key, force); err != nil {
+			fmt.Printf("删除JDK失败: %v\n", err)
+			return false
+		}
+		s.reload()
+	default:
+		// 直接输入编号或版本时切换
+		if len(args) != 1 {
+			fmt.Printf("未知命令: %s，输入 help 查看所有命令\n", args[0])
+			return false
+		}
+		s.use(args[0])
+	}
+	return false
+}
+
+// use 切换到编号或版本对应的JDK，名称为配置方案时切换到该方案
+func (s *shell) use(query string) {
+	if profile := s.cfg.Profiles[query]; profile != nil {
+		opts := s.opts
+		opts.profile = query
+		if err := switchJDK(s.cfg, profile.JDK, opts); err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		fmt.Printf("成功切换到配置方案 %s (JDK %s)\n", query, profile.JDK)
+		return
+	}
+
+	key, err := s.selectJDK(query)
+	if err != nil {
+		fmt.Printf("错误: %v\n", err)
+		return
+	}
+	if err := switchJDK(s.cfg, key, s.opts); err != nil {
+		fmt.Printf("错误: %v\n", err)
+		return
+	}
+	fmt.Printf("成功切换到JDK %s\n", key)
+}
+
+// reload 在添加或删除条目后重新加载配置
+func (s *shell) reload() {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Printf("重新加载配置失败: %v\n", err)
+		return
+	}
+	s.cfg = cfg
+}
+
+// list 列出带编号的JDK，按主版本号排序
+func (s *shell) list() {
+	fmt.Printf("当前JDK版本: %s (系统架构: %s)\n", currentLabel(s.cfg), jdk.HostArch())
+	printLinkStatus(s.cfg)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for i, install := range jdk.Installations(s.cfg.JDKPaths) {
+		marker := " "
+		if install.Key == s.cfg.CurrentVersion {
+			marker = "*"
+		}
+		arch := "?"
+		if info, err := jdk.DetectArch(install.Path); err == nil {
+			arch = info.Arch
+		}
+		vendor := install.Vendor()
+		if vendor == "" {
+			vendor = "-"
+		}
+		kind := jdk.ValidateInstallation(install.Path).Kind
+		fmt.Fprintf(w, "%s %d)\t%s\t%s\t%s\t%s\t%s\t%s\n", marker, i+1, install.Key, install.Version(), vendor, arch, kind, install.Path)
+	}
+	w.Flush()
+	listProfiles(s.cfg)
+}
+
+// help 显示交互模式的命令说明
+func (s *shell) help() {
+	fmt.Println("可用命令:")
+	fmt.Println("  <编号|版本>             切换到指定的JDK，版本可以是名称、完整版本号、主版本号或名称的一部分")
+	fmt.Println("  use <编号|版本|配置方案> 同上，也可以切换到配置方案")
+	fmt.Println("  list                    列出所有JDK")
+	fmt.Println("  backup                  备份环境变量")
+	fmt.Println("  add <JDK路径> [名称]    添加JDK，路径包含空格时请使用双引号")
+	fmt.Println("  remove <编号|版本> [-f] 删除JDK条目，删除当前版本需要 -f")
+	fmt.Println("  history [版本]          查看最近的切换和备份记录")
+	fmt.Println("  undo                    撤销最近一次切换")
+	fmt.Println("  help                    显示本帮助")
+	fmt.Println("  quit                    退出（也可以按Ctrl-D或Ctrl-C）")
+	fmt.Println("上下方向键浏览历史命令，Tab键补全命令和版本")
+	fmt.Println("名称与编号相同时优先按名称选择")
+}
+
+// selectJDK 按编号或版本选择JDK，返回配置中的版本名称
+// 依次尝试：完整名称、列表编号、完整版本号或主版本号、名称或版本号的一部分
+func (s *shell) selectJDK(query string) (string, error) {
+	if _, exists := s.cfg.JDKPaths[query]; exists {
+		return query, nil
+	}
+
+	installs := jdk.Installations(s.cfg.JDKPaths)
+	if n, err := strconv.Atoi(query); err == nil && n >= 1 && n <= len(installs) {
+		return installs[n-1].Key, nil
+	}
+
+	matches := jdk.MatchInstallations(installs, query, "")
+	if len(matches) == 0 {
+		lower := strings.ToLower(query)
+		for _, install := range installs {
+			if strings.Contains(strings.ToLower(install.Key), lower) || strings.HasPrefix(install.Version(), query) {
+				matches = append(matches, install)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("JDK版本 %s 不存在", query)
+	case 1:
+		return matches[0].Key, nil
+	}
+	var keys []string
+	for _, match := range matches {
+		keys = append(keys, match.Key)
+	}
+	return "", fmt.Errorf("%s 匹配到多个JDK: %s", query, strings.Join(keys, ", "))
+}
+
+// complete 补全命令名称，以及use、remove之后的版本名称
+func (s *shell) complete(line string) []string {
+	head, word := "", line
+	if i := strings.LastIndex(line, " "); i >= 0 {
+		head, word = line[:i+1], line[i+1:]
+	}
+
+	var words []string
+	fields := strings.Fields(head)
+	switch {
+	case len(fields) == 0:
+		words = append(words, shellCommands...)
+		words = append(words, s.targetNames()...)
+	case len(fields) == 1 && fields[0] != "add":
+		words = append(words, s.targetNames()...)
+	}
+
+	var candidates []string
+	for _, w := range words {
+		if strings.HasPrefix(w, word) {
+			candidates = append(candidates, head+w)
+		}
+	}
+	return candidates
+}
+
+// targetNames 返回可以切换的JDK版本名称和配置方案名称
+func (s *shell) targetNames() []string {
+	var names []string
+	for _, install := range jdk.Installations(s.cfg.JDKPaths) {
+		names = append(names, install.Key)
+	}
+	var profiles []string
+	for name := range s.cfg.Profiles {
+		profiles = append(profiles, name)
+	}
+	sort.Strings(profiles)
+	return append(names, profiles...)
+}
+
+// splitArgs 按空白拆分输入，双引号内的空白不拆分
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inQuote, hasArg := false, false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			hasArg = true
+		case (r == ' ' || r == '\t') && !inQuote:
+			if hasArg {
+				args = append(args, current.String())
+				current.Reset()
+				hasArg = false
+			}
+		default:
+			current.WriteRune(r)
+			hasArg = true
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("缺少结束的双引号")
+	}
+	if hasArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
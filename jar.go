@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"switch/config"
+	"switch/jarinfo"
+	"switch/jdk"
+	"switch/shim"
+)
+
+// chooseJarJDK 分析jar文件并选出满足要求的最低版本JDK
+func chooseJarJDK(cfg *config.Config, path string) (*jarinfo.Info, jdk.Installation, error) {
+	info, err := jarinfo.Analyze(path)
+	if err != nil {
+		return nil, jdk.Installation{}, err
+	}
+	install, ok := jdk.LowestSatisfying(jdk.Installations(cfg.JDKPaths), info.RequiredJava())
+	if !ok {
+		return info, jdk.Installation{}, fmt.Errorf("没有满足要求的JDK: %s 需要Java %d或更高版本", filepath.Base(path), info.RequiredJava())
+	}
+	return info, install, nil
+}
+
+// runJar 使用满足要求的最低版本JDK运行jar文件，只为子进程设置JAVA_HOME和PATH，返回退出码
+// 提示信息输出到标准错误，不影响jar本身的标准输出
+func runJar(path string, args []string) int {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jdk-switch: 加载配置失败: %v\n", err)
+		return 1
+	}
+	info, install, err := chooseJarJDK(cfg, path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jdk-switch: %v\n", err)
+		return 1
+	}
+	if info.MainClass == "" {
+		fmt.Fprintf(os.Stderr, "jdk-switch: %s 的清单中没有Main-Class，无法使用 java -jar 运行\n", path)
+		return 1
+	}
+	for _, warning := range info.Warnings {
+		fmt.Fprintf(os.Stderr, "jdk-switch: 警告: %s\n", warning)
+	}
+	fmt.Fprintf(os.Stderr, "jdk-switch: 使用 %s (需要Java %d)\n", install.Key, info.RequiredJava())
+
+	env, err := shim.Environment(cfg, &shim.Resolution{Version: install.Key, Path: install.Path})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jdk-switch: %v\n", err)
+		return 1
+	}
+	java := filepath.Join(install.Path, "bin", jdk.ExecutableName("java"))
+	code, err := shim.Exec(java, append([]string{"-jar", path}, args...), env)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jdk-switch: 运行 %s 失败: %v\n", java, err)
+		return 126
+	}
+	return code
+}
+
+// inspectJar 显示jar文件的清单信息、class文件版本和将会使用的JDK
+func inspectJar(cfg *config.Config, path string) error {
+	info, install, chooseErr := chooseJarJDK(cfg, path)
+	if info == nil {
+		return chooseErr
+	}
+
+	fmt.Printf("%s\n", path)
+	if info.MainClass != "" {
+		fmt.Printf("  Main-Class: %s", info.MainClass)
+		if info.MainClassMajor > 0 {
+			fmt.Printf(" (class版本 %d, Java %d)", info.MainClassMajor, jarinfo.JavaVersion(info.MainClassMajor))
+		}
+		fmt.Println()
+	} else {
+		fmt.Println("  Main-Class: 无")
+	}
+	if info.MultiRelease {
+		var versions []string
+		for _, v := range info.ReleaseVersions {
+			versions = append(versions, fmt.Sprint(v))
+		}
+		fmt.Printf("  Multi-Release: 是 (META-INF/versions: %s)\n", strings.Join(versions, ", "))
+	}
+	if info.BuildJdkSpec != "" {
+		fmt.Printf("  Build-Jdk-Spec: %s\n", info.BuildJdkSpec)
+	}
+	if createdBy := info.Manifest["Created-By"]; createdBy != "" {
+		fmt.Printf("  Created-By: %s\n", createdBy)
+	}
+	if info.ClassCount > 0 {
+		fmt.Printf("  class文件: %d 个，最高版本 %d (Java %d): %s\n", info.ClassCount, info.MaxMajor, jarinfo.JavaVersion(info.MaxMajor), info.MaxEntry)
+	} else {
+		fmt.Println("  class文件: 无")
+	}
+	if info.ModuleInfoMajor > 0 {
+		fmt.Printf("  module-info.class: Java %d\n", jarinfo.JavaVersion(info.ModuleInfoMajor))
+	}
+	for _, warning := range info.Warnings {
+		fmt.Printf("  警告: %s\n", warning)
+	}
+
+	required := info.RequiredJava()
+	if required == 0 {
+		fmt.Println("  最低Java版本: 无法判断")
+	} else {
+		fmt.Printf("  最低Java版本: %d\n", required)
+	}
+	if chooseErr != nil {
+		fmt.Println("  将使用: 没有满足要求的JDK")
+		return nil
+	}
+	fmt.Printf("  将使用: %s (%s)\n", install.Key, install.Path)
+	return nil
+}
@@ -0,0 +1,179 @@
+// Package jarinfo 读取jar文件的清单和class文件版本，推断运行它所需的最低Java版本
+package jarinfo
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// versionsPrefix 多版本jar中针对特定Java版本的class所在目录
+const versionsPrefix = "META-INF/versions/"
+
+// Info jar文件的分析结果
+type Info struct {
+	Path string
+	// Manifest META-INF/MANIFEST.MF 中的主属性
+	Manifest     map[string]string
+	MainClass    string
+	MultiRelease bool
+	BuildJdkSpec string
+	// MainClassMajor Main-Class对应class文件的版本号，找不到时为0
+	MainClassMajor int
+	// ClassCount 参与计算的class文件数量（不含多版本目录和module-info.class）
+	ClassCount int
+	// MaxMajor 参与计算的class文件中最高的版本号，MaxEntry为对应的文件
+	MaxMajor int
+	MaxEntry string
+	// ReleaseVersions 多版本jar中 META-INF/versions 下的Java版本
+	ReleaseVersions []int
+	// ModuleInfoMajor 根目录下module-info.class的版本号，Java 8会忽略它，不参与计算
+	ModuleInfoMajor int
+	// Warnings 无法读取而被跳过的class文件等
+	Warnings []string
+}
+
+// JavaVersion 将class文件版本号转换为Java版本（52为Java 8，45为Java 1.1）
+func JavaVersion(major int) int {
+	if major < 45 {
+		return 0
+	}
+	return major - 44
+}
+
+// RequiredJava 运行该jar所需的最低Java版本，无法判断时为0
+// 以普通class文件的最高版本为准；没有class文件时使用Build-Jdk-Spec
+func (i *Info) RequiredJava() int {
+	if i.MaxMajor > 0 {
+		return JavaVersion(i.MaxMajor)
+	}
+	if spec, err := strconv.Atoi(strings.TrimPrefix(i.BuildJdkSpec, "1.")); err == nil {
+		return spec
+	}
+	return 0
+}
+
+// Analyze 读取jar文件的清单和所有class文件的版本
+// 无法读取或不是有效class文件的条目会被跳过并记录到Warnings中，不影响其他条目的分析
+// 只分析jar本身包含的class，Spring Boot等jar中嵌套的 BOOT-INF/lib/*.jar 等依赖jar不会被展开读取
+func Analyze(path string) (*Info, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开jar文件失败: %v", err)
+	}
+	defer r.Close()
+
+	info := &Info{Path: path, Manifest: map[string]string{}}
+	for _, f := range r.File {
+		if strings.EqualFold(f.Name, "META-INF/MANIFEST.MF") {
+			manifest, err := readManifest(f)
+			if err != nil {
+				return nil, err
+			}
+			info.Manifest = manifest
+		}
+	}
+	info.MainClass = info.Manifest["Main-Class"]
+	info.MultiRelease = strings.EqualFold(info.Manifest["Multi-Release"], "true")
+	info.BuildJdkSpec = info.Manifest["Build-Jdk-Spec"]
+
+	mainEntry := ""
+	if info.MainClass != "" {
+		mainEntry = strings.ReplaceAll(info.MainClass, ".", "/") + ".class"
+	}
+	releases := make(map[int]bool)
+	for _, f := range r.File {
+		if !strings.HasSuffix(f.Name, ".class") || f.FileInfo().IsDir() {
+			continue
+		}
+
+		// 多版本目录中的class只在对应版本及以上的Java中使用，非多版本jar中会被忽略
+		if strings.HasPrefix(f.Name, versionsPrefix) {
+			version, _, _ := strings.Cut(strings.TrimPrefix(f.Name, versionsPrefix), "/")
+			if n, err := strconv.Atoi(version); err == nil && info.MultiRelease {
+				releases[n] = true
+			}
+			continue
+		}
+
+		major, err := classMajor(f)
+		if err != nil {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("已跳过 %s: %v", f.Name, err))
+			continue
+		}
+		if f.Name == "module-info.class" {
+			info.ModuleInfoMajor = major
+			continue
+		}
+		if f.Name == mainEntry || f.Name == "BOOT-INF/classes/"+mainEntry {
+			info.MainClassMajor = major
+		}
+		info.ClassCount++
+		if major > info.MaxMajor {
+			info.MaxMajor, info.MaxEntry = major, f.Name
+		}
+	}
+
+	for n := range releases {
+		info.ReleaseVersions = append(info.ReleaseVersions, n)
+	}
+	sort.Ints(info.ReleaseVersions)
+	return info, nil
+}
+
+// classMajor 读取class文件头中的主版本号
+func classMajor(f *zip.File) (int, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	var header [8]byte
+	if _, err := io.ReadFull(rc, header[:]); err != nil {
+		return 0, fmt.Errorf("文件过短")
+	}
+	if binary.BigEndian.Uint32(header[:4]) != 0xCAFEBABE {
+		return 0, fmt.Errorf("不是有效的class文件")
+	}
+	return int(binary.BigEndian.Uint16(header[6:8])), nil
+}
+
+// readManifest 解析清单的主属性，以空格开头的行是上一行的续行
+func readManifest(f *zip.File) (map[string]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("读取MANIFEST.MF失败: %v", err)
+	}
+	defer rc.Close()
+
+	attrs := make(map[string]string)
+	var lastKey string
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			// 空行之后是各个条目的属性，不属于主属性
+			break
+		}
+		if strings.HasPrefix(line, " ") && lastKey != "" {
+			attrs[lastKey] += line[1:]
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		lastKey = strings.TrimSpace(key)
+		attrs[lastKey] = strings.TrimPrefix(value, " ")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取MANIFEST.MF失败: %v", err)
+	}
+	return attrs, nil
+}
@@ -0,0 +1,122 @@
+package jarinfo
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// class 返回只有文件头的class文件内容
+func class(major byte) string {
+	return string([]byte{0xCA, 0xFE, 0xBA, 0xBE, 0, 0, 0, major})
+}
+
+func writeJar(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.jar")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("创建jar失败: %v", err)
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	for name, body := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("写入jar失败: %v", err)
+		}
+		fw.Write([]byte(body))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("写入jar失败: %v", err)
+	}
+	return path
+}
+
+func TestAnalyze(t *testing.T) {
+	manifest := "Manifest-Version: 1.0\r\n" +
+		"Main-Class: com.example.very.long.package.na\r\n" +
+		" me.Main\r\n" +
+		"Multi-Release: true\r\n" +
+		"Build-Jdk-Spec: 17\r\n" +
+		"\r\n" +
+		"Name: com/example/\r\n" +
+		"Main-Class: other.Main\r\n"
+	path := writeJar(t, map[string]string{
+		"META-INF/MANIFEST.MF":                          manifest,
+		"com/example/very/long/package/name/Main.class": class(52),
+		"com/example/Util.class":                        class(55),
+		"META-INF/versions/17/com/example/Util.class":   class(61),
+		"META-INF/versions/21/com/example/Util.class":   class(65),
+		"module-info.class":                             class(53),
+	})
+
+	info, err := Analyze(path)
+	if err != nil {
+		t.Fatalf("分析失败: %v", err)
+	}
+	if info.MainClass != "com.example.very.long.package.name.Main" {
+		t.Errorf("Main-Class续行解析错误: %q", info.MainClass)
+	}
+	if info.MainClassMajor != 52 {
+		t.Errorf("期望Main-Class版本52，实际: %d", info.MainClassMajor)
+	}
+	if !info.MultiRelease || !reflect.DeepEqual(info.ReleaseVersions, []int{17, 21}) {
+		t.Errorf("多版本信息错误: %v %v", info.MultiRelease, info.ReleaseVersions)
+	}
+	if info.ClassCount != 2 || info.MaxMajor != 55 || info.MaxEntry != "com/example/Util.class" {
+		t.Errorf("class统计错误: %+v", info)
+	}
+	if info.ModuleInfoMajor != 53 {
+		t.Errorf("期望module-info版本53，实际: %d", info.ModuleInfoMajor)
+	}
+	// 多版本目录和module-info不影响最低版本
+	if got := info.RequiredJava(); got != 11 {
+		t.Errorf("期望需要Java 11，实际: %d", got)
+	}
+}
+
+func TestRequiredJava(t *testing.T) {
+	tests := []struct {
+		info Info
+		want int
+	}{
+		{Info{MaxMajor: 52, BuildJdkSpec: "17"}, 8},
+		{Info{MaxMajor: 65}, 21},
+		{Info{BuildJdkSpec: "1.8"}, 8},
+		{Info{BuildJdkSpec: "11"}, 11},
+		{Info{}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.info.RequiredJava(); got != tt.want {
+			t.Errorf("%+v: 期望 %d，实际 %d", tt.info, tt.want, got)
+		}
+	}
+
+	// 非多版本jar中 META-INF/versions 下的class会被忽略
+	path := writeJar(t, map[string]string{
+		"META-INF/MANIFEST.MF":                 "Manifest-Version: 1.0\n",
+		"App.class":                            class(50),
+		"META-INF/versions/11/App.class":       class(55),
+		"BOOT-INF/classes/com/example/A.class": class(61),
+	})
+	info, err := Analyze(path)
+	if err != nil {
+		t.Fatalf("分析失败: %v", err)
+	}
+	if info.MultiRelease || len(info.ReleaseVersions) != 0 || info.RequiredJava() != 17 {
+		t.Errorf("分析结果错误: %+v", info)
+	}
+
+	// 无效的class文件被跳过并记录警告，不影响其他class
+	bad := writeJar(t, map[string]string{"Bad.class": "not a class", "App.class": class(52)})
+	info, err = Analyze(bad)
+	if err != nil {
+		t.Fatalf("无效的class文件不应导致分析失败: %v", err)
+	}
+	if len(info.Warnings) != 1 || info.ClassCount != 1 || info.RequiredJava() != 8 {
+		t.Errorf("分析结果错误: %+v", info)
+	}
+}
@@ -0,0 +1,202 @@
+package jdk
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// ArchInfo 表示JDK可执行文件的架构信息
+type ArchInfo struct {
+	// Arch 统一后的架构名称，例如 x64、x86、arm64
+	Arch string
+	// Bits 位数，32或64
+	Bits int
+	// Format 可执行文件格式：PE、ELF或Mach-O
+	Format string
+}
+
+// String 返回便于显示的架构描述，例如 "x64 (64位 PE)"
+func (a *ArchInfo) String() string {
+	return fmt.Sprintf("%s (%d位 %s)", a.Arch, a.Bits, a.Format)
+}
+
+// archAliases 各种来源的架构名称到统一名称的映射
+var archAliases = map[string]string{
+	"x64":     "x64",
+	"amd64":   "x64",
+	"x86_64":  "x64",
+	"x86-64":  "x64",
+	"x86":     "x86",
+	"386":     "x86",
+	"i386":    "x86",
+	"i586":    "x86",
+	"i686":    "x86",
+	"arm64":   "arm64",
+	"aarch64": "arm64",
+	"arm":     "arm",
+	"arm32":   "arm",
+	"aarch32": "arm",
+	"ppc64le": "ppc64le",
+	"ppc64":   "ppc64",
+	"s390x":   "s390x",
+	"riscv64": "riscv64",
+}
+
+// NormalizeArch 将 amd64、x86_64、aarch64 等架构名称统一为 x64、arm64 等，无法识别时返回小写的原名称
+func NormalizeArch(arch string) string {
+	lower := strings.ToLower(strings.TrimSpace(arch))
+	if normalized, ok := archAliases[lower]; ok {
+		return normalized
+	}
+	return lower
+}
+
+// HostArch 返回当前系统的架构名称
+func HostArch() string {
+	return NormalizeArch(runtime.GOARCH)
+}
+
+// DetectArch 读取JDK中java可执行文件的文件头判断架构，不会运行该文件
+// 依次尝试 bin\java.exe（PE）和 bin/java（ELF或Mach-O）
+func DetectArch(jdkPath string) (*ArchInfo, error) {
+	for _, name := range []string{"java.exe", "java"} {
+		path := filepath.Join(jdkPath, "bin", name)
+		if _, err := os.Stat(path); err == nil {
+			return DetectExecutableArch(path)
+		}
+	}
+	return nil, fmt.Errorf("找不到java可执行文件: %s", filepath.Join(jdkPath, "bin"))
+}
+
+// DetectExecutableArch 解析可执行文件的PE、ELF或Mach-O文件头获取架构
+func DetectExecutableArch(path string) (*ArchInfo, error) {
+	if f, err := pe.Open(path); err == nil {
+		defer f.Close()
+		return peArch(f.Machine)
+	}
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+		return elfArch(f)
+	}
+	if f, err := macho.Open(path); err == nil {
+		defer f.Close()
+		return machoArch(f.Cpu)
+	}
+	if f, err := macho.OpenFat(path); err == nil {
+		defer f.Close()
+		// 通用二进制包含多个架构，优先返回与当前系统一致的架构
+		var first *ArchInfo
+		for _, arch := range f.Arches {
+			info, err := machoArch(arch.Cpu)
+			if err != nil {
+				continue
+			}
+			if info.Arch == HostArch() {
+				return info, nil
+			}
+			if first == nil {
+				first = info
+			}
+		}
+		if first != nil {
+			return first, nil
+		}
+	}
+	return nil, fmt.Errorf("无法识别的可执行文件格式: %s", path)
+}
+
+// peArch 根据PE文件头的Machine字段返回架构
+func peArch(machine uint16) (*ArchInfo, error) {
+	switch machine {
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		return &ArchInfo{Arch: "x64", Bits: 64, Format: "PE"}, nil
+	case pe.IMAGE_FILE_MACHINE_I386:
+		return &ArchInfo{Arch: "x86", Bits: 32, Format: "PE"}, nil
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		return &ArchInfo{Arch: "arm64", Bits: 64, Format: "PE"}, nil
+	case pe.IMAGE_FILE_MACHINE_ARMNT, pe.IMAGE_FILE_MACHINE_ARM:
+		return &ArchInfo{Arch: "arm", Bits: 32, Format: "PE"}, nil
+	}
+	return nil, fmt.Errorf("未知的PE架构: 0x%x", machine)
+}
+
+// elfArch 根据ELF文件头返回架构
+func elfArch(f *elf.File) (*ArchInfo, error) {
+	bits := 32
+	if f.Class == elf.ELFCLASS64 {
+		bits = 64
+	}
+
+	var arch string
+	switch f.Machine {
+	case elf.EM_X86_64:
+		arch = "x64"
+	case elf.EM_386:
+		arch = "x86"
+	case elf.EM_AARCH64:
+		arch = "arm64"
+	case elf.EM_ARM:
+		arch = "arm"
+	case elf.EM_PPC64:
+		arch = "ppc64"
+		if f.ByteOrder.String() == "LittleEndian" {
+			arch = "ppc64le"
+		}
+	case elf.EM_S390:
+		arch = "s390x"
+	case elf.EM_RISCV:
+		arch = "riscv" + strconv.Itoa(bits)
+	default:
+		return nil, fmt.Errorf("未知的ELF架构: %s", f.Machine)
+	}
+	return &ArchInfo{Arch: arch, Bits: bits, Format: "ELF"}, nil
+}
+
+// machoArch 根据Mach-O文件头的CPU类型返回架构
+func machoArch(cpu macho.Cpu) (*ArchInfo, error) {
+	switch cpu {
+	case macho.CpuAmd64:
+		return &ArchInfo{Arch: "x64", Bits: 64, Format: "Mach-O"}, nil
+	case macho.Cpu386:
+		return &ArchInfo{Arch: "x86", Bits: 32, Format: "Mach-O"}, nil
+	case macho.CpuArm64:
+		return &ArchInfo{Arch: "arm64", Bits: 64, Format: "Mach-O"}, nil
+	case macho.CpuArm:
+		return &ArchInfo{Arch: "arm", Bits: 32, Format: "Mach-O"}, nil
+	}
+	return nil, fmt.Errorf("未知的Mach-O架构: %s", cpu)
+}
+
+// MatchInstallations 按版本查询JDK：版本名称或完整版本号相同的条目优先，
+// 查询为主版本号（如 17、1.8）时再按主版本号匹配；arch非空时只保留该架构的JDK（无法识别架构的JDK会被排除）
+func MatchInstallations(installs []Installation, query, arch string) []Installation {
+	major := MajorVersion(query)
+	isMajorQuery := major > 0 && strings.TrimPrefix(query, "1.") == strconv.Itoa(major)
+
+	var exact, byMajor []Installation
+	for _, install := range installs {
+		if arch != "" {
+			info, err := DetectArch(install.Path)
+			if err != nil || info.Arch != NormalizeArch(arch) {
+				continue
+			}
+		}
+		switch {
+		case install.Key == query || install.Version() == query:
+			exact = append(exact, install)
+		case isMajorQuery && install.Major() == major:
+			byMajor = append(byMajor, install)
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+	return byMajor
+}
@@ -0,0 +1,106 @@
+package jdk
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// minimalPE 生成只包含DOS头、PE签名和COFF文件头的最小PE文件（末尾补零以便完整读取文件头）
+func minimalPE(machine uint16) []byte {
+	data := make([]byte, 0x200)
+	copy(data, "MZ")
+	binary.LittleEndian.PutUint32(data[0x3c:], 0x40)
+	copy(data[0x40:], "PE\x00\x00")
+	binary.LittleEndian.PutUint16(data[0x44:], machine)
+	return data
+}
+
+// 测试通过PE文件头识别架构
+func TestDetectArchPE(t *testing.T) {
+	jdkPath := t.TempDir()
+	bin := filepath.Join(jdkPath, "bin")
+	if err := os.MkdirAll(bin, 0755); err != nil {
+		t.Fatalf("无法创建bin目录: %v", err)
+	}
+
+	tests := []struct {
+		machine uint16
+		arch    string
+		bits    int
+	}{
+		{0x8664, "x64", 64},
+		{0x14c, "x86", 32},
+		{0xaa64, "arm64", 64},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(filepath.Join(bin, "java.exe"), minimalPE(tt.machine), 0755); err != nil {
+			t.Fatalf("无法创建java.exe: %v", err)
+		}
+		info, err := DetectArch(jdkPath)
+		if err != nil {
+			t.Fatalf("DetectArch 错误: %v", err)
+		}
+		if info.Arch != tt.arch || info.Bits != tt.bits || info.Format != "PE" {
+			t.Errorf("Machine 0x%x 期望 %s (%d位), 得到 %s", tt.machine, tt.arch, tt.bits, info)
+		}
+	}
+}
+
+// 测试通过ELF或Mach-O文件头识别架构（使用测试程序自身）
+func TestDetectArchHost(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skipf("无法获取测试程序路径: %v", err)
+	}
+	info, err := DetectExecutableArch(exe)
+	if err != nil {
+		t.Fatalf("DetectExecutableArch 错误: %v", err)
+	}
+	if info.Arch != HostArch() {
+		t.Errorf("期望 %s, 得到 %s", HostArch(), info)
+	}
+
+	if _, err := DetectArch(t.TempDir()); err == nil {
+		t.Error("没有java可执行文件时应该返回错误")
+	}
+}
+
+// 测试按版本和架构查询JDK
+func TestMatchInstallations(t *testing.T) {
+	root := t.TempDir()
+	newJDK := func(name string, machine uint16) string {
+		path := filepath.Join(root, name)
+		os.MkdirAll(filepath.Join(path, "bin"), 0755)
+		os.WriteFile(filepath.Join(path, "bin", "java.exe"), minimalPE(machine), 0755)
+		return path
+	}
+
+	installs := []Installation{
+		{Key: "8", Path: newJDK("jdk8-x86", 0x14c), Release: &Release{JavaVersion: "1.8.0_301"}},
+		{Key: "17", Path: newJDK("jdk17-x64", 0x8664), Release: &Release{JavaVersion: "17.0.2"}},
+		{Key: "17-arm", Path: newJDK("jdk17-arm64", 0xaa64), Release: &Release{JavaVersion: "17.0.9"}},
+	}
+
+	check := func(query, arch string, expected ...string) {
+		t.Helper()
+		matches := MatchInstallations(installs, query, arch)
+		if len(matches) != len(expected) {
+			t.Fatalf("查询 %s/%s 期望 %v, 得到 %v", query, arch, expected, matches)
+		}
+		for i := range expected {
+			if matches[i].Key != expected[i] {
+				t.Errorf("查询 %s/%s 期望 %v, 得到 %v", query, arch, expected, matches)
+			}
+		}
+	}
+
+	check("17", "", "17")
+	check("17", "arm64", "17-arm")
+	check("17", "aarch64", "17-arm")
+	check("1.8", "", "8")
+	check("8", "x64")
+	check("17.0.9", "", "17-arm")
+	check("17.0", "")
+}
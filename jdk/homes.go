@@ -0,0 +1,98 @@
+package jdk
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"switch/config"
+)
+
+// VersionHomes 返回每个主版本对应的环境变量名称（按配置中的名称模板）和JDK路径，例如 JAVA_17_HOME
+// 同一主版本有多个JDK时优先选择与当前系统架构一致的，其次是版本名称排序靠前的；无效的JDK和无法确定主版本的JDK被忽略
+func VersionHomes(cfg *config.Config) map[string]string {
+	chosen := make(map[int]Installation)
+	native := make(map[int]bool)
+	for _, install := range Installations(cfg.JDKPaths) {
+		major := install.Major()
+		if major == 0 || !ValidateJDKPath(install.Path) {
+			continue
+		}
+		isNative := false
+		if info, err := DetectArch(install.Path); err == nil {
+			isNative = info.Arch == HostArch()
+		}
+		// Installations已按版本名称排序，只有架构更合适时才替换
+		if _, exists := chosen[major]; !exists || isNative && !native[major] {
+			chosen[major] = install
+			native[major] = isNative
+		}
+	}
+
+	homes := make(map[string]string, len(chosen))
+	for major, install := range chosen {
+		homes[cfg.VersionHomeVar(major)] = install.Path
+	}
+	return homes
+}
+
+// SyncVersionHomes 将系统环境变量与VersionHomes的结果同步：设置缺少或值不同的变量，删除上一次设置过但不再需要的变量
+// 未开启version_homes时删除上一次设置过的全部变量。返回应记录到配置 managed_version_homes 中的变量和修改过的变量名称
+func SyncVersionHomes(cfg *config.Config) (map[string]string, []string, error) {
+	want := map[string]string{}
+	if cfg.VersionHomes {
+		want = VersionHomes(cfg)
+	}
+	if len(want) == 0 && len(cfg.ManagedVersionHomes) == 0 {
+		return want, nil, nil
+	}
+	if runtime.GOOS != "windows" {
+		return nil, nil, fmt.Errorf("当前只支持Windows系统")
+	}
+
+	var changed []string
+	for name := range cfg.ManagedVersionHomes {
+		// 环境变量名称不区分大小写，模板只改了大小写时不能删除
+		if sameNameKept(name, want) {
+			continue
+		}
+		if err := DeleteSystemEnvVarFromRegistry(name); err != nil {
+			return nil, changed, fmt.Errorf("删除系统%s失败: %v", name, err)
+		}
+		changed = append(changed, name)
+	}
+
+	names := make([]string, 0, len(want))
+	for name := range want {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// 与注册表比较而不是与上次记录的值比较，变量被手动修改或删除后也能恢复
+		if current, err := GetSystemEnvVarFromRegistry(name); err == nil && current == want[name] {
+			continue
+		}
+		if err := SetSystemEnvVarToRegistry(name, want[name]); err != nil {
+			return nil, changed, fmt.Errorf("设置系统%s失败: %v", name, err)
+		}
+		changed = append(changed, name)
+	}
+
+	if len(changed) > 0 {
+		if err := BroadcastEnvironmentChange(); err != nil {
+			fmt.Printf("警告: 环境变量可能需要手动刷新 (%v)\n", err)
+		}
+	}
+	sort.Strings(changed)
+	return want, changed, nil
+}
+
+// sameNameKept 判断names中是否有与name只差大小写的变量
+func sameNameKept(name string, names map[string]string) bool {
+	for other := range names {
+		if strings.EqualFold(other, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package jdk
+
+import (
+	"os"
+	"path/filepath"
+	"switch/config"
+	"testing"
+)
+
+func TestVersionHomes(t *testing.T) {
+	release := func(dir, version string) {
+		content := "JAVA_VERSION=\"" + version + "\"\n"
+		if err := os.WriteFile(filepath.Join(dir, "release"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	jdk11, cleanup11 := setupTestJDK(t)
+	defer cleanup11()
+	release(jdk11, "11.0.20")
+	jdk17a, cleanup17a := setupTestJDK(t)
+	defer cleanup17a()
+	release(jdk17a, "17.0.8")
+	jdk17b, cleanup17b := setupTestJDK(t)
+	defer cleanup17b()
+	release(jdk17b, "17.0.2")
+
+	cfg := &config.Config{JDKPaths: map[string]string{
+		"11":          jdk11,
+		"17-corretto": jdk17a,
+		"17-temurin":  jdk17b,
+		"21":          filepath.Join(t.TempDir(), "missing"),
+	}}
+	homes := VersionHomes(cfg)
+	// 架构相同时选择版本名称排序靠前的，无效的JDK被忽略
+	if len(homes) != 2 || homes["JAVA_11_HOME"] != jdk11 || homes["JAVA_17_HOME"] != jdk17a {
+		t.Errorf("主版本环境变量错误: %v", homes)
+	}
+
+	cfg.VersionHomeName = "JDK_${major}"
+	if homes := VersionHomes(cfg); homes["JDK_11"] != jdk11 {
+		t.Errorf("应使用名称模板: %v", homes)
+	}
+
+	// 未开启且没有设置过变量时不需要修改系统环境变量
+	want, changed, err := SyncVersionHomes(cfg)
+	if err != nil || len(want) != 0 || len(changed) != 0 {
+		t.Errorf("未开启时不应修改: %v %v %v", want, changed, err)
+	}
+}
@@ -0,0 +1,53 @@
+package jdk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"switch/config"
+)
+
+// linkBackupFile 备份目录中记录current链接指向的文件，没有.txt扩展名，不会被当作环境变量恢复
+const linkBackupFile = "current_link"
+
+// CurrentLinkPath 返回链接模式下current链接的路径，位于配置文件所在目录
+func CurrentLinkPath() string {
+	return filepath.Join(config.Dir(), "current")
+}
+
+// ReadCurrentLink 返回current链接指向的JDK目录，链接不存在时返回空字符串
+func ReadCurrentLink() (string, error) {
+	link := CurrentLinkPath()
+	info, err := os.Lstat(link)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("读取current链接失败: %v", err)
+	}
+	if !isLink(info) {
+		return "", fmt.Errorf("%s 已存在且不是链接", link)
+	}
+	target, err := os.Readlink(link)
+	if err != nil {
+		return "", fmt.Errorf("读取current链接失败: %v", err)
+	}
+	return target, nil
+}
+
+// SetCurrentLink 将current链接指向jdkPath，已存在的链接会被替换
+// 其他平台先创建新的符号链接再重命名覆盖，Windows上原地修改目录联接的指向，
+// 其他进程都不会看到链接不存在的中间状态（Windows上已有的是符号链接时除外，见replaceLink）
+func SetCurrentLink(jdkPath string) error {
+	if _, err := ReadCurrentLink(); err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(jdkPath)
+	if err != nil {
+		return fmt.Errorf("无法解析路径 %s: %v", jdkPath, err)
+	}
+	if err := os.MkdirAll(config.Dir(), 0755); err != nil {
+		return fmt.Errorf("创建配置目录失败: %v", err)
+	}
+	return replaceLink(CurrentLinkPath(), absPath)
+}
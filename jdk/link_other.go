@@ -0,0 +1,28 @@
+//go:build !windows
+// +build !windows
+
+package jdk
+
+import (
+	"fmt"
+	"os"
+)
+
+// isLink 判断文件是否为符号链接
+func isLink(info os.FileInfo) bool {
+	return info.Mode()&os.ModeSymlink != 0
+}
+
+// replaceLink 创建临时符号链接后重命名为link，rename会原子地替换旧链接
+func replaceLink(link, target string) error {
+	tmp := fmt.Sprintf("%s.new%d", link, os.Getpid())
+	os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return fmt.Errorf("创建符号链接失败: %v", err)
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("替换符号链接失败: %v", err)
+	}
+	return nil
+}
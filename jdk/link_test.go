@@ -0,0 +1,77 @@
+package jdk
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"switch/config"
+	"testing"
+)
+
+func TestCurrentLink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows上备份和恢复会修改注册表，只在非Windows平台上测试符号链接")
+	}
+	t.Setenv(config.HomeEnv, t.TempDir())
+
+	if target, err := ReadCurrentLink(); err != nil || target != "" {
+		t.Fatalf("链接不存在时应返回空字符串，实际: %q, %v", target, err)
+	}
+
+	first, second := t.TempDir(), t.TempDir()
+	for _, target := range []string{first, second} {
+		if err := SetCurrentLink(target); err != nil {
+			t.Fatalf("设置链接失败: %v", err)
+		}
+		got, err := ReadCurrentLink()
+		if err != nil || got != target {
+			t.Errorf("期望链接指向 %s，实际: %q, %v", target, got, err)
+		}
+	}
+
+	// 替换链接时不应留下临时链接
+	entries, _ := os.ReadDir(config.Dir())
+	if len(entries) != 1 {
+		t.Errorf("配置目录中应只有current链接，实际有%d项", len(entries))
+	}
+
+	// 链接模式下备份会记录链接，恢复时链接回到备份时的指向
+	id, err := BackupEnvironmentVariables()
+	if err != nil {
+		t.Fatalf("备份失败: %v", err)
+	}
+	if err := SetCurrentLink(first); err != nil {
+		t.Fatalf("设置链接失败: %v", err)
+	}
+	restoreID, err := RestoreBackup(id)
+	if err != nil {
+		t.Fatalf("恢复备份失败: %v", err)
+	}
+	if got, _ := ReadCurrentLink(); got != second {
+		t.Errorf("恢复后链接应指向 %s，实际: %s", second, got)
+	}
+
+	// 同一秒内的多次备份不能共用目录
+	if restoreID == id {
+		t.Errorf("恢复前所做的备份与原备份使用了相同的标识 %s", id)
+	}
+
+	// 没有登记在备份信息中的文件不属于该备份，恢复时应被忽略
+	stray := filepath.Join(BackupDir(restoreID), "JAVA_HOME.txt")
+	if err := os.WriteFile(stray, []byte("/opt/other"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RestoreBackup(restoreID); err != nil {
+		t.Errorf("恢复备份时不应使用未登记的文件: %v", err)
+	}
+}
+
+func TestCurrentLinkRefusesDirectory(t *testing.T) {
+	t.Setenv(config.HomeEnv, t.TempDir())
+	if err := os.Mkdir(filepath.Join(config.Dir(), "current"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetCurrentLink(t.TempDir()); err == nil {
+		t.Error("current为普通目录时不应被替换")
+	}
+}
@@ -0,0 +1,95 @@
+//go:build windows
+// +build windows
+
+package jdk
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"unicode/utf16"
+
+	"golang.org/x/sys/windows"
+)
+
+// isLink 判断文件是否为符号链接或目录联接（junction），新版Go将联接报告为ModeIrregular
+func isLink(info os.FileInfo) bool {
+	return info.Mode()&(os.ModeSymlink|os.ModeIrregular) != 0
+}
+
+// replaceLink 将link设置为指向target的目录联接，目录联接不需要管理员权限或开发者模式
+// 已存在的联接通过FSCTL_SET_REPARSE_POINT原地修改指向，其他进程不会看到联接不存在的中间状态；
+// link是符号链接时无法原地改为联接，只能先删除再创建，其间有很短的时间link不存在
+func replaceLink(link, target string) error {
+	if _, err := os.Lstat(link); os.IsNotExist(err) {
+		return createJunction(link, target)
+	} else if err != nil {
+		return fmt.Errorf("读取目录联接失败: %v", err)
+	}
+
+	err := setJunction(link, target)
+	if err == nil {
+		return nil
+	}
+	if err != windows.ERROR_REPARSE_TAG_MISMATCH {
+		return fmt.Errorf("修改目录联接失败: %v", err)
+	}
+	// 删除链接本身，不影响其指向的JDK目录
+	if err := os.Remove(link); err != nil {
+		return fmt.Errorf("删除旧链接失败: %v", err)
+	}
+	return createJunction(link, target)
+}
+
+// createJunction 创建空目录并将其设置为指向target的目录联接，失败时删除该目录
+func createJunction(link, target string) error {
+	if err := os.Mkdir(link, 0755); err != nil {
+		return fmt.Errorf("创建目录联接失败: %v", err)
+	}
+	if err := setJunction(link, target); err != nil {
+		os.Remove(link)
+		return fmt.Errorf("创建目录联接失败: %v", err)
+	}
+	return nil
+}
+
+// setJunction 将目录link的重解析点设置为指向target的挂载点，已有的挂载点会被原地替换
+func setJunction(link, target string) error {
+	name, err := windows.UTF16PtrFromString(link)
+	if err != nil {
+		return err
+	}
+	handle, err := windows.CreateFile(name, windows.GENERIC_WRITE,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE|windows.FILE_SHARE_DELETE, nil,
+		windows.OPEN_EXISTING, windows.FILE_FLAG_OPEN_REPARSE_POINT|windows.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(handle)
+
+	data := mountPointReparseData(target)
+	var returned uint32
+	return windows.DeviceIoControl(handle, windows.FSCTL_SET_REPARSE_POINT, &data[0], uint32(len(data)), nil, 0, &returned, nil)
+}
+
+// mountPointReparseData 生成挂载点的REPARSE_DATA_BUFFER
+// 替换名称为NT路径 \??\target，显示名称为target，两者都以空字符结尾
+func mountPointReparseData(target string) []byte {
+	substitute := utf16.Encode([]rune(`\??\` + target))
+	display := utf16.Encode([]rune(target))
+	names := append(append(append(substitute, 0), display...), 0)
+
+	dataLen := 8 + 2*len(names)
+	buf := make([]byte, 8+dataLen)
+	le := binary.LittleEndian
+	le.PutUint32(buf[0:], windows.IO_REPARSE_TAG_MOUNT_POINT)
+	le.PutUint16(buf[4:], uint16(dataLen))
+	le.PutUint16(buf[8:], 0)
+	le.PutUint16(buf[10:], uint16(2*len(substitute)))
+	le.PutUint16(buf[12:], uint16(2*(len(substitute)+1)))
+	le.PutUint16(buf[14:], uint16(2*len(display)))
+	for i, c := range names {
+		le.PutUint16(buf[16+2*i:], c)
+	}
+	return buf
+}
@@ -0,0 +1,117 @@
+package jdk
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"switch/config"
+)
+
+// pathListSeparator PATH环境变量中条目的分隔符（Windows为分号）
+const pathListSeparator = string(os.PathListSeparator)
+
+// BuildPath 计算切换后的PATH：删除所有Java相关条目和空条目，并在开头添加JDK的bin目录
+func BuildPath(pathValue, jdkPath string) string {
+	return BuildProfilePath(pathValue, jdkPath, nil, nil)
+}
+
+// BuildProfilePath 在BuildPath的基础上删除remove中的条目（上一个配置方案添加的），
+// 并将add中的条目放在JDK的bin目录之后
+// PATH中有shim目录时保留在最前面，否则JDK的bin目录会绕过shim
+func BuildProfilePath(pathValue, jdkPath string, add, remove []string) string {
+	jdkBinPath := filepath.Join(jdkPath, "bin") // 使用完整路径而不是变量引用
+
+	var newPathEntries []string
+	shimsDir := ShimsDir()
+	if containsPath(splitPath(pathValue), shimsDir) {
+		newPathEntries = append(newPathEntries, shimsDir)
+	}
+	newPathEntries = append(newPathEntries, jdkBinPath)
+	for _, entry := range add {
+		if entry = strings.TrimSpace(entry); entry != "" && !containsPath(newPathEntries, entry) {
+			newPathEntries = append(newPathEntries, entry)
+		}
+	}
+	for _, entry := range strings.Split(pathValue, pathListSeparator) {
+		entry = strings.TrimSpace(entry)
+		// 跳过空条目、Java相关条目、上一个配置方案添加的条目和已放在开头的条目
+		if entry == "" || IsJavaPathEntry(entry) || containsPath(remove, entry) || containsPath(add, entry) || samePath(entry, shimsDir) {
+			continue
+		}
+		newPathEntries = append(newPathEntries, entry)
+	}
+
+	return strings.Join(newPathEntries, pathListSeparator)
+}
+
+// SwitchedPath 按切换选项计算切换后的PATH，SetJavaHome和切换预览共用
+// 链接模式下JDK的bin目录使用current链接路径；current链接的bin目录总会先被删除，非链接模式下不会残留
+func SwitchedPath(pathValue, jdkPath string, opts SwitchOptions) string {
+	if opts.Link {
+		jdkPath = CurrentLinkPath()
+	}
+	removePath := append(append([]string{}, opts.RemovePath...), filepath.Join(CurrentLinkPath(), "bin"))
+	return BuildProfilePath(pathValue, jdkPath, opts.AddPath, removePath)
+}
+
+// ShimsDir 返回shim启动器所在目录，位于配置文件所在目录
+func ShimsDir() string {
+	return filepath.Join(config.Dir(), "shims")
+}
+
+// containsPath 判断entries中是否有与entry相同的路径
+func containsPath(entries []string, entry string) bool {
+	for _, e := range entries {
+		if samePath(strings.TrimSpace(e), entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsJavaPathEntry 判断PATH条目是否与Java相关，切换时这些条目会被删除
+// 特别注意Oracle安装程序添加的javapath路径，它会让java命令始终指向固定版本
+func IsJavaPathEntry(entry string) bool {
+	// 统一使用反斜杠比较，使其他平台上的路径也能按相同规则判断
+	lower := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(entry), "/", "\\"))
+	return lower == "%java_home%\\bin" ||
+		lower == "$java_home\\bin" ||
+		strings.Contains(lower, "\\java\\") ||
+		strings.Contains(lower, "\\jdk") ||
+		strings.Contains(lower, "oracle\\java\\javapath")
+}
+
+// DiffPath 比较两个PATH值，返回被删除和新增的条目（按原顺序）
+func DiffPath(oldPath, newPath string) (removed, added []string) {
+	oldEntries := splitPath(oldPath)
+	newEntries := splitPath(newPath)
+
+	inNew := make(map[string]bool)
+	for _, entry := range newEntries {
+		inNew[entry] = true
+	}
+	inOld := make(map[string]bool)
+	for _, entry := range oldEntries {
+		inOld[entry] = true
+		if !inNew[entry] {
+			removed = append(removed, entry)
+		}
+	}
+	for _, entry := range newEntries {
+		if !inOld[entry] {
+			added = append(added, entry)
+		}
+	}
+	return removed, added
+}
+
+// splitPath 拆分PATH值，忽略空条目
+func splitPath(pathValue string) []string {
+	var entries []string
+	for _, entry := range strings.Split(pathValue, pathListSeparator) {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
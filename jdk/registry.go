@@ -14,6 +14,9 @@ import (
 // 系统环境变量注册表路径
 const envRegistryPath = `SYSTEM\CurrentControlSet\Control\Session Manager\Environment`
 
+// 用户环境变量注册表路径（HKEY_CURRENT_USER下）
+const userEnvRegistryPath = `Environment`
+
 // GetSystemEnvVarFromRegistry 从注册表直接读取系统环境变量原始值
 func GetSystemEnvVarFromRegistry(name string) (string, error) {
 	// 打开系统环境变量注册表键
@@ -36,6 +39,28 @@ func GetSystemEnvVarFromRegistry(name string) (string, error) {
 	return value, nil
 }
 
+// GetUserEnvVarFromRegistry 从注册表读取当前用户环境变量原始值，变量不存在时返回空字符串
+func GetUserEnvVarFromRegistry(name string) (string, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, userEnvRegistryPath, registry.QUERY_VALUE)
+	if err != nil {
+		if errors.Is(err, registry.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("打开注册表失败: %v", err)
+	}
+	defer key.Close()
+
+	value, _, err := key.GetStringValue(name)
+	if err != nil {
+		if errors.Is(err, registry.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("读取环境变量值失败: %v", err)
+	}
+
+	return value, nil
+}
+
 // SetSystemEnvVarToRegistry 设置系统环境变量（通过注册表）
 func SetSystemEnvVarToRegistry(name, value string) error {
 	// 打开系统环境变量注册表键
@@ -55,6 +80,23 @@ func SetSystemEnvVarToRegistry(name, value string) error {
 	return nil
 }
 
+// DeleteSystemEnvVarFromRegistry 删除系统环境变量（通过注册表），变量不存在时不报错
+func DeleteSystemEnvVarFromRegistry(name string) error {
+	// 打开系统环境变量注册表键
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, envRegistryPath, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("打开注册表失败: %v", err)
+	}
+	defer key.Close()
+
+	// 删除环境变量值
+	if err := key.DeleteValue(name); err != nil && !errors.Is(err, registry.ErrNotExist) {
+		return fmt.Errorf("删除环境变量值失败: %v", err)
+	}
+
+	return nil
+}
+
 // BroadcastEnvironmentChange 广播环境变量更改消息（导出函数）
 func BroadcastEnvironmentChange() error {
 	// 使用PowerShell脚本发送WM_SETTINGCHANGE消息，并使用try-catch捕获可能的错误
@@ -13,6 +13,12 @@ func GetSystemEnvVarFromRegistry(name string) (string, error) {
 	return "", fmt.Errorf("不支持的平台: 只有Windows支持通过注册表获取环境变量")
 }
 
+// GetUserEnvVarFromRegistry 从注册表读取当前用户环境变量原始值
+// 在非Windows平台上，这个函数总是返回错误
+func GetUserEnvVarFromRegistry(name string) (string, error) {
+	return "", fmt.Errorf("不支持的平台: 只有Windows支持通过注册表获取环境变量")
+}
+
 // SetSystemEnvVarToRegistry 设置系统环境变量（通过注册表）
 // 在非Windows平台上，这个函数总是返回错误
 func SetSystemEnvVarToRegistry(name, value string) error {
@@ -23,4 +29,10 @@ func SetSystemEnvVarToRegistry(name, value string) error {
 // 在非Windows平台上，这个函数不执行任何操作
 func BroadcastEnvironmentChange() error {
 	return fmt.Errorf("不支持的平台: 只有Windows支持广播环境变量更改")
-} 
\ No newline at end of file
+}
+
+// DeleteSystemEnvVarFromRegistry 删除系统环境变量（通过注册表）
+// 在非Windows平台上，这个函数总是返回错误
+func DeleteSystemEnvVarFromRegistry(name string) error {
+	return fmt.Errorf("不支持的平台: 只有Windows支持通过注册表删除环境变量")
+}
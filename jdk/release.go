@@ -0,0 +1,235 @@
+package jdk
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Release 表示JDK根目录下release文件中的信息
+type Release struct {
+	// JavaVersion 对应JAVA_VERSION，例如 "17.0.2" 或 "1.8.0_301"
+	JavaVersion string
+	// JavaRuntimeVersion 对应JAVA_RUNTIME_VERSION，例如 "17.0.2+8"
+	JavaRuntimeVersion string
+	// Implementor 对应IMPLEMENTOR，例如 "Eclipse Adoptium"
+	Implementor string
+	// OSName 对应OS_NAME，例如 "Windows"
+	OSName string
+	// OSArch 对应OS_ARCH，例如 "amd64"
+	OSArch string
+	// Properties release文件中的全部键值对
+	Properties map[string]string
+}
+
+// ReadRelease 读取并解析JDK目录下的release文件
+func ReadRelease(jdkPath string) (*Release, error) {
+	data, err := os.ReadFile(filepath.Join(jdkPath, "release"))
+	if err != nil {
+		return nil, fmt.Errorf("读取release文件失败: %v", err)
+	}
+	return ParseRelease(data), nil
+}
+
+// ParseRelease 解析release文件内容，格式为每行一个 KEY="value"
+func ParseRelease(data []byte) *Release {
+	props := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		props[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+
+	return &Release{
+		JavaVersion:        props["JAVA_VERSION"],
+		JavaRuntimeVersion: props["JAVA_RUNTIME_VERSION"],
+		Implementor:        props["IMPLEMENTOR"],
+		OSName:             props["OS_NAME"],
+		OSArch:             props["OS_ARCH"],
+		Properties:         props,
+	}
+}
+
+// MajorVersion 返回release文件中Java版本的主版本号，无法识别时返回0
+func (r *Release) MajorVersion() int {
+	return MajorVersion(r.JavaVersion)
+}
+
+// MajorVersion 从Java版本字符串中解析主版本号
+// 支持 "1.8.0_301"（返回8）、"17.0.2"、"21"、"17-ea" 等格式，无法识别时返回0
+func MajorVersion(version string) int {
+	version = strings.TrimSpace(version)
+	version = strings.TrimPrefix(version, "1.")
+	end := 0
+	for end < len(version) && version[end] >= '0' && version[end] <= '9' {
+		end++
+	}
+	major, err := strconv.Atoi(version[:end])
+	if err != nil {
+		return 0
+	}
+	return major
+}
+
+// Installation 表示配置中的一个JDK及其release信息
+type Installation struct {
+	// Key 配置中的版本名称
+	Key string
+	// Path JDK安装路径
+	Path string
+	// Release release文件信息，文件不存在或无法读取时为nil
+	Release *Release
+}
+
+// Installations 读取配置中所有JDK的release信息，按主版本号和版本名称排序
+func Installations(paths map[string]string) []Installation {
+	var installs []Installation
+	for key, path := range paths {
+		install := Installation{Key: key, Path: path}
+		if release, err := ReadRelease(path); err == nil {
+			install.Release = release
+		}
+		installs = append(installs, install)
+	}
+
+	sort.Slice(installs, func(i, j int) bool {
+		if installs[i].Major() != installs[j].Major() {
+			return installs[i].Major() < installs[j].Major()
+		}
+		return installs[i].Key < installs[j].Key
+	})
+	return installs
+}
+
+// LowestSatisfying 返回主版本号不低于minMajor的JDK中版本最低的一个，可以运行java的才会考虑
+// 同一主版本有多个JDK时优先选择与当前系统架构一致的
+func LowestSatisfying(installs []Installation, minMajor int) (Installation, bool) {
+	var best Installation
+	found, bestNative := false, false
+	for _, install := range installs {
+		major := install.Major()
+		if major < minMajor || major == 0 {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(install.Path, "bin", ExecutableName("java"))); err != nil || info.IsDir() {
+			continue
+		}
+		native := false
+		if info, err := DetectArch(install.Path); err == nil {
+			native = info.Arch == HostArch()
+		}
+		if !found || major < best.Major() || major == best.Major() && native && !bestNative {
+			best, found, bestNative = install, true, native
+		}
+	}
+	return best, found
+}
+
+// Major 返回JDK的主版本号，优先使用release文件，否则从版本名称推断
+func (i Installation) Major() int {
+	if i.Release != nil {
+		if major := i.Release.MajorVersion(); major > 0 {
+			return major
+		}
+	}
+	return MajorVersion(i.Key)
+}
+
+// Version 返回JDK的完整版本号，没有release信息时返回版本名称
+func (i Installation) Version() string {
+	if i.Release != nil && i.Release.JavaVersion != "" {
+		return i.Release.JavaVersion
+	}
+	return i.Key
+}
+
+// Vendor 返回JDK的提供商，没有release信息时返回空字符串
+func (i Installation) Vendor() string {
+	if i.Release == nil {
+		return ""
+	}
+	return i.Release.Implementor
+}
+
+// vendorIDs 常见JDK提供商（IMPLEMENTOR）对应的简短标识
+var vendorIDs = []struct {
+	keyword string
+	id      string
+}{
+	{"adoptium", "temurin"},
+	{"adoptopenjdk", "adopt"},
+	{"azul", "zulu"},
+	{"amazon", "corretto"},
+	{"bellsoft", "liberica"},
+	{"graalvm", "graalvm"},
+	{"microsoft", "microsoft"},
+	{"sap", "sapmachine"},
+	{"alibaba", "dragonwell"},
+	{"tencent", "kona"},
+	{"red hat", "redhat"},
+	{"ibm", "semeru"},
+	{"jetbrains", "jbr"},
+	{"oracle", "oracle"},
+}
+
+// VendorID 返回提供商名称对应的简短标识，例如 "Eclipse Adoptium" 返回 "temurin"
+// 无法识别时返回名称第一个单词的小写形式，名称为空时返回空字符串
+func VendorID(implementor string) string {
+	lower := strings.ToLower(implementor)
+	for _, vendor := range vendorIDs {
+		if strings.Contains(lower, vendor.keyword) {
+			return vendor.id
+		}
+	}
+	fields := strings.FieldsFunc(lower, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// SuggestKey 根据JDK目录的release文件生成配置中的默认版本名称
+// 依次尝试主版本号(17)、主版本号加提供商(17-temurin)、完整版本号(17.0.2)和目录名，
+// 都已被占用时在目录名后追加序号
+func SuggestKey(path string, exists func(key string) bool) string {
+	var candidates []string
+	if release, err := ReadRelease(path); err == nil {
+		if major := release.MajorVersion(); major > 0 {
+			candidates = append(candidates, strconv.Itoa(major))
+			if vendor := VendorID(release.Implementor); vendor != "" {
+				candidates = append(candidates, fmt.Sprintf("%d-%s", major, vendor))
+			}
+		}
+		if release.JavaVersion != "" {
+			candidates = append(candidates, release.JavaVersion)
+		}
+	}
+	base := filepath.Base(filepath.Clean(path))
+	candidates = append(candidates, base)
+
+	for _, key := range candidates {
+		if !exists(key) {
+			return key
+		}
+	}
+	for i := 2; ; i++ {
+		key := fmt.Sprintf("%s-%d", base, i)
+		if !exists(key) {
+			return key
+		}
+	}
+}
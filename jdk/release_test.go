@@ -0,0 +1,105 @@
+package jdk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 测试主版本号解析
+func TestMajorVersion(t *testing.T) {
+	tests := map[string]int{
+		"1.8.0_301": 8,
+		"1.7.0":     7,
+		"11.0.12":   11,
+		"17.0.2":    17,
+		"21":        21,
+		"22-ea":     22,
+		"":          0,
+		"abc":       0,
+	}
+	for version, expected := range tests {
+		if major := MajorVersion(version); major != expected {
+			t.Errorf("MajorVersion(%q) 期望 %d, 得到 %d", version, expected, major)
+		}
+	}
+}
+
+// 测试读取release文件
+func TestReadRelease(t *testing.T) {
+	jdkPath, cleanup := setupTestJDK(t)
+	defer cleanup()
+
+	content := "IMPLEMENTOR=\"Eclipse Adoptium\"\nJAVA_VERSION=\"17.0.2\"\nOS_ARCH=\"x86_64\"\n"
+	if err := os.WriteFile(filepath.Join(jdkPath, "release"), []byte(content), 0644); err != nil {
+		t.Fatalf("无法创建release文件: %v", err)
+	}
+
+	release, err := ReadRelease(jdkPath)
+	if err != nil {
+		t.Fatalf("ReadRelease 错误: %v", err)
+	}
+	if release.JavaVersion != "17.0.2" || release.Implementor != "Eclipse Adoptium" || release.OSArch != "x86_64" {
+		t.Errorf("release信息解析错误: %+v", release)
+	}
+	if release.MajorVersion() != 17 {
+		t.Errorf("期望主版本号 17, 得到 %d", release.MajorVersion())
+	}
+
+	// 没有release文件时根据版本名称推断
+	installs := Installations(map[string]string{"17": jdkPath, "8": filepath.Join(jdkPath, "missing")})
+	if len(installs) != 2 || installs[0].Key != "8" || installs[1].Vendor() != "Eclipse Adoptium" {
+		t.Errorf("Installations 结果错误: %+v", installs)
+	}
+}
+
+// 测试默认版本名称的生成
+func TestSuggestKey(t *testing.T) {
+	jdkPath, cleanup := setupTestJDK(t)
+	defer cleanup()
+
+	content := "IMPLEMENTOR=\"Eclipse Adoptium\"\nJAVA_VERSION=\"17.0.2\"\n"
+	if err := os.WriteFile(filepath.Join(jdkPath, "release"), []byte(content), 0644); err != nil {
+		t.Fatalf("无法创建release文件: %v", err)
+	}
+
+	taken := map[string]bool{}
+	exists := func(key string) bool { return taken[key] }
+
+	for _, expected := range []string{"17", "17-temurin", "17.0.2", filepath.Base(jdkPath), filepath.Base(jdkPath) + "-2"} {
+		key := SuggestKey(jdkPath, exists)
+		if key != expected {
+			t.Fatalf("期望 %s, 得到 %s", expected, key)
+		}
+		taken[key] = true
+	}
+
+	if id := VendorID("Azul Systems, Inc."); id != "zulu" {
+		t.Errorf("期望 zulu, 得到 %s", id)
+	}
+}
+
+func TestLowestSatisfying(t *testing.T) {
+	root := t.TempDir()
+	paths := map[string]string{}
+	for _, key := range []string{"8", "11", "17", "21"} {
+		dir := filepath.Join(root, key)
+		os.MkdirAll(filepath.Join(dir, "bin"), 0755)
+		if key != "11" {
+			os.WriteFile(filepath.Join(dir, "bin", ExecutableName("java")), []byte("fake"), 0755)
+		}
+		paths[key] = dir
+	}
+	installs := Installations(paths)
+
+	// 11 没有java，跳过
+	for min, want := range map[int]string{0: "8", 8: "8", 9: "17", 17: "17", 18: "21"} {
+		install, ok := LowestSatisfying(installs, min)
+		if !ok || install.Key != want {
+			t.Errorf("需要 %d: 期望 %s, 得到 %s (%v)", min, want, install.Key, ok)
+		}
+	}
+	if _, ok := LowestSatisfying(installs, 22); ok {
+		t.Error("没有满足要求的JDK时应返回false")
+	}
+}
@@ -5,166 +5,365 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
+	"switch/config"
 	"time"
 )
 
-// BackupEnvironmentVariables 备份当前系统环境变量到C:\jdk-switch\backup\年月日时分秒目录
-func BackupEnvironmentVariables() error {
+// SwitchOptions 切换JDK时除JAVA_HOME、PATH、CLASSPATH之外的附加设置
+type SwitchOptions struct {
+	// ExtraEnv 需要设置的附加环境变量（占位符已展开）
+	ExtraEnv map[string]string
+	// UnsetEnv 需要从系统环境变量中删除的附加环境变量
+	UnsetEnv []string
+	// AddPath 添加到PATH中JDK的bin目录之后的条目
+	AddPath []string
+	// RemovePath 需要从PATH中删除的条目（上一个配置方案添加的）
+	RemovePath []string
+	// Link 链接模式：将current链接指向JDK，JAVA_HOME和PATH使用链接路径，已经设置好时不再修改
+	Link bool
+	// BackupEnv 切换前需要一并备份的其他环境变量，如主版本环境变量（JAVA_17_HOME）
+	BackupEnv []string
+}
+
+// backupEntry 描述一个需要备份的环境变量及其备份文件名（不含扩展名）
+type backupEntry struct {
+	name string
+	file string
+}
+
+// backupEnvNames 每次备份都会包含的环境变量
+var backupEnvNames = []backupEntry{
+	{"Path", "PATH"},
+	{"JAVA_HOME", "JAVA_HOME"},
+	{"CLASSPATH", "CLASSPATH"},
+}
+
+const (
+	// backupInfoFile 备份信息文件，登记了该备份包含的文件，恢复时只使用其中登记的文件
+	backupInfoFile = "backup_info.txt"
+	// linkInfoName 备份信息文件中current链接条目的名称
+	linkInfoName = "current链接"
+)
+
+// BackupDir 返回备份标识对应的备份目录
+func BackupDir(id string) string {
+	return filepath.Join(config.Dir(), "backup", id)
+}
+
+// BackupEnvironmentVariables 备份当前系统环境变量到 配置目录\backup\年月日时分秒 目录，返回备份标识（目录名）
+// 除PATH、JAVA_HOME、CLASSPATH外，extraNames中的环境变量（附加环境变量、主版本环境变量等）也会一并备份
+func BackupEnvironmentVariables(extraNames ...string) (string, error) {
+	// 链接模式下同时记录current链接指向的JDK；非Windows平台只能备份链接
+	linkTarget, err := ReadCurrentLink()
+	if err != nil {
+		return "", err
+	}
+	if runtime.GOOS != "windows" && linkTarget == "" {
+		return "", fmt.Errorf("当前只支持Windows系统")
+	}
+
 	// 创建备份目录
-	baseDir := `C:\jdk-switch`
+	baseDir := config.Dir()
 	if err := os.MkdirAll(baseDir, 0755); err != nil {
-		return fmt.Errorf("创建备份基础目录失败: %v", err)
+		return "", fmt.Errorf("创建备份基础目录失败: %v", err)
 	}
 
 	// 创建backup子目录
 	backupBaseDir := filepath.Join(baseDir, "backup")
 	if err := os.MkdirAll(backupBaseDir, 0755); err != nil {
-		return fmt.Errorf("创建备份子目录失败: %v", err)
+		return "", fmt.Errorf("创建备份子目录失败: %v", err)
 	}
 
-	// 获取当前时间作为备份标识
+	// 获取当前时间作为备份标识，同一秒内的多次备份追加 _2、_3 等后缀
 	now := time.Now()
-	timestamp := now.Format("20060102_150405")
-	backupDir := filepath.Join(backupBaseDir, timestamp)
+	timestamp, backupDir, err := createBackupDir(now)
+	if err != nil {
+		return "", err
+	}
 
-	// 创建时间戳子目录
-	if err := os.MkdirAll(backupDir, 0755); err != nil {
-		return fmt.Errorf("创建备份时间目录失败: %v", err)
+	// 固定备份的环境变量在前，extraNames在后
+	entries := append([]backupEntry{}, backupEnvNames...)
+	seen := make(map[string]bool)
+	for _, name := range extraNames {
+		if !isFixedBackupName(name) && !seen[strings.ToUpper(name)] {
+			seen[strings.ToUpper(name)] = true
+			entries = append(entries, backupEntry{name, name})
+		}
 	}
 
-	// 从注册表获取系统PATH环境变量（保留原始变量引用）
-	pathSystem, err := GetSystemEnvVarFromRegistry("Path")
-	if err != nil {
-		return fmt.Errorf("获取系统PATH环境变量失败: %v", err)
+	infoContent := fmt.Sprintf("备份时间: %s\n", now.Format("2006-01-02 15:04:05"))
+	infoContent += "备份文件:\n"
+
+	if runtime.GOOS != "windows" {
+		entries = nil
 	}
+	for _, entry := range entries {
+		// 从注册表获取系统环境变量（保留原始变量引用）
+		value, err := GetSystemEnvVarFromRegistry(entry.name)
+		if err != nil {
+			return "", fmt.Errorf("获取系统%s环境变量失败: %v", entry.file, err)
+		}
 
-	// 写入PATH环境变量
-	pathFile := filepath.Join(backupDir, "PATH.txt")
-	if err := os.WriteFile(pathFile, []byte(pathSystem), 0644); err != nil {
-		return fmt.Errorf("备份PATH环境变量失败: %v", err)
+		file := filepath.Join(backupDir, entry.file+".txt")
+		if err := os.WriteFile(file, []byte(value), 0644); err != nil {
+			return "", fmt.Errorf("备份%s环境变量失败: %v", entry.file, err)
+		}
+		infoContent += fmt.Sprintf("- %s: %s\n", entry.file, file)
 	}
 
-	// 获取并备份系统JAVA_HOME环境变量
-	javaHome, err := GetSystemEnvVarFromRegistry("JAVA_HOME")
+	if linkTarget != "" {
+		file := filepath.Join(backupDir, linkBackupFile)
+		if err := os.WriteFile(file, []byte(linkTarget), 0644); err != nil {
+			return "", fmt.Errorf("备份current链接失败: %v", err)
+		}
+		infoContent += fmt.Sprintf("- %s: %s -> %s\n", linkInfoName, file, linkTarget)
+	}
+
+	// 创建备份信息文件
+	infoFile := filepath.Join(backupDir, backupInfoFile)
+	if err := os.WriteFile(infoFile, []byte(infoContent), 0644); err != nil {
+		return "", fmt.Errorf("创建备份信息文件失败: %v", err)
+	}
+
+	// 打印备份成功信息，使用实际时间戳
+	fmt.Printf("环境变量已备份到 %s 目录\n", backupDir)
+
+	return timestamp, nil
+}
+
+// createBackupDir 以时间戳为标识创建新的备份目录，目录已存在时依次尝试 _2、_3 等后缀
+// 使用不覆盖已有目录的方式创建，同一秒内的多次备份（包括其他进程所做的）不会写入同一目录
+func createBackupDir(now time.Time) (string, string, error) {
+	timestamp := now.Format("20060102_150405")
+	for i := 1; i <= 100; i++ {
+		id := timestamp
+		if i > 1 {
+			id = fmt.Sprintf("%s_%d", timestamp, i)
+		}
+		dir := BackupDir(id)
+		err := os.Mkdir(dir, 0755)
+		if err == nil {
+			return id, dir, nil
+		}
+		if !os.IsExist(err) {
+			return "", "", fmt.Errorf("创建备份时间目录失败: %v", err)
+		}
+	}
+	return "", "", fmt.Errorf("创建备份时间目录失败: %s 的备份过多", timestamp)
+}
+
+// readBackupInfo 读取备份信息文件中登记的备份文件名（不含扩展名），以及是否备份了current链接
+// 只有登记过的文件才属于该备份
+func readBackupInfo(id string) ([]string, bool, error) {
+	data, err := os.ReadFile(filepath.Join(BackupDir(id), backupInfoFile))
 	if err != nil {
-		return fmt.Errorf("获取系统JAVA_HOME环境变量失败: %v", err)
+		if os.IsNotExist(err) {
+			return nil, false, fmt.Errorf("备份 %s 不存在或不完整", id)
+		}
+		return nil, false, fmt.Errorf("读取备份 %s 失败: %v", id, err)
+	}
+	var names []string
+	hasLink := false
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if !strings.HasPrefix(line, "- ") {
+			continue
+		}
+		name, _, found := strings.Cut(strings.TrimPrefix(line, "- "), ": ")
+		if !found || strings.ContainsAny(name, `/\`) {
+			continue
+		}
+		if name == linkInfoName {
+			hasLink = true
+		} else {
+			names = append(names, name)
+		}
 	}
+	return names, hasLink, nil
+}
 
-	javaHomeFile := filepath.Join(backupDir, "JAVA_HOME.txt")
-	if err := os.WriteFile(javaHomeFile, []byte(javaHome), 0644); err != nil {
-		return fmt.Errorf("备份JAVA_HOME环境变量失败: %v", err)
+// isFixedBackupName 判断环境变量是否已在每次备份的固定列表中（不区分大小写）
+func isFixedBackupName(name string) bool {
+	for _, entry := range backupEnvNames {
+		if strings.EqualFold(entry.name, name) {
+			return true
+		}
 	}
+	return false
+}
 
-	// 获取并备份系统CLASSPATH环境变量
-	classpath, err := GetSystemEnvVarFromRegistry("CLASSPATH")
+// RestoreBackup 将系统环境变量恢复为指定备份中的值，备份中为空的环境变量会被删除
+// 备份中记录了current链接时同时恢复链接的指向
+// 恢复前会先备份当前的环境变量（包括备份中的和extraNames中的），返回该备份的标识
+func RestoreBackup(id string, extraNames ...string) (string, error) {
+	names, hasLink, err := readBackupInfo(id)
 	if err != nil {
-		return fmt.Errorf("获取系统CLASSPATH环境变量失败: %v", err)
+		return "", err
 	}
 
-	classpathFile := filepath.Join(backupDir, "CLASSPATH.txt")
-	if err := os.WriteFile(classpathFile, []byte(classpath), 0644); err != nil {
-		return fmt.Errorf("备份CLASSPATH环境变量失败: %v", err)
+	var linkTarget string
+	if hasLink {
+		data, err := os.ReadFile(filepath.Join(BackupDir(id), linkBackupFile))
+		if err != nil {
+			return "", fmt.Errorf("读取备份文件失败: %v", err)
+		}
+		linkTarget = string(data)
 	}
 
-	// 创建备份信息文件
-	infoContent := fmt.Sprintf("备份时间: %s\n", now.Format("2006-01-02 15:04:05"))
-	infoContent += "备份文件:\n"
-	infoContent += fmt.Sprintf("- PATH: %s\n", pathFile)
-	infoContent += fmt.Sprintf("- JAVA_HOME: %s\n", javaHomeFile)
-	infoContent += fmt.Sprintf("- CLASSPATH: %s\n", classpathFile)
+	// 备份文件名为环境变量名，PATH对应注册表中的Path
+	values := make(map[string]string)
+	extraNames = append([]string{}, extraNames...)
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(BackupDir(id), name+".txt"))
+		if err != nil {
+			return "", fmt.Errorf("读取备份文件失败: %v", err)
+		}
+		isFixed := false
+		for _, entry := range backupEnvNames {
+			if entry.file == name {
+				name, isFixed = entry.name, true
+			}
+		}
+		if !isFixed {
+			extraNames = append(extraNames, name)
+		}
+		values[name] = string(data)
+	}
+	if len(values) == 0 && linkTarget == "" {
+		return "", fmt.Errorf("备份 %s 不存在或为空", id)
+	}
+	if len(values) > 0 && runtime.GOOS != "windows" {
+		return "", fmt.Errorf("当前只支持Windows系统")
+	}
 
-	infoFile := filepath.Join(backupDir, "backup_info.txt")
-	if err := os.WriteFile(infoFile, []byte(infoContent), 0644); err != nil {
-		return fmt.Errorf("创建备份信息文件失败: %v", err)
+	currentID, err := BackupEnvironmentVariables(extraNames...)
+	if err != nil {
+		return "", fmt.Errorf("备份当前环境变量失败: %v", err)
 	}
 
-	// 打印备份成功信息，使用实际时间戳
-	fmt.Printf("环境变量已备份到 C:\\jdk-switch\\backup\\%s 目录\n", timestamp)
+	if linkTarget != "" {
+		if err := SetCurrentLink(linkTarget); err != nil {
+			return currentID, fmt.Errorf("恢复current链接失败: %v", err)
+		}
+	}
+	if len(values) == 0 {
+		return currentID, nil
+	}
+
+	for name, value := range values {
+		if value == "" {
+			err = DeleteSystemEnvVarFromRegistry(name)
+		} else {
+			err = SetSystemEnvVarToRegistry(name, value)
+		}
+		if err != nil {
+			return currentID, fmt.Errorf("恢复系统%s失败: %v", name, err)
+		}
+	}
 
-	return nil
+	if err := BroadcastEnvironmentChange(); err != nil {
+		fmt.Printf("警告: 环境变量可能需要手动刷新 (%v)\n", err)
+	}
+	return currentID, nil
 }
 
-func SetJavaHome(jdkPath string) error {
-	if runtime.GOOS != "windows" {
-		return fmt.Errorf("当前只支持Windows系统")
+// SetJavaHome 设置系统级JAVA_HOME、PATH、CLASSPATH，并按opts设置或清除附加环境变量
+// 链接模式下先将current链接指向jdkPath，环境变量使用链接路径，已经设置好时跳过修改和广播
+// 返回切换前所做备份的标识，可用于撤销本次切换
+func SetJavaHome(jdkPath string, opts SwitchOptions) (string, error) {
+	if runtime.GOOS != "windows" && !opts.Link {
+		return "", fmt.Errorf("当前只支持Windows系统")
 	}
 
 	// 验证JDK路径是否存在
 	if _, err := os.Stat(jdkPath); os.IsNotExist(err) {
-		return fmt.Errorf("JDK路径不存在: %s", jdkPath)
+		return "", fmt.Errorf("JDK路径不存在: %s", jdkPath)
 	}
 
 	// 注意：ValidateJDKPath已经在switchJDK函数中调用过，这里不再重复验证
 
-	// 备份当前环境变量
+	// 备份当前环境变量（包括将被修改或清除的附加环境变量）
+	var setNames []string
+	for name := range opts.ExtraEnv {
+		setNames = append(setNames, name)
+	}
+	sort.Strings(setNames)
+	extraNames := append(append(append([]string{}, setNames...), opts.UnsetEnv...), opts.BackupEnv...)
+
+	// 非Windows平台只能备份current链接，第一次创建链接时没有可备份的内容
+	var backupID string
 	backupStart := time.Now()
-	if err := BackupEnvironmentVariables(); err != nil {
-		return fmt.Errorf("备份环境变量失败: %v", err)
+	if link, err := ReadCurrentLink(); runtime.GOOS == "windows" || link != "" || err != nil {
+		if backupID, err = BackupEnvironmentVariables(extraNames...); err != nil {
+			return "", fmt.Errorf("备份环境变量失败: %v", err)
+		}
 	}
 	backupDuration := time.Since(backupStart)
 	fmt.Printf("备份环境变量耗时: %s\n", backupDuration)
 
+	// 链接模式：修改current链接的指向，之后的环境变量都使用链接路径
+	if opts.Link {
+		if err := SetCurrentLink(jdkPath); err != nil {
+			return backupID, fmt.Errorf("更新current链接失败: %v", err)
+		}
+		fmt.Printf("current链接已指向 %s\n", jdkPath)
+		jdkPath = CurrentLinkPath()
+		if runtime.GOOS != "windows" {
+			printLinkShellHint(jdkPath)
+			return backupID, nil
+		}
+	}
+
 	// 检查是否存在Oracle Java路径问题
 	oracleJavaPathExists := checkOracleJavaPath()
-	
+
 	// 读取环境变量阶段开始时间
 	readEnvStart := time.Now()
-	
+
 	// 获取系统级PATH环境变量
 	pathSystem, err := GetSystemEnvVarFromRegistry("Path")
 	if err != nil {
-		return fmt.Errorf("获取系统PATH环境变量失败: %v", err)
+		return backupID, fmt.Errorf("获取系统PATH环境变量失败: %v", err)
 	}
-	
+
 	// 读取环境变量阶段结束时间
 	readEnvDuration := time.Since(readEnvStart)
 	fmt.Printf("读取环境变量耗时: %s\n", readEnvDuration)
-	
+
 	// 修改环境变量阶段开始时间
 	modifyEnvStart := time.Now()
 
-	// 设置系统级JAVA_HOME环境变量
-	if err := SetSystemEnvVarToRegistry("JAVA_HOME", jdkPath); err != nil {
-		return fmt.Errorf("设置系统JAVA_HOME失败: %v", err)
-	}
-
-	// 解析PATH为条目列表
-	pathEntries := strings.Split(pathSystem, ";")
+	// 删除所有Java相关条目、上一个配置方案的条目和current链接的bin目录，并在PATH开头添加新的JDK bin路径（使用完整路径）和配置方案的条目
+	newPath := SwitchedPath(pathSystem, jdkPath, opts)
 
-	// 创建新的PATH，删除所有Java相关条目
-	var newPathEntries []string
-	jdkBinPath := filepath.Join(jdkPath, "bin") // 使用完整路径而不是变量引用
+	// 使用完整路径而不是变量引用
+	dtJarPath := filepath.Join(jdkPath, "lib", "dt.jar")
+	toolsJarPath := filepath.Join(jdkPath, "lib", "tools.jar")
+	classpath := fmt.Sprintf(".;%s;%s;", dtJarPath, toolsJarPath)
 
-	for _, entry := range pathEntries {
-		entry = strings.TrimSpace(entry)
-		// 跳过空条目和Java相关条目，特别注意Oracle的javapath路径
-		if entry == "" || 
-		   entry == "%JAVA_HOME%\\bin" || 
-		   strings.Contains(strings.ToLower(entry), "\\java\\") ||
-		   strings.Contains(strings.ToLower(entry), "\\jdk") ||
-		   strings.Contains(strings.ToLower(entry), "oracle\\java\\javapath") {
-			continue
+	// 链接模式下环境变量已经指向current链接时，只需要修改链接，不必重写PATH和广播
+	if opts.Link {
+		unchanged, err := environmentUnchanged(jdkPath, pathSystem, newPath, classpath, opts)
+		if err != nil {
+			return backupID, err
+		}
+		if unchanged {
+			fmt.Println("环境变量已指向current链接，无需修改")
+			return backupID, nil
 		}
-		newPathEntries = append(newPathEntries, entry)
 	}
 
-	// 在PATH开头添加新的JDK bin路径（使用完整路径）
-	newPathEntries = append([]string{jdkBinPath}, newPathEntries...)
-	newPath := strings.Join(newPathEntries, ";")
+	// 设置系统级JAVA_HOME环境变量
+	if err := SetSystemEnvVarToRegistry("JAVA_HOME", jdkPath); err != nil {
+		return backupID, fmt.Errorf("设置系统JAVA_HOME失败: %v", err)
+	}
 
 	// 更新系统级PATH环境变量
 	if err := SetSystemEnvVarToRegistry("Path", newPath); err != nil {
-		return fmt.Errorf("更新系统PATH失败: %v", err)
+		return backupID, fmt.Errorf("更新系统PATH失败: %v", err)
 	}
 
-	// 设置系统级CLASSPATH环境变量
-	dtJarPath := filepath.Join(jdkPath, "lib", "dt.jar")
-	toolsJarPath := filepath.Join(jdkPath, "lib", "tools.jar")
-	
-	// 使用完整路径而不是变量引用
-	classpath := fmt.Sprintf(".;%s;%s;", dtJarPath, toolsJarPath)
-
 	// 检查JDK中是否存在这些jar文件
 	var warnings []string
 	if _, err := os.Stat(dtJarPath); os.IsNotExist(err) {
@@ -176,27 +375,41 @@ func SetJavaHome(jdkPath string) error {
 
 	// 设置CLASSPATH环境变量
 	if err := SetSystemEnvVarToRegistry("CLASSPATH", classpath); err != nil {
-		return fmt.Errorf("设置系统CLASSPATH失败: %v", err)
+		return backupID, fmt.Errorf("设置系统CLASSPATH失败: %v", err)
+	}
+
+	// 清除目标JDK未定义的附加环境变量
+	for _, name := range opts.UnsetEnv {
+		if err := DeleteSystemEnvVarFromRegistry(name); err != nil {
+			return backupID, fmt.Errorf("清除系统%s失败: %v", name, err)
+		}
+	}
+
+	// 设置目标JDK的附加环境变量
+	for _, name := range setNames {
+		if err := SetSystemEnvVarToRegistry(name, opts.ExtraEnv[name]); err != nil {
+			return backupID, fmt.Errorf("设置系统%s失败: %v", name, err)
+		}
 	}
-	
+
 	// 修改环境变量阶段结束时间
 	modifyEnvDuration := time.Since(modifyEnvStart)
 	fmt.Printf("修改环境变量耗时: %s\n", modifyEnvDuration)
 
 	// 广播环境变量阶段开始时间
 	broadcastStart := time.Now()
-	
+
 	// 所有环境变量都设置完成后，只执行一次广播
 	if err := BroadcastEnvironmentChange(); err != nil {
 		fmt.Printf("警告: 环境变量可能需要手动刷新 (%v)\n", err)
 	} else {
 		fmt.Println("\n环境变量已成功通知系统")
 	}
-	
+
 	// 广播环境变量阶段结束时间
 	broadcastDuration := time.Since(broadcastStart)
 	fmt.Printf("广播环境变量变更耗时: %s\n", broadcastDuration)
-	
+
 	// 总耗时统计
 	totalDuration := backupDuration + readEnvDuration + modifyEnvDuration + broadcastDuration
 	fmt.Printf("\n总耗时: %s\n", totalDuration)
@@ -215,23 +428,46 @@ func SetJavaHome(jdkPath string) error {
 		fmt.Println("2. 或临时重命名该目录: C:\\Program Files\\Common Files\\Oracle\\Java\\javapath")
 	}
 
-	return nil
+	return backupID, nil
 }
 
-func ValidateJDKPath(path string) bool {
-	// 检查java.exe是否存在
-	javaExe := filepath.Join(path, "bin", "java.exe")
-	if _, err := os.Stat(javaExe); err != nil {
-		return false
+// environmentUnchanged 判断系统环境变量是否已经是切换后的值
+func environmentUnchanged(javaHome, oldPath, newPath, classpath string, opts SwitchOptions) (bool, error) {
+	if oldPath != newPath {
+		return false, nil
+	}
+	expected := map[string]string{"JAVA_HOME": javaHome, "CLASSPATH": classpath}
+	for name, value := range opts.ExtraEnv {
+		expected[name] = value
+	}
+	for _, name := range opts.UnsetEnv {
+		expected[name] = ""
+	}
+	for name, value := range expected {
+		current, err := GetSystemEnvVarFromRegistry(name)
+		if err != nil {
+			return false, fmt.Errorf("获取系统%s环境变量失败: %v", name, err)
+		}
+		if current != value {
+			return false, nil
+		}
 	}
+	return true, nil
+}
 
-	// 检查javac.exe是否存在
-	javacExe := filepath.Join(path, "bin", "javac.exe")
-	if _, err := os.Stat(javacExe); err != nil {
-		return false
+// printLinkShellHint 非Windows平台不修改系统环境变量，JAVA_HOME未指向current链接时提示如何设置
+func printLinkShellHint(link string) {
+	if samePath(os.Getenv("JAVA_HOME"), link) {
+		return
 	}
+	fmt.Println("请在shell配置文件（如 ~/.bashrc、~/.zshrc）中添加以下内容，之后切换只需修改current链接:")
+	fmt.Printf("  export JAVA_HOME=%q\n", link)
+	fmt.Println("  export PATH=\"$JAVA_HOME/bin:$PATH\"")
+}
 
-	return true
+// ValidateJDKPath 检查路径是否为完整的JDK，详细检查结果见ValidateInstallation
+func ValidateJDKPath(path string) bool {
+	return ValidateInstallation(path).Kind == KindJDK
 }
 
 // checkOracleJavaPath 检查系统中是否存在Oracle Java路径问题
@@ -246,13 +482,13 @@ func checkOracleJavaPath() bool {
 			return true
 		}
 	}
-	
+
 	// 检查环境变量PATH中是否包含Oracle Java路径
 	pathSystem, err := GetSystemEnvVarFromRegistry("Path")
 	if err != nil {
 		return false // 无法读取PATH，假设没有问题
 	}
-	
+
 	// 检查PATH中是否包含Oracle路径
 	pathEntries := strings.Split(pathSystem, ";")
 	for _, entry := range pathEntries {
@@ -261,6 +497,6 @@ func checkOracleJavaPath() bool {
 			return true
 		}
 	}
-	
+
 	return false
 }
@@ -26,15 +26,18 @@ func setupTestJDK(t *testing.T) (string, func()) {
 		t.Fatalf("无法创建lib目录: %v", err)
 	}
 
-	// 创建java.exe和javac.exe空文件
-	javaExe := filepath.Join(binDir, "java.exe")
-	if err := os.WriteFile(javaExe, []byte{}, 0755); err != nil {
-		t.Fatalf("无法创建java.exe: %v", err)
+	// 创建当前平台的java和javac可执行文件（空文件会被视为无效）
+	for _, name := range []string{"java", "javac"} {
+		exe := filepath.Join(binDir, ExecutableName(name))
+		if err := os.WriteFile(exe, []byte("fake"), 0755); err != nil {
+			t.Fatalf("无法创建%s: %v", ExecutableName(name), err)
+		}
 	}
 
-	javacExe := filepath.Join(binDir, "javac.exe")
-	if err := os.WriteFile(javacExe, []byte{}, 0755); err != nil {
-		t.Fatalf("无法创建javac.exe: %v", err)
+	// 创建运行时类库
+	modules := filepath.Join(libDir, "modules")
+	if err := os.WriteFile(modules, []byte("fake"), 0644); err != nil {
+		t.Fatalf("无法创建lib/modules: %v", err)
 	}
 
 	// 创建一些JAR文件
@@ -73,23 +76,83 @@ func TestValidateJDKPath(t *testing.T) {
 		t.Errorf("ValidateJDKPath(%s) 应该返回 false", invalidPath)
 	}
 
-	// 测试缺少java.exe的路径
-	javaExePath := filepath.Join(jdkPath, "bin", "java.exe")
+	// 测试缺少java的路径
+	javaExePath := filepath.Join(jdkPath, "bin", ExecutableName("java"))
 	os.Remove(javaExePath)
 	if ValidateJDKPath(jdkPath) {
-		t.Errorf("缺少java.exe时ValidateJDKPath应该返回false")
+		t.Errorf("缺少java时ValidateJDKPath应该返回false")
 	}
 
-	// 重新创建java.exe
+	// java为空文件时同样无效
 	if err := os.WriteFile(javaExePath, []byte{}, 0755); err != nil {
-		t.Fatalf("无法重新创建java.exe: %v", err)
+		t.Fatalf("无法重新创建java: %v", err)
+	}
+	if ValidateJDKPath(jdkPath) {
+		t.Errorf("java为空文件时ValidateJDKPath应该返回false")
+	}
+
+	// 重新创建java
+	if err := os.WriteFile(javaExePath, []byte("fake"), 0755); err != nil {
+		t.Fatalf("无法重新创建java: %v", err)
 	}
 
-	// 测试缺少javac.exe的路径
-	javacExePath := filepath.Join(jdkPath, "bin", "javac.exe")
+	// 测试缺少javac的路径
+	javacExePath := filepath.Join(jdkPath, "bin", ExecutableName("javac"))
 	os.Remove(javacExePath)
 	if ValidateJDKPath(jdkPath) {
-		t.Errorf("缺少javac.exe时ValidateJDKPath应该返回false")
+		t.Errorf("缺少javac时ValidateJDKPath应该返回false")
+	}
+}
+
+// 测试安装目录分类
+func TestValidateInstallation(t *testing.T) {
+	jdkPath, cleanup := setupTestJDK(t)
+	defer cleanup()
+
+	report := ValidateInstallation(jdkPath)
+	if report.Kind != KindJDK {
+		t.Fatalf("完整JDK应分类为JDK，实际为 %s: %v", report.Kind, report.Problems())
+	}
+
+	// 可选检查项失败不影响分类
+	for _, check := range report.Checks {
+		if check.Name == "jlink" && check.OK {
+			t.Errorf("测试JDK中没有jlink，检查不应通过")
+		}
+	}
+
+	// javac为空文件时视为已损坏
+	javacExePath := filepath.Join(jdkPath, "bin", ExecutableName("javac"))
+	if err := os.WriteFile(javacExePath, []byte{}, 0755); err != nil {
+		t.Fatalf("无法写入javac: %v", err)
+	}
+	if kind := ValidateInstallation(jdkPath).Kind; kind != KindCorrupt {
+		t.Errorf("javac为空文件时应分类为已损坏，实际为 %s", kind)
+	}
+
+	// 没有javac时视为JRE
+	os.Remove(javacExePath)
+	report = ValidateInstallation(jdkPath)
+	if report.Kind != KindJRE {
+		t.Errorf("没有javac时应分类为JRE，实际为 %s", report.Kind)
+	}
+	if problems := report.Problems(); len(problems) != 0 {
+		t.Errorf("JRE不应报告必需检查项失败: %v", problems)
+	}
+
+	// JDK 8的运行时类库位于jre/lib/rt.jar
+	os.Remove(filepath.Join(jdkPath, "lib", "modules"))
+	if kind := ValidateInstallation(jdkPath).Kind; kind != KindCorrupt {
+		t.Errorf("缺少运行时类库时应分类为已损坏，实际为 %s", kind)
+	}
+	if err := os.MkdirAll(filepath.Join(jdkPath, "jre", "lib"), 0755); err != nil {
+		t.Fatalf("无法创建jre/lib目录: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(jdkPath, "jre", "lib", "rt.jar"), []byte("fake"), 0644); err != nil {
+		t.Fatalf("无法创建rt.jar: %v", err)
+	}
+	if kind := ValidateInstallation(jdkPath).Kind; kind != KindJRE {
+		t.Errorf("存在rt.jar时应分类为JRE，实际为 %s", kind)
 	}
 }
 
@@ -133,4 +196,4 @@ func TestSetJavaHomeMock(t *testing.T) {
 	if _, err := os.Stat(dtJar); os.IsNotExist(err) {
 		t.Errorf("dt.jar不存在: %s", dtJar)
 	}
-} 
\ No newline at end of file
+}
@@ -0,0 +1,164 @@
+package jdk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// InstallKind 安装目录的分类
+type InstallKind int
+
+const (
+	// KindCorrupt 缺少运行Java所必需的文件
+	KindCorrupt InstallKind = iota
+	// KindJRE 可以运行Java但没有编译器
+	KindJRE
+	// KindJDK 完整的JDK
+	KindJDK
+)
+
+func (k InstallKind) String() string {
+	switch k {
+	case KindJDK:
+		return "JDK"
+	case KindJRE:
+		return "JRE"
+	default:
+		return "已损坏"
+	}
+}
+
+// Check 单项检查的结果
+type Check struct {
+	// Name 检查项名称
+	Name string
+	// OK 检查是否通过
+	OK bool
+	// Required 为true时检查失败会影响安装目录的分类
+	Required bool
+	// Detail 检查的对象或失败原因
+	Detail string
+}
+
+// ValidationReport 安装目录的检查报告
+type ValidationReport struct {
+	Path   string
+	Kind   InstallKind
+	Checks []Check
+}
+
+// Problems 返回未通过的必需检查项描述
+func (r *ValidationReport) Problems() []string {
+	var problems []string
+	for _, check := range r.Checks {
+		if check.Required && !check.OK {
+			problems = append(problems, fmt.Sprintf("%s: %s", check.Name, check.Detail))
+		}
+	}
+	return problems
+}
+
+// ExecutableName 返回当前平台上可执行文件的文件名，Windows下追加.exe
+func ExecutableName(name string) string {
+	if runtime.GOOS == "windows" {
+		return name + ".exe"
+	}
+	return name
+}
+
+// ValidateInstallation 检查安装目录的完整性，并将其分类为JDK、JRE或已损坏
+// java可执行文件和运行时类库缺失或无效时视为已损坏；javac不存在时视为JRE，存在但无效时视为已损坏
+// release文件、jmods和jar、jlink、jpackage、jshell等工具只作为参考，不影响分类
+func ValidateInstallation(path string) *ValidationReport {
+	report := &ValidationReport{Path: path, Kind: KindCorrupt}
+
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		report.Checks = append(report.Checks, Check{Name: "安装目录", Required: true, Detail: "目录不存在"})
+		return report
+	}
+
+	javaCheck := checkExecutable(path, "java", true)
+	runtimeCheck := checkRuntimeLibs(path)
+	javacCheck := checkExecutable(path, "javac", true)
+	report.Checks = append(report.Checks, javaCheck, runtimeCheck, javacCheck)
+
+	releaseCheck := Check{Name: "release文件", Detail: filepath.Join(path, "release")}
+	if _, err := ReadRelease(path); err == nil {
+		releaseCheck.OK = true
+	} else {
+		releaseCheck.Detail = "无法读取release文件"
+	}
+	report.Checks = append(report.Checks, releaseCheck)
+
+	jmodsCheck := Check{Name: "jmods", Detail: filepath.Join(path, "jmods")}
+	if info, err := os.Stat(jmodsCheck.Detail); err == nil && info.IsDir() {
+		jmodsCheck.OK = true
+	} else {
+		jmodsCheck.Detail = "jmods目录不存在(JDK 8及部分精简发行版没有该目录)"
+	}
+	report.Checks = append(report.Checks, jmodsCheck)
+
+	for _, tool := range []string{"jar", "jlink", "jpackage", "jshell"} {
+		report.Checks = append(report.Checks, checkExecutable(path, tool, false))
+	}
+
+	switch {
+	case !javaCheck.OK || !runtimeCheck.OK:
+		report.Kind = KindCorrupt
+	case javacCheck.OK:
+		report.Kind = KindJDK
+	default:
+		// 没有javac是JRE，javac存在但无效说明安装目录已损坏
+		if _, err := os.Stat(filepath.Join(path, "bin", ExecutableName("javac"))); err == nil {
+			report.Kind = KindCorrupt
+		} else {
+			report.Kind = KindJRE
+			// JRE中缺少javac属于正常情况
+			report.Checks[2].Required = false
+		}
+	}
+	return report
+}
+
+// checkExecutable 检查bin目录下的可执行文件存在且不是空文件
+func checkExecutable(path, name string, required bool) Check {
+	file := filepath.Join(path, "bin", ExecutableName(name))
+	check := Check{Name: name, Required: required, Detail: file}
+
+	info, err := os.Stat(file)
+	switch {
+	case err != nil:
+		check.Detail = fmt.Sprintf("%s 不存在", file)
+	case !info.Mode().IsRegular():
+		check.Detail = fmt.Sprintf("%s 不是普通文件", file)
+	case info.Size() == 0:
+		check.Detail = fmt.Sprintf("%s 是空文件", file)
+	case runtime.GOOS != "windows" && info.Mode()&0111 == 0:
+		check.Detail = fmt.Sprintf("%s 没有执行权限", file)
+	default:
+		check.OK = true
+	}
+	return check
+}
+
+// checkRuntimeLibs 检查运行时类库：JDK 9及以上为lib/modules，JDK 8为jre/lib/rt.jar（JRE中为lib/rt.jar）
+func checkRuntimeLibs(path string) Check {
+	check := Check{Name: "运行时类库", Required: true}
+	candidates := []string{
+		filepath.Join(path, "lib", "modules"),
+		filepath.Join(path, "jre", "lib", "rt.jar"),
+		filepath.Join(path, "lib", "rt.jar"),
+	}
+	for _, file := range candidates {
+		if info, err := os.Stat(file); err == nil && info.Mode().IsRegular() && info.Size() > 0 {
+			check.OK = true
+			check.Detail = file
+			return check
+		}
+	}
+	check.Detail = "lib/modules 和 rt.jar 都不存在或为空文件"
+	return check
+}
@@ -0,0 +1,273 @@
+package jdk
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"switch/config"
+	"time"
+)
+
+// verifyTimeout 运行 java -version 和 javac -version 的超时时间
+const verifyTimeout = 15 * time.Second
+
+var (
+	javaVersionPattern  = regexp.MustCompile(`version "([^"]+)"`)
+	javacVersionPattern = regexp.MustCompile(`javac\s+(\S+)`)
+)
+
+// VerifyResult 切换后在新环境中实际运行java和javac的检查结果
+type VerifyResult struct {
+	// Expected release文件中的版本号，无法读取时为空
+	Expected string
+	// JavaPath PATH中实际找到的java
+	JavaPath string
+	// JavaVersion java -version 输出的版本号
+	JavaVersion string
+	// JavacPath PATH中实际找到的javac
+	JavacPath string
+	// JavacVersion javac -version 输出的版本号
+	JavacVersion string
+	// Problems 发现的问题，为空表示验证通过
+	Problems []string
+}
+
+// OK 返回验证是否通过
+func (r *VerifyResult) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// SwitchedEnvironment 返回切换后新打开的命令行窗口将看到的环境变量
+// Windows上从注册表读取系统和用户级的PATH、JAVA_HOME（用户级JAVA_HOME会覆盖系统级），
+// 其他平台在当前进程环境变量的基础上按BuildPath计算PATH
+func SwitchedEnvironment(jdkPath string) ([]string, error) {
+	if runtime.GOOS != "windows" {
+		return MergeEnv(os.Environ(), map[string]string{
+			"JAVA_HOME": jdkPath,
+			"PATH":      BuildPath(os.Getenv("PATH"), jdkPath),
+		}), nil
+	}
+
+	systemPath, err := GetSystemEnvVarFromRegistry("Path")
+	if err != nil {
+		return nil, fmt.Errorf("获取系统PATH环境变量失败: %v", err)
+	}
+	userPath, err := GetUserEnvVarFromRegistry("Path")
+	if err != nil {
+		return nil, fmt.Errorf("获取用户PATH环境变量失败: %v", err)
+	}
+	javaHome, err := GetSystemEnvVarFromRegistry("JAVA_HOME")
+	if err != nil {
+		return nil, fmt.Errorf("获取系统JAVA_HOME环境变量失败: %v", err)
+	}
+	userJavaHome, err := GetUserEnvVarFromRegistry("JAVA_HOME")
+	if err != nil {
+		return nil, fmt.Errorf("获取用户JAVA_HOME环境变量失败: %v", err)
+	}
+	if userJavaHome != "" {
+		javaHome = userJavaHome
+	}
+
+	// 展开PATH中的 %变量% 引用，JAVA_HOME使用切换后的值
+	lookup := func(name string) string {
+		if strings.EqualFold(name, "JAVA_HOME") {
+			return javaHome
+		}
+		return os.Getenv(name)
+	}
+	path := ExpandWindowsEnv(systemPath, lookup)
+	if userPath != "" {
+		path += ";" + ExpandWindowsEnv(userPath, lookup)
+	}
+
+	return MergeEnv(os.Environ(), map[string]string{
+		"JAVA_HOME": javaHome,
+		"PATH":      path,
+	}), nil
+}
+
+// VerifySwitch 在给定环境中按PATH查找并运行 java -version 和 javac -version，
+// 检查实际生效的JDK是否为jdkPath（版本与release文件一致，且没有被shim或残留的PATH条目抢先）
+// jdkPath可以是链接模式下的current链接，比较路径时会解析链接；
+// 先找到的是shims目录中的shim时，按shim实际启动的版本检查
+func VerifySwitch(jdkPath string, env []string) *VerifyResult {
+	result := &VerifyResult{}
+	if release, err := ReadRelease(jdkPath); err == nil {
+		result.Expected = release.JavaVersion
+	}
+
+	if javaHome := envValue(env, "JAVA_HOME"); !sameLocation(javaHome, jdkPath) {
+		result.Problems = append(result.Problems,
+			fmt.Sprintf("JAVA_HOME为 %s，而不是 %s（可能被用户环境变量覆盖）", javaHome, jdkPath))
+	}
+
+	pathValue := envValue(env, "PATH")
+	result.JavaPath, result.JavaVersion = verifyTool(result, "java", jdkPath, pathValue, env, javaVersionPattern)
+	result.JavacPath, result.JavacVersion = verifyTool(result, "javac", jdkPath, pathValue, env, javacVersionPattern)
+	return result
+}
+
+// verifyTool 查找并运行单个工具，发现的问题追加到result中，返回工具路径和版本号
+func verifyTool(result *VerifyResult, tool, jdkPath, pathValue string, env []string, pattern *regexp.Regexp) (string, string) {
+	toolPath := LookPath(tool, pathValue)
+	if toolPath == "" {
+		result.Problems = append(result.Problems, fmt.Sprintf("PATH中找不到%s", tool))
+		return "", ""
+	}
+	shim := samePath(filepath.Dir(toolPath), ShimsDir())
+	if !shim && !sameLocation(filepath.Dir(toolPath), filepath.Join(jdkPath, "bin")) {
+		result.Problems = append(result.Problems,
+			fmt.Sprintf("PATH中优先找到的%s是 %s，而不是 %s 中的（可能是残留的PATH条目）", tool, toolPath, filepath.Join(jdkPath, "bin")))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), verifyTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, toolPath, "-version")
+	cmd.Env = env
+	if shim {
+		// 在配置目录中运行shim，避免当前目录的 .java-version 影响选中的版本
+		cmd.Dir = config.Dir()
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		result.Problems = append(result.Problems, fmt.Sprintf("运行 %s -version 失败: %v", tool, err))
+		return toolPath, ""
+	}
+
+	match := pattern.FindSubmatch(output)
+	if match == nil {
+		result.Problems = append(result.Problems,
+			fmt.Sprintf("无法识别 %s -version 的输出: %s", tool, strings.TrimSpace(string(output))))
+		return toolPath, ""
+	}
+	version := string(match[1])
+	if result.Expected != "" && version != result.Expected {
+		result.Problems = append(result.Problems,
+			fmt.Sprintf("%s版本为 %s，期望 %s", tool, version, result.Expected))
+	}
+	return toolPath, version
+}
+
+// LookPath 按pathValue中的顺序查找可执行文件，找不到时返回空字符串
+// Windows上按PATHEXT依次尝试扩展名（.cmd、.bat形式的shim也会被找到）
+func LookPath(name, pathValue string) string {
+	exts := []string{""}
+	if runtime.GOOS == "windows" {
+		pathext := os.Getenv("PATHEXT")
+		if pathext == "" {
+			pathext = ".COM;.EXE;.BAT;.CMD"
+		}
+		exts = strings.Split(strings.ToLower(pathext), ";")
+	}
+
+	for _, dir := range strings.Split(pathValue, pathListSeparator) {
+		dir = strings.Trim(strings.TrimSpace(dir), `"`)
+		if dir == "" {
+			continue
+		}
+		for _, ext := range exts {
+			candidate := filepath.Join(dir, name+ext)
+			info, err := os.Stat(candidate)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			if runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+				continue
+			}
+			return candidate
+		}
+	}
+	return ""
+}
+
+// ExpandWindowsEnv 展开字符串中 %NAME% 形式的环境变量引用，未定义的变量保持原样
+func ExpandWindowsEnv(s string, lookup func(name string) string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "%")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start+1:], "%")
+		if end < 0 {
+			break
+		}
+		end += start + 1
+
+		name := s[start+1 : end]
+		if value := lookup(name); name != "" && value != "" {
+			b.WriteString(s[:start])
+			b.WriteString(value)
+		} else {
+			b.WriteString(s[:end+1])
+		}
+		s = s[end+1:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// MergeEnv 用overrides覆盖base中的同名环境变量（Windows上不区分大小写）
+func MergeEnv(base []string, overrides map[string]string) []string {
+	var env []string
+	for _, kv := range base {
+		name, _, _ := strings.Cut(kv, "=")
+		if _, overridden := lookupEnvName(overrides, name); !overridden {
+			env = append(env, kv)
+		}
+	}
+	for name, value := range overrides {
+		env = append(env, name+"="+value)
+	}
+	return env
+}
+
+// lookupEnvName 在map中查找环境变量，Windows上不区分大小写
+func lookupEnvName(vars map[string]string, name string) (string, bool) {
+	for key, value := range vars {
+		if key == name || runtime.GOOS == "windows" && strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// envValue 返回环境变量列表中指定变量的值（Windows上不区分大小写）
+func envValue(env []string, name string) string {
+	value := ""
+	for _, kv := range env {
+		key, v, _ := strings.Cut(kv, "=")
+		if key == name || runtime.GOOS == "windows" && strings.EqualFold(key, name) {
+			value = v
+		}
+	}
+	return value
+}
+
+// sameLocation 解析符号链接和junction后判断两个路径是否指向同一位置，解析失败时按原路径比较
+func sameLocation(a, b string) bool {
+	if samePath(a, b) {
+		return true
+	}
+	if resolved, err := filepath.EvalSymlinks(a); err == nil {
+		a = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	return samePath(a, b)
+}
+
+// samePath 判断两个路径是否相同（Windows上不区分大小写）
+func samePath(a, b string) bool {
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
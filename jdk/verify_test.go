@@ -0,0 +1,191 @@
+package jdk
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"switch/config"
+	"testing"
+)
+
+// createFakeJDK 创建一个java/javac为shell脚本的假JDK，用于验证测试
+func createFakeJDK(t *testing.T, dir, version string) string {
+	t.Helper()
+	bin := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(bin, 0755); err != nil {
+		t.Fatalf("无法创建bin目录: %v", err)
+	}
+	scripts := map[string]string{
+		"java":  "#!/bin/sh\necho 'openjdk version \"" + version + "\" 2022-01-18' >&2\n",
+		"javac": "#!/bin/sh\necho 'javac " + version + "'\n",
+	}
+	for name, script := range scripts {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte(script), 0755); err != nil {
+			t.Fatalf("无法创建%s: %v", name, err)
+		}
+	}
+	release := "JAVA_VERSION=\"" + version + "\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "release"), []byte(release), 0644); err != nil {
+		t.Fatalf("无法创建release文件: %v", err)
+	}
+	return dir
+}
+
+// 测试切换后验证：正常情况和被shim抢先的情况
+func TestVerifySwitch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("验证测试使用shell脚本模拟java，跳过Windows平台")
+	}
+
+	root := t.TempDir()
+	jdk17 := createFakeJDK(t, filepath.Join(root, "jdk-17"), "17.0.2")
+	jdk11 := createFakeJDK(t, filepath.Join(root, "jdk-11"), "11.0.12")
+
+	env := []string{"JAVA_HOME=" + jdk17, "PATH=" + BuildPath("/usr/bin", jdk17)}
+	result := VerifySwitch(jdk17, env)
+	if !result.OK() {
+		t.Fatalf("验证应通过, 问题: %v", result.Problems)
+	}
+	if result.JavaVersion != "17.0.2" || result.JavacVersion != "17.0.2" {
+		t.Errorf("版本解析错误: %+v", result)
+	}
+
+	// 残留的PATH条目排在新JDK之前
+	stale := filepath.Join(jdk11, "bin") + pathListSeparator + filepath.Join(jdk17, "bin")
+	result = VerifySwitch(jdk17, []string{"JAVA_HOME=" + jdk17, "PATH=" + stale})
+	if result.OK() {
+		t.Fatal("被其他JDK抢先时验证不应通过")
+	}
+	if !strings.Contains(strings.Join(result.Problems, "\n"), "11.0.12") {
+		t.Errorf("问题中应说明实际版本: %v", result.Problems)
+	}
+}
+
+// 测试PATH计算
+func TestVerifySwitchLinkMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("验证测试使用shell脚本模拟java，跳过Windows平台")
+	}
+
+	root := t.TempDir()
+	t.Setenv(config.HomeEnv, filepath.Join(root, "home"))
+	jdk17 := createFakeJDK(t, filepath.Join(root, "jdk-17"), "17.0.2")
+	if err := SetCurrentLink(jdk17); err != nil {
+		t.Fatalf("创建current链接失败: %v", err)
+	}
+
+	// 链接模式下JAVA_HOME和PATH都指向current链接
+	link := CurrentLinkPath()
+	env := []string{"JAVA_HOME=" + link, "PATH=" + BuildPath("/usr/bin", link)}
+	if result := VerifySwitch(link, env); !result.OK() {
+		t.Fatalf("链接模式的切换验证应通过, 问题: %v", result.Problems)
+	}
+	// 按实际JDK目录验证时也应解析链接
+	if result := VerifySwitch(jdk17, env); !result.OK() {
+		t.Fatalf("current链接指向的JDK验证应通过, 问题: %v", result.Problems)
+	}
+}
+
+func TestVerifySwitchShims(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("验证测试使用shell脚本模拟java，跳过Windows平台")
+	}
+
+	root := t.TempDir()
+	t.Setenv(config.HomeEnv, filepath.Join(root, "home"))
+	jdk17 := createFakeJDK(t, filepath.Join(root, "jdk-17"), "17.0.2")
+	jdk11 := createFakeJDK(t, filepath.Join(root, "jdk-11"), "11.0.12")
+
+	// shim转发到切换后的JDK
+	shims := ShimsDir()
+	if err := os.MkdirAll(shims, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeShims := func(target string) {
+		for _, tool := range []string{"java", "javac"} {
+			script := "#!/bin/sh\nexec " + filepath.Join(target, "bin", tool) + " \"$@\"\n"
+			if err := os.WriteFile(filepath.Join(shims, tool), []byte(script), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	writeShims(jdk17)
+
+	env := []string{"JAVA_HOME=" + jdk17, "PATH=" + shims + pathListSeparator + filepath.Join(jdk17, "bin")}
+	if result := VerifySwitch(jdk17, env); !result.OK() {
+		t.Fatalf("shims在PATH最前面时验证应通过, 问题: %v", result.Problems)
+	}
+
+	// shim实际启动的不是切换后的JDK
+	writeShims(jdk11)
+	if result := VerifySwitch(jdk17, env); result.OK() {
+		t.Fatal("shim启动其他JDK时验证不应通过")
+	}
+}
+
+func TestBuildPath(t *testing.T) {
+	sep := pathListSeparator
+	jdkPath := filepath.Join("opt", "jdk-17")
+	pathValue := strings.Join([]string{"/usr/bin", "/opt/jdk-11/bin", "", "\\Program Files\\Java\\jdk1.8\\bin", "%JAVA_HOME%\\bin", "/usr/local/bin"}, sep)
+
+	expected := strings.Join([]string{filepath.Join(jdkPath, "bin"), "/usr/bin", "/usr/local/bin"}, sep)
+	if got := BuildPath(pathValue, jdkPath); got != expected {
+		t.Errorf("期望 %s, 得到 %s", expected, got)
+	}
+
+	// 配置方案：删除上一个方案添加的条目，新条目放在JDK的bin目录之后
+	profilePath := BuildProfilePath(pathValue, jdkPath, []string{"/opt/maven/bin", "/usr/local/bin"}, []string{"/usr/bin"})
+	expectedProfile := strings.Join([]string{filepath.Join(jdkPath, "bin"), "/opt/maven/bin", "/usr/local/bin"}, sep)
+	if profilePath != expectedProfile {
+		t.Errorf("期望 %s, 得到 %s", expectedProfile, profilePath)
+	}
+
+	// PATH中的shim目录保留在最前面
+	t.Setenv(config.HomeEnv, t.TempDir())
+	shimsPath := strings.Join([]string{"/usr/bin", ShimsDir(), "/opt/jdk-11/bin"}, sep)
+	expectedShims := strings.Join([]string{ShimsDir(), filepath.Join(jdkPath, "bin"), "/usr/bin"}, sep)
+	if got := BuildPath(shimsPath, jdkPath); got != expectedShims {
+		t.Errorf("期望 %s, 得到 %s", expectedShims, got)
+	}
+
+	removed, added := DiffPath(pathValue, expected)
+	if strings.Join(removed, ",") != "/opt/jdk-11/bin,\\Program Files\\Java\\jdk1.8\\bin,%JAVA_HOME%\\bin" {
+		t.Errorf("删除的条目不正确: %v", removed)
+	}
+	if len(added) != 1 || added[0] != filepath.Join(jdkPath, "bin") {
+		t.Errorf("新增的条目不正确: %v", added)
+	}
+
+	lookup := func(name string) string {
+		if name == "SystemRoot" {
+			return `C:\Windows`
+		}
+		return ""
+	}
+	if got := ExpandWindowsEnv(`%SystemRoot%\system32;%UNKNOWN%\bin;50%`, lookup); got != `C:\Windows\system32;%UNKNOWN%\bin;50%` {
+		t.Errorf("变量展开错误: %s", got)
+	}
+}
+
+// 测试SwitchedPath与切换时使用的PATH计算一致：配置方案条目、current链接和链接模式
+func TestSwitchedPath(t *testing.T) {
+	sep := pathListSeparator
+	t.Setenv(config.HomeEnv, t.TempDir())
+	jdkPath := filepath.Join("opt", "jdk-17")
+	linkBin := filepath.Join(CurrentLinkPath(), "bin")
+	pathValue := strings.Join([]string{linkBin, "/opt/old-tools/bin", "/usr/bin"}, sep)
+
+	opts := SwitchOptions{AddPath: []string{"/opt/maven/bin"}, RemovePath: []string{"/opt/old-tools/bin"}}
+	expected := strings.Join([]string{filepath.Join(jdkPath, "bin"), "/opt/maven/bin", "/usr/bin"}, sep)
+	if got := SwitchedPath(pathValue, jdkPath, opts); got != expected {
+		t.Errorf("期望 %s, 得到 %s", expected, got)
+	}
+
+	// 链接模式下使用current链接的bin目录
+	opts.Link = true
+	expected = strings.Join([]string{linkBin, "/opt/maven/bin", "/usr/bin"}, sep)
+	if got := SwitchedPath(pathValue, jdkPath, opts); got != expected {
+		t.Errorf("链接模式期望 %s, 得到 %s", expected, got)
+	}
+}
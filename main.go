@@ -4,10 +4,22 @@ import (
 	"bufio"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"switch/api"
+	"switch/buildtool"
 	"switch/config"
+	"switch/fsutil"
+	"switch/history"
+	"switch/hook"
+	"switch/ide"
 	"switch/jdk"
+	"switch/shim"
+	"time"
 )
 
 const version = "1.0.0"
@@ -19,13 +31,63 @@ func showHelp() {
 	fmt.Println("  -init      初始化配置文件")
 	fmt.Println("  -list      列出所有可用的JDK版本")
 	fmt.Println("  -set <版本> 切换到指定的JDK版本")
+	fmt.Println("  -set auto  根据当前目录的pom.xml、Gradle构建脚本中声明的Java版本选择满足要求的最低版本JDK，没有声明时使用 .java-version")
+	fmt.Println("  -strict    与 -set 一起使用，JDK不能运行当前项目的Gradle或Maven wrapper时拒绝切换（默认只警告）")
+	fmt.Println("  -verify    切换后在新环境中运行java -version和javac -version验证")
+	fmt.Println("  -rollback  切换后验证，失败时自动切换回原版本")
+	fmt.Println("  -arch <架构> 与 -list 或 -set 一起使用，只考虑指定架构(x64、x86、arm64)的JDK")
+	fmt.Println("  -tui       使用全屏界面选择JDK：输入筛选，↑↓选择，Enter切换，Ctrl-B备份，Esc退出")
+	fmt.Println("  -history [版本] 查看切换和备份的历史记录（时间、用户、主机、版本、备份、结果、耗时）")
+	fmt.Println("  -history-limit <数量> 与 -history 一起使用，显示的记录条数，默认20，0表示全部")
+	fmt.Println("  -undo      撤销最近一次切换，使用切换前的备份恢复环境变量，可重复执行依次撤销更早的切换")
+	fmt.Println("  -link-mode <on|off> 开启或关闭链接模式：JAVA_HOME和PATH固定指向配置目录下的current链接，切换时只修改链接")
+	fmt.Println("  -version-homes <on|off> 开启或关闭主版本环境变量：为每个主版本设置一个系统环境变量（如 JAVA_8_HOME、JAVA_17_HOME），JDK条目变化后自动同步")
+	fmt.Println("  -version-home-name <模板> 与 -version-homes on 一起使用，变量名称模板，${major} 为主版本号，默认 JAVA_${major}_HOME")
 	fmt.Println("  -backup    仅备份当前环境变量，不切换JDK版本")
+	fmt.Println("  -add <路径> 添加JDK，可用 -name <名称> 指定版本名称（默认根据release文件生成）")
+	fmt.Println("  -remove <名称> 删除JDK，删除当前版本需要同时指定 -force")
+	fmt.Println("  -rename <旧名称> <新名称> 修改JDK版本名称")
+	fmt.Println("  -setpath <名称> <新路径> 修改JDK的安装路径")
+	fmt.Println("  -install <压缩包> 将zip或tar.gz压缩包中的JDK安装到配置目录下的jdks目录并添加，可用 -name 指定名称")
+	fmt.Println("  -checksum <文件> 与 -install 一起使用，指定校验和文件（默认查找同名的 .sha256、.sha512 文件）")
+	fmt.Println("  -uninstall <名称> 删除通过 -install 安装的JDK及其文件，删除当前版本需要同时指定 -force")
+	fmt.Println("  -import [来源...] 导入SDKMAN、jabba、asdf、mise、IntelliJ(~/.jdks)和Windows注册表中的JDK，默认扫描全部来源")
+	fmt.Println("  -dry-run   与 -import 一起使用，只显示将要导入和跳过的JDK，不修改配置")
+	fmt.Println("  -toolchains 根据配置生成或更新Maven的toolchains.xml")
+	fmt.Println("  -toolchains-file <路径> 指定toolchains.xml路径（默认 ~/.m2/toolchains.xml）")
+	fmt.Println("  -gradle    将JDK路径写入用户级gradle.properties的工具链安装路径")
+	fmt.Println("  -gradle-no-auto 与 -gradle 一起使用，关闭Gradle工具链的自动检测和自动下载")
+	fmt.Println("  -gradle-file <路径> 指定gradle.properties路径（默认 ~/.gradle/gradle.properties）")
+	fmt.Println("  -gradle-list 列出每个JDK可满足的Gradle工具链languageVersion")
+	fmt.Println("  -idea <IDE配置目录> 将JDK定义写入IntelliJ IDEA的jdk.table.xml")
+	fmt.Println("  -vscode    将JDK写入VS Code的java.configuration.runtimes设置")
+	fmt.Println("  -vscode-file <路径> 指定VS Code的settings.json路径")
+	fmt.Println("  -export <版本> 生成设置JAVA_HOME、PATH和附加环境变量的脚本env.bat、env.ps1、env.sh、env.fish，也可以指定配置方案")
+	fmt.Println("  -export-dir <目录> 与 -export 一起使用，脚本的输出目录（默认当前目录）")
+	fmt.Println("  -export-format <格式> 与 -export 一起使用，只生成指定格式的脚本，多个格式用逗号分隔，如 ps1,sh")
+	fmt.Println("  -export-relative 与 -export 一起使用，脚本中的路径相对于脚本所在目录，适合与JDK一起放入仓库")
+	fmt.Println("  -project [目录] 显示项目（包括所有模块）构建文件中声明的Java版本、模块之间的冲突和 -set auto 将选择的JDK")
+	fmt.Println("  -projects [目录] 扫描目录下的所有项目，显示每个项目需要的Java版本和将使用的JDK，以及没有满足要求的JDK的项目")
+	fmt.Println("  -depth <深度> 与 -projects 一起使用，查找项目的最大目录深度，默认3，-1表示不限制")
+	fmt.Println("  -json      与 -projects 一起使用，以JSON格式输出")
+	fmt.Println("  -run <jar> [参数...] 根据jar中class文件的版本选择满足要求的最低版本JDK运行jar，只对该进程设置JAVA_HOME和PATH，必须是第一个参数")
+	fmt.Println("  -inspect <jar> 显示jar的Main-Class、Multi-Release、Build-Jdk-Spec、class文件版本和需要的最低Java版本")
+	fmt.Println("  -use <版本> -ci 在CI流水线中使用本机已有的JDK：与setup-java相同地设置JAVA_HOME、JAVA_HOME_<主版本>_<架构>和PATH，不修改注册表和配置")
+	fmt.Println("  -ci-dotenv <文件> 与 -use -ci 一起使用，将变量写入指定的dotenv文件（GitLab CI中默认 jdk-switch.env）")
+	fmt.Println("  -serve     启动只监听本机的JSON接口（list、current、switch、backup和切换事件），令牌保存在config.json所在目录的api-token文件中")
+	fmt.Println("  -serve-addr <地址> 与 -serve 一起使用，监听地址，默认 " + api.DefaultAddr + "，只允许回环地址")
+	fmt.Println("  -rehash    根据所有已配置JDK中的工具重新生成shim，添加或删除JDK后需要重新执行")
 	fmt.Println("  -v         显示版本信息")
 	fmt.Println("  -h         显示帮助信息")
 	fmt.Println("\n不带参数运行将启动交互模式")
+	fmt.Println("\nshim:")
+	fmt.Printf("  将 %s 添加到PATH最前面后，java、javac等命令按以下顺序确定JDK:\n", jdk.ShimsDir())
+	fmt.Println("  当前目录及上级目录中的 .java-version、环境变量 JDK_SWITCH_VERSION、配置中的当前版本")
 	fmt.Println("\n环境变量备份信息:")
-	fmt.Println("  每次切换JDK版本时会自动备份当前的环境变量(PATH, JAVA_HOME, CLASSPATH)")
-	fmt.Println("  备份文件存储位置: C:\\jdk-switch\\backup\\时间戳")
+	fmt.Println("  每次切换JDK版本时会自动备份当前的环境变量(PATH, JAVA_HOME, CLASSPATH及配置中的附加环境变量)")
+	fmt.Printf("  备份文件存储位置: %s\n", filepath.Join(config.Dir(), "backup", "时间戳"))
+	fmt.Println("\n切换钩子:")
+	fmt.Printf("  可在配置文件的 hooks/jdk_hooks 中配置切换前后执行的命令，输出记录在 %s\n", filepath.Join(config.Dir(), "switch.log"))
 	fmt.Println("\n提示:")
 	fmt.Println("  切换JDK版本后，重新打开命令行窗口或重新登录系统，以确保新的Java版本生效")
 }
@@ -35,7 +97,12 @@ func askForInit() bool {
 	reader := bufio.NewReader(os.Stdin)
 	for {
 		fmt.Print("是否要初始化配置文件？(y/n): ")
-		input, _ := reader.ReadString('\n')
+		input, err := reader.ReadString('\n')
+		if err != nil && input == "" {
+			// 输入已结束（例如标准输入被重定向），视为不初始化
+			fmt.Println()
+			return false
+		}
 		input = strings.TrimSpace(strings.ToLower(input))
 
 		if input == "y" || input == "yes" {
@@ -48,11 +115,71 @@ func askForInit() bool {
 }
 
 func main() {
+	// 作为shim运行时，将参数原样传给解析出的JDK中的同名工具
+	if tool, ok := shim.Invoked(); ok {
+		os.Exit(shim.Run(tool, os.Args[1:]))
+	}
+	// -exec <工具> 供shim脚本调用，工具之后的参数不能按本程序的参数解析
+	if len(os.Args) > 2 && os.Args[1] == "-exec" {
+		os.Exit(shim.Run(os.Args[2], os.Args[3:]))
+	}
+	// -run <jar> 之后的参数原样传给jar
+	if len(os.Args) > 2 && os.Args[1] == "-run" {
+		os.Exit(runJar(os.Args[2], os.Args[3:]))
+	}
+
 	// 解析命令行参数
 	initFlag := flag.Bool("init", false, "初始化配置文件")
 	listFlag := flag.Bool("list", false, "列出所有可用的JDK版本")
-	setVersion := flag.String("set", "", "切换到指定的JDK版本")
+	setVersion := flag.String("set", "", "切换到指定的JDK版本或配置方案")
+	strictFlag := flag.Bool("strict", false, "与 -set 一起使用，JDK与项目的Gradle或Maven wrapper不兼容时拒绝切换")
+	verifyFlag := flag.Bool("verify", false, "切换后运行java -version和javac -version验证")
+	rollbackFlag := flag.Bool("rollback", false, "切换后验证，失败时切换回原版本")
+	archFlag := flag.String("arch", "", "与 -list 或 -set 一起使用，只考虑指定架构的JDK")
+	tuiFlag := flag.Bool("tui", false, "使用全屏界面选择要切换的JDK")
+	historyFlag := flag.Bool("history", false, "查看切换和备份的历史记录，可以在后面指定版本进行筛选")
+	historyLimit := flag.Int("history-limit", 20, "与 -history 一起使用，显示的记录条数，0表示全部")
+	undoFlag := flag.Bool("undo", false, "撤销最近一次切换，恢复切换前备份的环境变量")
+	linkMode := flag.String("link-mode", "", "开启(on)或关闭(off)链接模式")
 	backupFlag := flag.Bool("backup", false, "仅备份当前环境变量，不切换JDK版本")
+	addPath := flag.String("add", "", "添加指定路径的JDK")
+	nameFlag := flag.String("name", "", "与 -add 或 -install 一起使用，指定版本名称")
+	removeName := flag.String("remove", "", "删除指定的JDK")
+	forceFlag := flag.Bool("force", false, "与 -remove 一起使用，允许删除当前版本")
+	renameName := flag.String("rename", "", "修改JDK版本名称: -rename <旧名称> <新名称>")
+	setPathName := flag.String("setpath", "", "修改JDK的安装路径: -setpath <名称> <新路径>")
+	installArchive := flag.String("install", "", "从本地压缩包(zip、tar.gz)安装JDK")
+	checksumFile := flag.String("checksum", "", "与 -install 一起使用，指定校验和文件")
+	uninstallName := flag.String("uninstall", "", "卸载通过 -install 安装的JDK")
+	importFlag := flag.Bool("import", false, "导入其他工具安装的JDK，可以在后面指定来源")
+	dryRunFlag := flag.Bool("dry-run", false, "与 -import 一起使用，只显示将要导入的JDK")
+	toolchainsFlag := flag.Bool("toolchains", false, "根据配置生成或更新Maven的toolchains.xml")
+	toolchainsFile := flag.String("toolchains-file", "", "指定toolchains.xml路径")
+	gradleFlag := flag.Bool("gradle", false, "将JDK路径写入用户级gradle.properties")
+	gradleNoAuto := flag.Bool("gradle-no-auto", false, "关闭Gradle工具链的自动检测和自动下载")
+	gradleFile := flag.String("gradle-file", "", "指定gradle.properties路径")
+	gradleList := flag.Bool("gradle-list", false, "列出每个JDK可满足的Gradle工具链languageVersion")
+	ideaDir := flag.String("idea", "", "将JDK定义写入指定IntelliJ IDEA配置目录的jdk.table.xml")
+	vscodeFlag := flag.Bool("vscode", false, "将JDK写入VS Code的java.configuration.runtimes设置")
+	vscodeFile := flag.String("vscode-file", "", "指定VS Code的settings.json路径")
+	inspectJarFlag := flag.String("inspect", "", "分析jar文件需要的最低Java版本")
+	flag.String("run", "", "使用满足要求的最低版本JDK运行jar文件")
+	exportVersion := flag.String("export", "", "为指定的JDK版本或配置方案生成设置环境变量的脚本")
+	exportDir := flag.String("export-dir", "", "与 -export 一起使用，脚本的输出目录")
+	exportFormat := flag.String("export-format", "", "与 -export 一起使用，脚本格式(bat,ps1,sh,fish)")
+	exportRelative := flag.Bool("export-relative", false, "与 -export 一起使用，脚本中的路径相对于脚本所在目录")
+	projectFlag := flag.Bool("project", false, "分析项目构建文件中声明的Java版本，可以在后面指定项目目录")
+	projectsFlag := flag.Bool("projects", false, "扫描目录下的所有项目，显示每个项目需要的Java版本")
+	depthFlag := flag.Int("depth", 3, "与 -projects 一起使用，查找项目的最大目录深度，-1表示不限制")
+	jsonFlag := flag.Bool("json", false, "与 -projects 一起使用，以JSON格式输出")
+	useVersion := flag.String("use", "", "与 -ci 一起使用，在CI流水线中使用指定的JDK版本或配置方案")
+	ciFlag := flag.Bool("ci", false, "与 -use 一起使用，写入GitHub Actions的环境文件或GitLab CI的dotenv文件，不修改注册表和配置")
+	ciDotenv := flag.String("ci-dotenv", "", "与 -use -ci 一起使用，指定dotenv文件（GitLab CI中默认 jdk-switch.env）")
+	versionHomes := flag.String("version-homes", "", "开启(on)或关闭(off)主版本环境变量，例如 JAVA_17_HOME")
+	versionHomeName := flag.String("version-home-name", "", "与 -version-homes on 一起使用，主版本环境变量的名称模板")
+	serveFlag := flag.Bool("serve", false, "启动只监听本机的JSON接口，供IDE插件等工具列出JDK和切换版本")
+	serveAddr := flag.String("serve-addr", api.DefaultAddr, "与 -serve 一起使用，监听地址，只允许回环地址")
+	rehashFlag := flag.Bool("rehash", false, "根据所有JDK中的工具重新生成shim")
 	versionFlag := flag.Bool("v", false, "显示版本信息")
 	helpFlag := flag.Bool("h", false, "显示帮助信息")
 	flag.Parse()
@@ -71,20 +198,51 @@ func main() {
 
 	// 如果是备份环境变量命令
 	if *backupFlag {
-		if err := jdk.BackupEnvironmentVariables(); err != nil {
+		if _, err := backupEnvironment(); err != nil {
 			fmt.Printf("备份环境变量失败: %v\n", err)
 			return
 		}
 		return
 	}
 
+	// 查看历史记录
+	if *historyFlag {
+		if err := showHistory(flag.Arg(0), *historyLimit); err != nil {
+			fmt.Printf("读取历史记录失败: %v\n", err)
+		}
+		return
+	}
+
+	// 撤销最近一次切换
+	if *undoFlag {
+		if err := undoLastSwitch(); err != nil {
+			fmt.Printf("撤销失败: %v\n", err)
+			return
+		}
+		fmt.Println("已撤销，请重新打开命令行窗口使环境变量生效")
+		return
+	}
+
+	// 在CI中使用JDK，失败时返回非0退出码使流水线的步骤失败
+	if *useVersion != "" || *ciFlag {
+		if *useVersion == "" || !*ciFlag {
+			fmt.Fprintln(os.Stderr, "错误: -use 需要与 -ci 一起使用，例如 jdk-switch -use 17 -ci")
+			os.Exit(2)
+		}
+		if err := useInCI(*useVersion, *archFlag, *ciDotenv); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// 如果是初始化命令
 	if *initFlag {
 		if err := config.InitDefaultConfig(); err != nil {
 			fmt.Printf("初始化配置失败: %v\n", err)
 			return
 		}
-		fmt.Printf("配置文件已初始化，路径: %s\\%s\n", config.DefaultDir, config.DefaultFile)
+		fmt.Printf("配置文件已初始化，路径: %s\n", config.Path())
 		fmt.Println("请根据实际情况修改JDK路径")
 		return
 	}
@@ -93,7 +251,7 @@ func main() {
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		if os.IsNotExist(err) {
-			fmt.Printf("未找到配置文件: %s\\%s\n", config.DefaultDir, config.DefaultFile)
+			fmt.Printf("未找到配置文件: %s\n", config.Path())
 
 			// 询问用户是否要初始化配置
 			if askForInit() {
@@ -101,7 +259,7 @@ func main() {
 					fmt.Printf("初始化配置失败: %v\n", err)
 					return
 				}
-				fmt.Printf("配置文件已初始化，路径: %s\\%s\n", config.DefaultDir, config.DefaultFile)
+				fmt.Printf("配置文件已初始化，路径: %s\n", config.Path())
 				fmt.Println("请根据实际情况修改JDK路径后重新运行程序")
 				return
 			}
@@ -112,96 +270,534 @@ func main() {
 		return
 	}
 
+	// 切换选项，命令行参数和配置文件任一开启即生效
+	switchOpts := switchOptions{
+		verify:   *verifyFlag || *rollbackFlag || cfg.VerifyAfterSwitch || cfg.RollbackOnVerifyFailure,
+		rollback: *rollbackFlag || cfg.RollbackOnVerifyFailure,
+	}
+
+	// 开启或关闭主版本环境变量
+	if *versionHomes != "" {
+		if err := setVersionHomes(*versionHomes, *versionHomeName); err != nil {
+			fmt.Printf("错误: %v\n", err)
+		}
+		return
+	}
+
+	// 启动JSON接口
+	if *serveFlag {
+		if err := serveAPI(*serveAddr); err != nil {
+			fmt.Printf("错误: %v\n", err)
+		}
+		return
+	}
+
+	// 重新生成shim
+	if *rehashFlag {
+		if err := rehashShims(cfg); err != nil {
+			fmt.Printf("错误: %v\n", err)
+		}
+		return
+	}
+
+	// 分析项目构建文件
+	if *projectFlag {
+		if err := showProject(cfg, flag.Arg(0)); err != nil {
+			fmt.Printf("错误: %v\n", err)
+		}
+		return
+	}
+
+	// 生成环境变量脚本
+	if *exportVersion != "" {
+		if err := exportScripts(cfg, *exportVersion, *exportDir, *exportFormat, *exportRelative); err != nil {
+			fmt.Printf("错误: %v\n", err)
+		}
+		return
+	}
+
+	// 扫描工作区中的项目
+	if *projectsFlag {
+		if err := scanProjects(cfg, flag.Arg(0), *depthFlag, *jsonFlag); err != nil {
+			fmt.Printf("错误: %v\n", err)
+		}
+		return
+	}
+
+	// 分析jar文件
+	if *inspectJarFlag != "" {
+		if err := inspectJar(cfg, *inspectJarFlag); err != nil {
+			fmt.Printf("错误: %v\n", err)
+		}
+		return
+	}
+
+	// 开启或关闭链接模式
+	if *linkMode != "" {
+		if err := setLinkMode(cfg, *linkMode, switchOpts); err != nil {
+			fmt.Printf("错误: %v\n", err)
+		}
+		return
+	}
+
 	// 列出所有JDK版本
 	if *listFlag {
-		fmt.Printf("当前JDK版本: %s\n", cfg.CurrentVersion)
-		fmt.Println("可用的JDK版本:")
-		for version := range cfg.JDKPaths {
-			if version == cfg.CurrentVersion {
-				fmt.Printf("* JDK %s: %s (当前)\n", version, cfg.JDKPaths[version])
-			} else {
-				fmt.Printf("  JDK %s: %s\n", version, cfg.JDKPaths[version])
-			}
+		listJDKs(cfg, *archFlag)
+		listProfiles(cfg)
+		return
+	}
+
+	// 管理JDK条目
+	if *addPath != "" || *removeName != "" || *renameName != "" || *setPathName != "" || *importFlag || *installArchive != "" || *uninstallName != "" {
+		var err error
+		switch {
+		case *installArchive != "":
+			err = installJDK(*installArchive, *checksumFile, *nameFlag)
+		case *uninstallName != "":
+			err = uninstallJDK(*uninstallName, *forceFlag)
+		case *importFlag:
+			err = importJDKs(cfg, flag.Args(), *dryRunFlag)
+		case *addPath != "":
+			err = addJDK(*addPath, *nameFlag)
+		case *removeName != "":
+			err = removeJDK(*removeName, *forceFlag)
+		case *renameName != "":
+			err = renameJDK(*renameName, flag.Arg(0))
+		default:
+			err = setJDKPath(*setPathName, flag.Arg(0))
+		}
+		if err != nil {
+			fmt.Printf("错误: %v\n", err)
 		}
 		return
 	}
 
+	// 同步Maven toolchains.xml
+	if *toolchainsFlag {
+		if *toolchainsFile != "" {
+			cfg.MavenToolchainsFile = *toolchainsFile
+		}
+		if err := syncMavenToolchains(cfg); err != nil {
+			fmt.Printf("错误: %v\n", err)
+		}
+		return
+	}
+
+	// 写入Gradle工具链安装路径
+	if *gradleFlag {
+		if err := syncGradleProperties(cfg, *gradleFile, *gradleNoAuto); err != nil {
+			fmt.Printf("错误: %v\n", err)
+		}
+		return
+	}
+
+	// 列出Gradle工具链languageVersion
+	if *gradleList {
+		listGradleLanguageVersions(cfg)
+		return
+	}
+
+	// 导出到IntelliJ IDEA
+	if *ideaDir != "" {
+		path, backup, err := ide.ExportIntelliJ(cfg, *ideaDir)
+		if err != nil {
+			fmt.Printf("导出到IntelliJ IDEA失败: %v\n", err)
+			return
+		}
+		fmt.Printf("已将 %d 个JDK写入 %s，请重启IDE使其生效\n", len(cfg.JDKPaths), path)
+		if backup != "" {
+			fmt.Printf("原文件已备份到 %s\n", backup)
+		}
+		return
+	}
+
+	// 导出到VS Code
+	if *vscodeFlag {
+		if err := exportVSCode(cfg, *vscodeFile); err != nil {
+			fmt.Printf("导出到VS Code失败: %v\n", err)
+		}
+		return
+	}
+
+	// 根据当前目录的项目选择JDK
+	if *setVersion == autoVersion && cfg.JDKPaths[autoVersion] == "" && cfg.Profiles[autoVersion] == nil {
+		dir, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("错误: 无法获取当前目录: %v\n", err)
+			return
+		}
+		target, profile, err := resolveAuto(cfg, dir, *archFlag)
+		if err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		if err := checkWrappers(cfg, target, *strictFlag); err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		switchOpts.profile = profile
+		if err := switchJDK(cfg, target, switchOpts); err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		fmt.Printf("成功切换到JDK %s\n", target)
+		return
+	}
+
+	// 切换到指定的配置方案
+	if profile := cfg.Profiles[*setVersion]; profile != nil {
+		if err := checkWrappers(cfg, profile.JDK, *strictFlag); err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		switchOpts.profile = *setVersion
+		if err := switchJDK(cfg, profile.JDK, switchOpts); err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		fmt.Printf("成功切换到配置方案 %s (JDK %s)\n", *setVersion, profile.JDK)
+		return
+	}
+
 	// 切换到指定版本
 	if *setVersion != "" {
-		if err := switchJDK(cfg, *setVersion); err != nil {
+		target, err := resolveVersion(cfg, *setVersion, *archFlag)
+		if err != nil {
 			fmt.Printf("错误: %v\n", err)
 			return
 		}
-		fmt.Printf("成功切换到JDK %s\n", *setVersion)
+		if err := checkWrappers(cfg, target, *strictFlag); err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		if err := switchJDK(cfg, target, switchOpts); err != nil {
+			fmt.Printf("错误: %v\n", err)
+			return
+		}
+		fmt.Printf("成功切换到JDK %s\n", target)
+		return
+	}
+
+	// 全屏选择界面
+	if *tuiFlag {
+		if err := runPicker(cfg, switchOpts); err != nil {
+			fmt.Printf("错误: %v\n", err)
+		}
 		return
 	}
 
 	// 交互模式
-	fmt.Println("JDK Switch Tool v" + version)
-	fmt.Printf("当前JDK版本: %s\n", cfg.CurrentVersion)
+	runInteractive(cfg, switchOpts)
+}
+
+// currentLabel 返回显示用的当前版本，当前版本被删除后提示重新切换
+func currentLabel(cfg *config.Config) string {
+	if cfg.CurrentVersion == "" {
+		return "未设置（请使用 -set 切换）"
+	}
+	return cfg.CurrentVersion
+}
+
+// listJDKs 列出配置中的JDK及其版本和架构，arch非空时只列出该架构的JDK
+func listJDKs(cfg *config.Config, arch string) {
+	fmt.Printf("当前JDK版本: %s (系统架构: %s)\n", currentLabel(cfg), jdk.HostArch())
+	printLinkStatus(cfg)
 	fmt.Println("可用的JDK版本:")
-	for version := range cfg.JDKPaths {
-		if version == cfg.CurrentVersion {
-			fmt.Printf("* JDK %s: %s (当前)\n", version, cfg.JDKPaths[version])
-		} else {
-			fmt.Printf("  JDK %s: %s\n", version, cfg.JDKPaths[version])
+	for _, install := range jdk.Installations(cfg.JDKPaths) {
+		archDesc := "架构未知"
+		info, err := jdk.DetectArch(install.Path)
+		if err == nil {
+			archDesc = info.String()
+		}
+		if arch != "" && (err != nil || info.Arch != jdk.NormalizeArch(arch)) {
+			continue
+		}
+
+		marker, suffix := " ", ""
+		if install.Key == cfg.CurrentVersion {
+			marker, suffix = "*", " (当前)"
+		}
+		report := jdk.ValidateInstallation(install.Path)
+		fmt.Printf("%s JDK %s: %s%s  [%s, %s, %s]\n", marker, install.Key, install.Path, suffix, install.Version(), archDesc, report.Kind)
+
+		var results []string
+		for _, check := range report.Checks {
+			mark := "✓"
+			if !check.OK {
+				mark = "✗"
+			}
+			results = append(results, check.Name+mark)
+		}
+		fmt.Printf("    检查: %s\n", strings.Join(results, " "))
+		for _, problem := range report.Problems() {
+			fmt.Printf("    问题: %s\n", problem)
 		}
 	}
+}
 
-	// 读取用户输入
-	reader := bufio.NewReader(os.Stdin)
-	for {
-		fmt.Print("\n请输入要切换的JDK版本 (输入 'b' 备份环境变量, 输入 'q' 退出): ")
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(input)
+// rehashShims 重新生成shim，shim目录不在PATH中时提示添加
+func rehashShims(cfg *config.Config) error {
+	tools, err := shim.Rehash(cfg)
+	if err != nil {
+		return err
+	}
+	dir := jdk.ShimsDir()
+	fmt.Printf("已在 %s 生成 %d 个shim: %s\n", dir, len(tools), strings.Join(tools, " "))
 
-		if input == "q" {
-			break
+	// Windows上检查系统级PATH，其他平台检查当前进程的PATH
+	pathValue, err := jdk.GetSystemEnvVarFromRegistry("Path")
+	if err != nil {
+		pathValue = os.Getenv("PATH")
+	}
+	for _, entry := range filepath.SplitList(pathValue) {
+		if filepath.Clean(entry) == filepath.Clean(dir) || runtime.GOOS == "windows" && strings.EqualFold(filepath.Clean(entry), filepath.Clean(dir)) {
+			return nil
 		}
+	}
+	fmt.Printf("提示: 请将 %s 添加到PATH的最前面，shim才会生效\n", dir)
+	return nil
+}
 
-		if input == "b" {
-			if err := jdk.BackupEnvironmentVariables(); err != nil {
-				fmt.Printf("备份环境变量失败: %v\n", err)
-				continue
-			}
-			continue
+// printLinkStatus 链接模式下显示current链接的指向，与当前版本不一致时给出提示
+func printLinkStatus(cfg *config.Config) {
+	if !cfg.LinkMode {
+		return
+	}
+	target, err := jdk.ReadCurrentLink()
+	switch {
+	case err != nil:
+		fmt.Printf("链接模式: %v\n", err)
+	case target == "":
+		fmt.Printf("链接模式: %s 尚未创建，请使用 -set 切换一次\n", jdk.CurrentLinkPath())
+	default:
+		fmt.Printf("链接模式: %s -> %s\n", jdk.CurrentLinkPath(), target)
+		if current := cfg.JDKPaths[cfg.CurrentVersion]; current != "" && filepath.Clean(current) != filepath.Clean(target) {
+			fmt.Printf("警告: current链接没有指向当前版本 %s (%s)\n", cfg.CurrentVersion, current)
 		}
+	}
+}
 
-		if err := switchJDK(cfg, input); err != nil {
-			fmt.Printf("错误: %v\n", err)
-			continue
+// setLinkMode 开启或关闭链接模式，并立即按新的模式重新切换到当前版本
+func setLinkMode(cfg *config.Config, mode string, opts switchOptions) error {
+	var enabled bool
+	switch strings.ToLower(mode) {
+	case "on":
+		enabled = true
+	case "off":
+		enabled = false
+	default:
+		return fmt.Errorf("用法: -link-mode on|off")
+	}
+
+	updated, err := config.Update(func(c *config.Config) error {
+		c.LinkMode = enabled
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if enabled {
+		fmt.Printf("已开启链接模式，JAVA_HOME将指向 %s\n", jdk.CurrentLinkPath())
+	} else {
+		fmt.Println("已关闭链接模式，JAVA_HOME将直接指向JDK目录")
+	}
+
+	// 重新切换一次，使环境变量改为使用（或不再使用）current链接
+	if updated.CurrentVersion == "" {
+		fmt.Println("请使用 -set 切换到一个JDK版本")
+		return nil
+	}
+	opts.profile = updated.CurrentProfile
+	if err := switchJDK(updated, updated.CurrentVersion, opts); err != nil {
+		return err
+	}
+	fmt.Printf("已按新的模式切换到JDK %s\n", updated.CurrentVersion)
+	return nil
+}
+
+// listProfiles 列出配置方案及其使用的JDK、附加环境变量和PATH条目
+func listProfiles(cfg *config.Config) {
+	if len(cfg.Profiles) == 0 {
+		return
+	}
+	var names []string
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("可用的配置方案:")
+	for _, name := range names {
+		profile := cfg.Profiles[name]
+		marker, suffix := " ", ""
+		if name == cfg.CurrentProfile {
+			marker, suffix = "*", " (当前)"
+		}
+		fmt.Printf("%s %s: JDK %s%s\n", marker, name, profile.JDK, suffix)
+
+		var envNames []string
+		for envName := range profile.Env {
+			envNames = append(envNames, envName)
+		}
+		sort.Strings(envNames)
+		if len(envNames) > 0 {
+			fmt.Printf("    环境变量: %s\n", strings.Join(envNames, ", "))
 		}
+		for _, entry := range profile.Path {
+			fmt.Printf("    PATH: %s\n", entry)
+		}
+	}
+}
 
-		fmt.Printf("成功切换到JDK %s\n", input)
+// validateJDK 检查路径是否为完整的JDK，不是时返回包含分类和失败检查项的错误
+func validateJDK(path string) error {
+	report := jdk.ValidateInstallation(path)
+	if report.Kind == jdk.KindJDK {
+		return nil
 	}
+	problems := report.Problems()
+	if report.Kind == jdk.KindJRE {
+		problems = append(problems, "缺少javac，这是一个JRE")
+	}
+	return fmt.Errorf("无效的JDK路径 - %s (%s): %s", path, report.Kind, strings.Join(problems, "; "))
+}
+
+// resolveVersion 将用户输入的版本查询解析为配置中的版本名称
+// 优先完全匹配版本名称，否则按完整版本号或主版本号匹配；arch非空时只考虑该架构的JDK
+func resolveVersion(cfg *config.Config, query, arch string) (string, error) {
+	if _, exists := cfg.JDKPaths[query]; exists && arch == "" {
+		return query, nil
+	}
+
+	matches := jdk.MatchInstallations(jdk.Installations(cfg.JDKPaths), query, arch)
+	switch len(matches) {
+	case 0:
+		if arch != "" {
+			return "", fmt.Errorf("没有架构为 %s 的JDK版本 %s", arch, query)
+		}
+		return "", fmt.Errorf("JDK版本 %s 不存在", query)
+	case 1:
+		return matches[0].Key, nil
+	}
+
+	var keys []string
+	for _, match := range matches {
+		keys = append(keys, match.Key)
+	}
+	return "", fmt.Errorf("版本 %s 匹配到多个JDK: %s，请使用完整的版本名称", query, strings.Join(keys, ", "))
+}
+
+// switchOptions 控制switchJDK的可选行为
+type switchOptions struct {
+	// verify 切换后在新环境中运行java和javac验证版本
+	verify bool
+	// rollback 验证失败时切换回原来的版本
+	rollback bool
+	// profile 通过配置方案切换时的方案名称，附加环境变量和PATH条目取自该方案
+	profile string
 }
 
 // 切换JDK版本的通用函数
-func switchJDK(cfg *config.Config, version string) error {
+func switchJDK(cfg *config.Config, version string, opts switchOptions) (err error) {
+	// 无论成功与否都写入历史记录
+	record := history.Entry{
+		Action:      history.ActionSwitch,
+		From:        cfg.CurrentVersion,
+		To:          version,
+		FromProfile: cfg.CurrentProfile,
+		Profile:     opts.profile,
+	}
+	start := time.Now()
+	defer func() { recordHistory(record, start, err) }()
+
 	// 获取对应的JDK路径
 	jdkPath, err := cfg.GetJDKPath(version)
 	if err != nil {
 		return err
 	}
 
-	// 验证JDK路径
-	if !jdk.ValidateJDKPath(jdkPath) {
-		return fmt.Errorf("无效的JDK路径 - %s", jdkPath)
+	// 验证JDK安装目录
+	if err := validateJDK(jdkPath); err != nil {
+		return err
+	}
+
+	// 检查JDK架构是否与当前系统一致
+	if info, err := jdk.DetectArch(jdkPath); err == nil && info.Arch != jdk.HostArch() {
+		fmt.Printf("警告: JDK %s 的架构为 %s，与当前系统架构 %s 不一致\n", version, info, jdk.HostArch())
+	}
+
+	// 打开切换日志，钩子输出会记录在其中
+	switchLog := openSwitchLog()
+	defer switchLog.Close()
+	fmt.Fprintf(switchLog, "==== %s 切换JDK %s -> %s\n", time.Now().Format("2006-01-02 15:04:05"), cfg.CurrentVersion, version)
+
+	hookCtx := hook.Context{
+		OldVersion:  cfg.CurrentVersion,
+		NewVersion:  version,
+		OldJavaHome: cfg.JDKPaths[cfg.CurrentVersion],
+		NewJavaHome: jdkPath,
+	}
+
+	// 执行切换前钩子，失败时中止切换
+	hookCtx.Phase = hook.PhasePre
+	if err := hook.Run(cfg.PreHooks(version), hookCtx, switchLog); err != nil {
+		fmt.Fprintf(switchLog, "切换已中止: %v\n", err)
+		return fmt.Errorf("切换前钩子失败，已中止切换: %v", err)
 	}
 
 	// 切换JDK
-	if err := jdk.SetJavaHome(jdkPath); err != nil {
-		return fmt.Errorf("切换JDK失败: %v", err)
+	if err := applySwitch(cfg, version, jdkPath, opts, &record); err != nil {
+		fmt.Fprintf(switchLog, "切换失败: %v\n", err)
+		return err
 	}
+	oldVersion, oldProfile := record.From, record.FromProfile
+	fmt.Fprintln(switchLog, "切换成功")
 
-	// 更新当前版本
-	if err := cfg.UpdateCurrentVersion(version); err != nil {
-		return fmt.Errorf("更新配置失败: %v", err)
+	// config.json可能被直接修改过，记录的主版本环境变量与JDK条目不一致时同步
+	if versionHomesStale(cfg) {
+		if updated, err := syncVersionHomes(cfg); err != nil {
+			fmt.Printf("警告: 同步主版本环境变量失败: %v\n", err)
+		} else {
+			*cfg = *updated
+		}
 	}
 
-	// 保存配置
-	if err := cfg.SaveConfig(); err != nil {
-		return fmt.Errorf("保存配置失败: %v", err)
+	// 按配置自动同步Maven toolchains.xml
+	if cfg.SyncMavenToolchains {
+		if err := syncMavenToolchains(cfg); err != nil {
+			fmt.Printf("警告: %v\n", err)
+		}
+	}
+
+	// 执行切换后钩子，失败时仅提示，不影响切换结果
+	hookCtx.Phase = hook.PhasePost
+	if err := hook.Run(cfg.PostHooks(version), hookCtx, switchLog); err != nil {
+		fmt.Printf("警告: 切换后钩子失败: %v\n", err)
+	}
+
+	// 在新环境中实际运行java和javac，确认切换生效
+	if opts.verify {
+		// 链接模式下JAVA_HOME和PATH指向current链接
+		home := jdkPath
+		if cfg.LinkMode {
+			home = jdk.CurrentLinkPath()
+		}
+		if err := verifySwitch(version, home, switchLog); err != nil {
+			if !opts.rollback || oldVersion == "" || oldVersion == version && oldProfile == opts.profile {
+				return err
+			}
+			fmt.Printf("%v\n正在切换回原版本 %s...\n", err, oldVersion)
+			fmt.Fprintf(switchLog, "验证失败，回滚到 %s\n", oldVersion)
+			if rollbackErr := switchJDK(cfg, oldVersion, switchOptions{profile: oldProfile}); rollbackErr != nil {
+				return fmt.Errorf("%v；回滚到 %s 也失败了: %v", err, oldVersion, rollbackErr)
+			}
+			return fmt.Errorf("%v；已回滚到JDK %s", err, oldVersion)
+		}
+	}
+
+	if cfg.LinkMode {
+		fmt.Println("\ncurrent链接已更新，JAVA_HOME已指向该链接的命令行窗口会立即使用新的JDK")
+		return nil
 	}
 
 	// 添加简洁明确的提示信息
@@ -211,3 +807,226 @@ func switchJDK(cfg *config.Config, version string) error {
 
 	return nil
 }
+
+// verifySwitch 在切换后的环境中运行java -version和javac -version，并打印检查结果
+func verifySwitch(version, jdkPath string, switchLog io.Writer) error {
+	env, err := jdk.SwitchedEnvironment(jdkPath)
+	if err != nil {
+		return fmt.Errorf("无法获取切换后的环境变量: %v", err)
+	}
+
+	result := jdk.VerifySwitch(jdkPath, env)
+	fmt.Println("\n切换验证:")
+	fmt.Printf("  java:  %s (%s)\n", result.JavaVersion, result.JavaPath)
+	fmt.Printf("  javac: %s (%s)\n", result.JavacVersion, result.JavacPath)
+	if result.Expected != "" {
+		fmt.Printf("  期望版本: %s\n", result.Expected)
+	}
+	fmt.Fprintf(switchLog, "验证: java %s (%s), javac %s (%s)\n", result.JavaVersion, result.JavaPath, result.JavacVersion, result.JavacPath)
+
+	if result.OK() {
+		fmt.Println("  验证通过")
+		return nil
+	}
+	for _, problem := range result.Problems {
+		fmt.Printf("  问题: %s\n", problem)
+		fmt.Fprintf(switchLog, "验证问题: %s\n", problem)
+	}
+	return fmt.Errorf("切换后验证失败: 新打开的命令行窗口中的java不是JDK %s", version)
+}
+
+// syncMavenToolchains 将配置中的所有JDK同步到Maven的toolchains.xml
+func syncMavenToolchains(cfg *config.Config) error {
+	path := cfg.MavenToolchainsFile
+	if path == "" {
+		defaultPath, err := buildtool.MavenToolchainsPath()
+		if err != nil {
+			return err
+		}
+		path = defaultPath
+	}
+
+	backup, err := buildtool.SyncMavenToolchains(path, jdk.Installations(cfg.JDKPaths))
+	if err != nil {
+		return fmt.Errorf("同步toolchains.xml失败: %v", err)
+	}
+
+	fmt.Printf("已同步 %d 个JDK到 %s\n", len(cfg.JDKPaths), path)
+	if backup != "" {
+		fmt.Printf("原文件已备份到 %s\n", backup)
+	}
+	return nil
+}
+
+// syncGradleProperties 将配置中的所有JDK路径写入gradle.properties
+func syncGradleProperties(cfg *config.Config, path string, disableAuto bool) error {
+	if path == "" {
+		defaultPath, err := buildtool.GradlePropertiesPath()
+		if err != nil {
+			return err
+		}
+		path = defaultPath
+	}
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+	}
+
+	// 只恢复本工具之前在同一文件中关闭的自动检测和自动下载
+	restoreAuto := cfg.GradleNoAutoFile != "" && fsutil.NormalizePath(cfg.GradleNoAutoFile) == fsutil.NormalizePath(path)
+	props := buildtool.GradleInstallationProperties(jdk.Installations(cfg.JDKPaths), disableAuto, restoreAuto)
+	backup, err := buildtool.SyncGradleProperties(path, props)
+	if err != nil {
+		return fmt.Errorf("更新gradle.properties失败: %v", err)
+	}
+
+	if disableAuto || restoreAuto {
+		if _, err := config.Update(func(c *config.Config) error {
+			c.GradleNoAutoFile = ""
+			if disableAuto {
+				c.GradleNoAutoFile = path
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("更新配置失败: %v", err)
+		}
+	}
+
+	fmt.Printf("已将 %d 个JDK路径写入 %s\n", len(cfg.JDKPaths), path)
+	if disableAuto {
+		fmt.Println("已关闭Gradle工具链的自动检测和自动下载")
+	} else if restoreAuto {
+		fmt.Println("已恢复Gradle工具链的自动检测和自动下载")
+	}
+	if backup != "" {
+		fmt.Printf("原文件已备份到 %s\n", backup)
+	}
+	return nil
+}
+
+// listGradleLanguageVersions 列出每个JDK可满足的Gradle工具链languageVersion
+// Gradle按主版本号精确匹配 JavaLanguageVersion.of(N)
+func listGradleLanguageVersions(cfg *config.Config) {
+	fmt.Println("JDK与Gradle工具链languageVersion对应关系:")
+	for _, install := range jdk.Installations(cfg.JDKPaths) {
+		major := install.Major()
+		if major == 0 {
+			fmt.Printf("  JDK %s: 无法识别版本 (%s)\n", install.Key, install.Path)
+			continue
+		}
+		vendor := install.Vendor()
+		if vendor == "" {
+			vendor = "未知提供商"
+		}
+		fmt.Printf("  JDK %s: languageVersion = JavaLanguageVersion.of(%d)  [%s, %s]\n", install.Key, major, install.Version(), vendor)
+	}
+}
+
+// exportVSCode 将配置中的JDK写入VS Code的settings.json
+func exportVSCode(cfg *config.Config, path string) error {
+	if path == "" {
+		defaultPath, err := ide.VSCodeSettingsPath()
+		if err != nil {
+			return err
+		}
+		path = defaultPath
+	}
+
+	backup, err := ide.ExportVSCode(cfg, path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("已将JDK写入 %s 的 java.configuration.runtimes\n", path)
+	if backup != "" {
+		fmt.Printf("原文件已备份到 %s\n", backup)
+	}
+	return nil
+}
+
+// openSwitchLog 以追加方式打开配置目录下的切换日志，打开失败时返回丢弃输出的日志
+func openSwitchLog() io.WriteCloser {
+	file, err := os.OpenFile(filepath.Join(config.Dir(), "switch.log"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		fmt.Printf("警告: 无法打开切换日志: %v\n", err)
+		return nopWriteCloser{io.Discard}
+	}
+	return file
+}
+
+// applySwitch 修改系统环境变量并保存切换结果，期间持有切换锁，同时进行的切换（如命令行和 -serve）依次执行
+// 持有锁后重新读取配置，需要清除的附加环境变量和PATH条目以及record中切换前的版本都以其他进程最后保存的结果为准
+func applySwitch(cfg *config.Config, version, jdkPath string, opts switchOptions, record *history.Entry) error {
+	unlock, err := config.LockSwitch()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if path, err := current.GetJDKPath(version); err != nil || path != jdkPath {
+		return fmt.Errorf("JDK版本 %s 已被其他进程修改或删除，请重新切换", version)
+	}
+	*cfg = *current
+	record.From, record.FromProfile = cfg.CurrentVersion, cfg.CurrentProfile
+
+	envOpts, err := switchEnvOptions(cfg, version, opts.profile)
+	if err != nil {
+		return err
+	}
+	backupID, err := jdk.SetJavaHome(jdkPath, envOpts)
+	record.BackupID = backupID
+	if err != nil {
+		return fmt.Errorf("切换JDK失败: %v", err)
+	}
+
+	// 记录本次设置的附加环境变量和PATH条目（下次切换时用于清理）和当前版本
+	// 在配置文件锁的保护下重新读取后只修改这些字段，不会覆盖其他进程同时写入的其他内容
+	managedEnv := config.ManagedEnvNames(envOpts.ExtraEnv)
+	updated, err := config.Update(func(c *config.Config) error {
+		c.ManagedEnv = managedEnv
+		c.ManagedPath = envOpts.AddPath
+		c.CurrentProfile = opts.profile
+		return c.UpdateCurrentVersion(version)
+	})
+	if err != nil {
+		return fmt.Errorf("更新配置失败: %v", err)
+	}
+	*cfg = *updated
+	return nil
+}
+
+// switchEnvOptions 计算切换到目标版本（或配置方案）时的附加环境变量和PATH条目，以及需要清除的旧附加环境变量和PATH条目
+// applySwitch和切换预览共用，预览与实际切换的结果保持一致
+func switchEnvOptions(cfg *config.Config, version, profile string) (jdk.SwitchOptions, error) {
+	extraEnv, addPath, err := cfg.SwitchEnv(version, profile)
+	if err != nil {
+		return jdk.SwitchOptions{}, err
+	}
+	return jdk.SwitchOptions{
+		ExtraEnv:   extraEnv,
+		UnsetEnv:   cfg.UnsetEnvNamesFor(extraEnv),
+		AddPath:    addPath,
+		RemovePath: cfg.ManagedPath,
+		Link:       cfg.LinkMode,
+		BackupEnv:  cfg.VersionHomeNames(),
+	}, nil
+}
+
+// nopWriteCloser 为不需要关闭的Writer提供空的Close方法
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// backupExtraNames 返回需要额外备份的附加环境变量和主版本环境变量名称，配置无法加载时只备份固定的环境变量
+func backupExtraNames() []string {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil
+	}
+	return append(cfg.ExtraEnvNames(), cfg.VersionHomeNames()...)
+}
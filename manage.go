@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"switch/config"
+	"switch/importer"
+	"switch/installer"
+	"switch/jdk"
+)
+
+// addJDK 添加JDK条目，未指定名称时根据JDK的release文件生成默认名称
+func addJDK(path, name string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("无法解析路径 %s: %v", path, err)
+	}
+	if err := validateJDK(absPath); err != nil {
+		return err
+	}
+
+	updated, err := config.Update(func(c *config.Config) error {
+		if name == "" {
+			name = jdk.SuggestKey(absPath, c.NameTaken)
+		}
+		return c.AddJDK(name, absPath)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("已添加JDK %s: %s\n", name, absPath)
+	syncVersionHomesAfterChange(updated)
+	return nil
+}
+
+// removeJDK 删除JDK条目，删除当前版本需要force
+func removeJDK(name string, force bool) error {
+	var removedCurrent bool
+	updated, err := config.Update(func(c *config.Config) error {
+		removedCurrent = c.CurrentVersion == name
+		return c.RemoveJDK(name, force)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("已删除JDK %s\n", name)
+	if removedCurrent {
+		fmt.Println("注意: 删除的是当前版本，系统环境变量仍指向该JDK，请使用 -set 切换到其他版本")
+	}
+	syncVersionHomesAfterChange(updated)
+	return nil
+}
+
+// installJDK 从本地压缩包安装JDK并添加条目，未指定名称时根据release文件生成
+func installJDK(archive, checksumFile, name string) error {
+	result, err := installer.Install(archive, checksumFile)
+	if err != nil {
+		return err
+	}
+	if result.Checksum != "" {
+		fmt.Printf("%s校验通过\n", result.Checksum)
+	} else {
+		fmt.Println("未找到校验和文件，跳过校验")
+	}
+
+	updated, err := config.Update(func(c *config.Config) error {
+		if name == "" {
+			name = jdk.SuggestKey(result.Home, c.NameTaken)
+		}
+		return c.AddJDK(name, result.Home)
+	})
+	if err != nil {
+		// 添加条目失败时不保留未登记的安装
+		if removeErr := installer.Remove(result.Root); removeErr != nil {
+			fmt.Printf("警告: %v\n", removeErr)
+		}
+		return err
+	}
+
+	fmt.Printf("已安装JDK %s: %s\n", name, result.Home)
+	syncVersionHomesAfterChange(updated)
+	return nil
+}
+
+// uninstallJDK 删除通过 -install 安装的JDK条目和文件，手动添加的JDK只能用 -remove 删除条目
+func uninstallJDK(name string, force bool) error {
+	var path string
+	var removedCurrent bool
+	updated, err := config.Update(func(c *config.Config) error {
+		var exists bool
+		if path, exists = c.JDKPaths[name]; !exists {
+			return fmt.Errorf("JDK版本 %s 不存在", name)
+		}
+		if !installer.IsManaged(path) {
+			return fmt.Errorf("JDK %s 不是通过 -install 安装的，请使用 -remove 删除条目", name)
+		}
+		removedCurrent = c.CurrentVersion == name
+		return c.RemoveJDK(name, force)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := installer.Remove(path); err != nil {
+		return err
+	}
+	fmt.Printf("已卸载JDK %s\n", name)
+	if removedCurrent {
+		fmt.Println("注意: 卸载的是当前版本，系统环境变量仍指向该JDK，请使用 -set 切换到其他版本")
+	}
+	syncVersionHomesAfterChange(updated)
+	return nil
+}
+
+// renameJDK 修改JDK版本名称
+func renameJDK(oldName, newName string) error {
+	if newName == "" {
+		return fmt.Errorf("用法: -rename <旧名称> <新名称>")
+	}
+	updated, err := config.Update(func(c *config.Config) error {
+		return c.RenameJDK(oldName, newName)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("已将JDK %s 重命名为 %s\n", oldName, newName)
+	syncVersionHomesAfterChange(updated)
+	return nil
+}
+
+// setJDKPath 修改JDK条目的路径
+func setJDKPath(name, path string) error {
+	if path == "" {
+		return fmt.Errorf("用法: -setpath <名称> <新路径>")
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("无法解析路径 %s: %v", path, err)
+	}
+	if err := validateJDK(absPath); err != nil {
+		return err
+	}
+
+	var isCurrent bool
+	updated, err := config.Update(func(c *config.Config) error {
+		isCurrent = c.CurrentVersion == name
+		return c.SetJDKPath(name, absPath)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("已将JDK %s 的路径修改为 %s\n", name, absPath)
+	if isCurrent {
+		fmt.Printf("注意: 这是当前版本，请重新执行 -set %s 使系统环境变量生效\n", name)
+	}
+	syncVersionHomesAfterChange(updated)
+	return nil
+}
+
+// importJDKs 扫描其他工具安装的JDK并添加到配置，已配置的路径和无效的目录会被跳过
+func importJDKs(cfg *config.Config, sources []string, dryRun bool) error {
+	candidates, scanErr := importer.Scan(importer.DefaultEnv(), sources...)
+	if scanErr != nil {
+		if len(candidates) == 0 {
+			return scanErr
+		}
+		fmt.Printf("警告: %v\n", scanErr)
+	}
+	if len(candidates) == 0 {
+		fmt.Printf("没有找到可导入的JDK，支持的来源: %s\n", strings.Join(importer.SourceNames(), ", "))
+		return nil
+	}
+
+	var actions []importer.Action
+	var updated *config.Config
+	if dryRun {
+		actions = importer.Plan(cfg, candidates)
+	} else {
+		// 在配置锁内重新计算，避免与其他进程同时修改时名称冲突
+		var err error
+		updated, err = config.Update(func(c *config.Config) error {
+			actions = importer.Plan(c, candidates)
+			for _, action := range actions {
+				if action.Skip != "" {
+					continue
+				}
+				if err := c.AddJDK(action.Key, action.Path); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	imported := 0
+	for _, action := range actions {
+		if action.Skip != "" {
+			fmt.Printf("  跳过 [%s] %s: %s\n", action.Source, action.Path, action.Skip)
+			continue
+		}
+		imported++
+		fmt.Printf("+ %s: %s [%s %s]\n", action.Key, action.Path, action.Source, action.ID)
+	}
+	if dryRun {
+		fmt.Printf("将导入 %d 个JDK（未修改配置）\n", imported)
+	} else {
+		fmt.Printf("已导入 %d 个JDK\n", imported)
+		syncVersionHomesAfterChange(updated)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"switch/config"
+	"switch/jdk"
+	"switch/term"
+	"switch/tui"
+)
+
+// runPicker 运行全屏选择界面，选择后通过switchJDK切换，与 -set 的行为一致
+func runPicker(cfg *config.Config, opts switchOptions) error {
+	status := ""
+	for {
+		picker := tui.NewPicker(pickerItems(cfg))
+		picker.Preview = func(item tui.Item) []string {
+			return switchPreview(cfg, item.Key, opts.profile)
+		}
+		picker.Status = status
+		if width, height, err := term.Size(os.Stdout); err == nil && width > 0 && height > 0 {
+			picker.Width, picker.Height = width, height
+		}
+
+		restore, err := term.MakeRaw(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("全屏界面需要在终端中运行: %v", err)
+		}
+		action, err := picker.Run(os.Stdin, os.Stdout)
+		restore()
+		if err != nil {
+			return err
+		}
+
+		switch action.Kind {
+		case tui.ActionSwitch:
+			if err := switchJDK(cfg, action.Item.Key, opts); err != nil {
+				return err
+			}
+			fmt.Printf("成功切换到JDK %s\n", action.Item.Key)
+			return nil
+		case tui.ActionBackup:
+			// 备份在普通屏幕中执行，输出保留在终端中，完成后回到选择界面
+			if _, err := backupEnvironment(); err != nil {
+				status = fmt.Sprintf("备份环境变量失败: %v", err)
+			} else {
+				status = "环境变量已备份"
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// pickerItems 将配置中的JDK转换为选择界面的列表项，按主版本号排序
+func pickerItems(cfg *config.Config) []tui.Item {
+	var items []tui.Item
+	for _, install := range jdk.Installations(cfg.JDKPaths) {
+		arch := ""
+		if info, err := jdk.DetectArch(install.Path); err == nil {
+			arch = info.Arch
+		}
+		items = append(items, tui.Item{
+			Key:     install.Key,
+			Version: install.Version(),
+			Vendor:  install.Vendor(),
+			Arch:    arch,
+			Path:    install.Path,
+			Current: install.Key == cfg.CurrentVersion,
+		})
+	}
+	return items
+}
+
+// switchPreview 描述切换到version（或配置方案）后JAVA_HOME和PATH的变化，PATH与applySwitch使用相同的计算
+func switchPreview(cfg *config.Config, version, profile string) []string {
+	jdkPath := cfg.JDKPaths[version]
+	var lines []string
+	home := jdkPath
+	if cfg.LinkMode {
+		// 链接模式下只修改current链接，JAVA_HOME和PATH使用链接路径
+		target, _ := jdk.ReadCurrentLink()
+		if target == "" {
+			target = "(无)"
+		}
+		home = jdk.CurrentLinkPath()
+		lines = append(lines, fmt.Sprintf("current链接: %s -> %s", target, jdkPath), "JAVA_HOME: "+home)
+	} else {
+		lines = append(lines, fmt.Sprintf("JAVA_HOME: %s -> %s", cfg.JDKPaths[cfg.CurrentVersion], jdkPath))
+	}
+	if report := jdk.ValidateInstallation(jdkPath); report.Kind != jdk.KindJDK {
+		lines = append(lines, fmt.Sprintf("警告: 该目录不是有效的JDK (%s)", report.Kind))
+	}
+
+	// Windows上比较系统级PATH，其他平台使用当前进程的PATH
+	pathValue, err := jdk.GetSystemEnvVarFromRegistry("Path")
+	if err != nil {
+		pathValue = os.Getenv("PATH")
+	}
+	envOpts, err := switchEnvOptions(cfg, version, profile)
+	if err != nil {
+		return append(lines, fmt.Sprintf("PATH: 无法计算 (%v)", err))
+	}
+	removed, added := jdk.DiffPath(pathValue, jdk.SwitchedPath(pathValue, jdkPath, envOpts))
+	if len(removed) == 0 && len(added) == 0 {
+		return append(lines, "PATH: 无变化")
+	}
+	lines = append(lines, "PATH:")
+	for _, entry := range removed {
+		lines = append(lines, "  - "+entry)
+	}
+	for _, entry := range added {
+		lines = append(lines, "  + "+entry)
+	}
+	return lines
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"switch/config"
+	"switch/jdk"
+	"switch/project"
+	"switch/shim"
+)
+
+// autoVersion 是 -set 的特殊值，根据当前目录的项目选择JDK
+const autoVersion = "auto"
+
+// resolveAuto 根据dir中构建文件声明的Java版本选择满足要求的最低版本JDK，
+// 构建文件中没有声明时使用 .java-version，返回版本名称和配置方案名称
+func resolveAuto(cfg *config.Config, dir, arch string) (string, string, error) {
+	a, err := project.Analyze(dir)
+	if err != nil {
+		return "", "", err
+	}
+	if a.HasRequirement() {
+		for _, conflict := range a.Conflicts {
+			fmt.Printf("警告: %s\n", describeConflict(conflict))
+		}
+		if !a.Satisfiable() {
+			return "", "", fmt.Errorf("构建文件中的Java版本要求相互冲突，请使用 -project 查看详情")
+		}
+		install, ok := a.Choose(filterArch(jdk.Installations(cfg.JDKPaths), arch))
+		if !ok {
+			return "", "", fmt.Errorf("没有满足要求的JDK: 项目需要 %s", a)
+		}
+		fmt.Printf("根据%s构建文件，项目需要 %s，选择JDK %s\n", a.Build, a, install.Key)
+		return install.Key, "", nil
+	}
+
+	query, file, err := shim.FindVersionFile(dir)
+	if err != nil {
+		return "", "", err
+	}
+	if query == "" {
+		return "", "", fmt.Errorf("无法确定项目需要的JDK: 构建文件中没有声明Java版本，也没有找到 %s", shim.VersionFile)
+	}
+	fmt.Printf("根据 %s 选择 %s\n", file, query)
+	if profile := cfg.Profiles[query]; profile != nil {
+		return profile.JDK, query, nil
+	}
+	target, err := resolveVersion(cfg, query, arch)
+	return target, "", err
+}
+
+// filterArch 只保留指定架构的JDK，arch为空时不过滤
+func filterArch(installs []jdk.Installation, arch string) []jdk.Installation {
+	if arch == "" {
+		return installs
+	}
+	var filtered []jdk.Installation
+	for _, install := range installs {
+		if info, err := jdk.DetectArch(install.Path); err == nil && info.Arch == jdk.NormalizeArch(arch) {
+			filtered = append(filtered, install)
+		}
+	}
+	return filtered
+}
+
+// describeConflict 描述两项无法同时满足的要求
+func describeConflict(c project.Conflict) string {
+	return fmt.Sprintf("%s 的 %s 要求 %s，与 %s 的 %s 要求 %s 冲突",
+		c.A.Module, c.A.Setting, c.A, c.B.Module, c.B.Setting, c.B)
+}
+
+// showProject 显示项目构建文件中的Java版本要求和 -set auto 将会选择的JDK
+func showProject(cfg *config.Config, dir string) error {
+	if dir == "" {
+		var err error
+		if dir, err = os.Getwd(); err != nil {
+			return fmt.Errorf("无法获取当前目录: %v", err)
+		}
+	}
+	a, err := project.Analyze(dir)
+	if err != nil {
+		return err
+	}
+
+	switch a.Build {
+	case "":
+		fmt.Printf("%s 中没有pom.xml或Gradle构建脚本\n", dir)
+	default:
+		fmt.Printf("%s (%s, %d 个模块)\n", dir, a.Build, len(a.Modules))
+	}
+	for _, r := range a.Requirements {
+		fmt.Printf("  %-12s %-34s %-10s %s\n", r.Module, r.Setting, r.Value, r)
+	}
+	for _, warning := range a.Warnings {
+		fmt.Printf("  警告: %s\n", warning)
+	}
+	wrappers, err := project.FindWrappers(dir)
+	if err != nil {
+		return err
+	}
+	for _, w := range wrappers {
+		fmt.Printf("  wrapper: %s (%s)\n", w.Describe(), w.File)
+	}
+
+	if !a.HasRequirement() {
+		if a.Build != "" {
+			fmt.Println("构建文件中没有声明Java版本")
+		}
+		query, file, err := shim.FindVersionFile(dir)
+		if err != nil {
+			return err
+		}
+		if query == "" {
+			fmt.Printf("也没有找到 %s，-set auto 无法确定JDK\n", shim.VersionFile)
+			return nil
+		}
+		fmt.Printf("%s 指定: %s\n", file, query)
+		return nil
+	}
+
+	// 各模块要求的最低版本不同时，整个项目需要其中最高的版本
+	mins := a.ModuleMinimums()
+	if distinct := distinctValues(mins); len(distinct) > 1 {
+		var modules []string
+		for module := range mins {
+			modules = append(modules, module)
+		}
+		sort.Strings(modules)
+		fmt.Println("各模块要求的最低版本不一致:")
+		for _, module := range modules {
+			fmt.Printf("  %-12s Java %d\n", module, mins[module])
+		}
+	}
+	for _, conflict := range a.Conflicts {
+		fmt.Printf("冲突: %s\n", describeConflict(conflict))
+	}
+
+	fmt.Printf("项目需要: %s\n", a)
+	if !a.Satisfiable() {
+		fmt.Println("-set auto 将选择: 没有JDK能同时满足所有要求")
+		return nil
+	}
+	if install, ok := a.Choose(jdk.Installations(cfg.JDKPaths)); ok {
+		fmt.Printf("-set auto 将选择: %s (%s)\n", install.Key, install.Path)
+	} else {
+		fmt.Println("-set auto 将选择: 没有满足要求的JDK")
+	}
+	return nil
+}
+
+// distinctValues 返回map中不同值的集合
+func distinctValues(m map[string]int) map[int]bool {
+	values := make(map[int]bool)
+	for _, v := range m {
+		values[v] = true
+	}
+	return values
+}
+
+// checkWrappers 检查当前目录所在项目的Gradle、Maven wrapper能否运行在目标JDK上，
+// 不兼容时给出警告并建议兼容的最高版本JDK，strict为true时返回错误拒绝切换
+func checkWrappers(cfg *config.Config, version string, strict bool) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	wrappers, err := project.FindWrappers(dir)
+	if err != nil {
+		fmt.Printf("警告: %v\n", err)
+		return nil
+	}
+
+	install := jdk.Installation{Key: version, Path: cfg.JDKPaths[version]}
+	if release, err := jdk.ReadRelease(install.Path); err == nil {
+		install.Release = release
+	}
+	major := install.Major()
+	if major == 0 {
+		return nil
+	}
+
+	unsupported := false
+	for _, w := range wrappers {
+		if !w.Supports(major) {
+			unsupported = true
+			fmt.Printf("警告: %s 使用 %s，不支持JDK %s (Java %d)\n", w.File, w.Describe(), version, major)
+		}
+	}
+	if !unsupported {
+		return nil
+	}
+
+	// 从高到低找出所有wrapper都支持的JDK
+	installs := jdk.Installations(cfg.JDKPaths)
+	suggestion := ""
+	for i := len(installs) - 1; i >= 0 && suggestion == ""; i-- {
+		compatible := installs[i].Major() > 0
+		for _, w := range wrappers {
+			compatible = compatible && w.Supports(installs[i].Major())
+		}
+		if compatible {
+			suggestion = installs[i].Key
+		}
+	}
+	if suggestion != "" {
+		fmt.Printf("建议使用兼容的JDK %s: jdk-switch -set %s\n", suggestion, suggestion)
+	} else {
+		fmt.Println("没有与项目构建工具兼容的JDK")
+	}
+	if strict {
+		return fmt.Errorf("JDK %s 与项目的构建工具不兼容，已取消切换", version)
+	}
+	return nil
+}
+
+// projectReport 工作区中一个项目的扫描结果
+type projectReport struct {
+	Path  string `json:"path"`
+	Build string `json:"build,omitempty"`
+	// Source 版本要求的来源：build（构建文件）或 .java-version 文件路径，无法确定时为空
+	Source      string   `json:"source,omitempty"`
+	Requirement string   `json:"requirement,omitempty"`
+	MinVersion  int      `json:"min_version,omitempty"`
+	JDK         string   `json:"jdk,omitempty"`
+	Status      string   `json:"status"`
+	Problems    []string `json:"problems,omitempty"`
+}
+
+// 项目扫描结果的状态
+const (
+	projectOK            = "ok"
+	projectUnsatisfiable = "unsatisfiable"
+	projectUnknown       = "unknown"
+	projectError         = "error"
+)
+
+// inspectProject 确定项目需要的Java版本和将会使用的JDK，与 -set auto 的规则相同
+func inspectProject(cfg *config.Config, installs []jdk.Installation, dir string) projectReport {
+	report := projectReport{Path: dir, Status: projectOK}
+	a, err := project.Analyze(dir)
+	if err != nil {
+		report.Status, report.Problems = projectError, []string{err.Error()}
+		return report
+	}
+	report.Build = a.Build
+	report.Problems = a.Warnings
+	for _, conflict := range a.Conflicts {
+		report.Problems = append(report.Problems, describeConflict(conflict))
+	}
+
+	if a.HasRequirement() {
+		report.Source, report.Requirement, report.MinVersion = "build", a.String(), a.Min
+		if install, ok := a.Choose(installs); ok {
+			report.JDK = install.Key
+		} else {
+			report.Status = projectUnsatisfiable
+		}
+		return report
+	}
+
+	query, file, err := shim.FindVersionFile(dir)
+	switch {
+	case err != nil:
+		report.Status, report.Problems = projectError, append(report.Problems, err.Error())
+		return report
+	case query == "":
+		report.Status = projectUnknown
+		return report
+	}
+	report.Source, report.Requirement, report.MinVersion = file, query, jdk.MajorVersion(query)
+	if profile := cfg.Profiles[query]; profile != nil {
+		report.JDK = profile.JDK
+	} else if target, err := resolveVersion(cfg, query, ""); err == nil {
+		report.JDK = target
+	} else {
+		report.Status, report.Problems = projectUnsatisfiable, append(report.Problems, err.Error())
+	}
+	if report.JDK != "" && report.MinVersion == 0 {
+		for _, install := range installs {
+			if install.Key == report.JDK {
+				report.MinVersion = install.Major()
+			}
+		}
+	}
+	return report
+}
+
+// scanProjects 查找root下的所有项目，按表格或JSON显示每个项目需要的Java版本和将会使用的JDK
+func scanProjects(cfg *config.Config, root string, depth int, asJSON bool) error {
+	if root == "" {
+		var err error
+		if root, err = os.Getwd(); err != nil {
+			return fmt.Errorf("无法获取当前目录: %v", err)
+		}
+	}
+	dirs, err := project.FindProjects(root, depth)
+	if err != nil {
+		return err
+	}
+
+	installs := jdk.Installations(cfg.JDKPaths)
+	reports := make([]projectReport, 0, len(dirs))
+	byVersion := make(map[int]int)
+	counts := make(map[string]int)
+	for _, dir := range dirs {
+		report := inspectProject(cfg, installs, dir)
+		if rel, err := filepath.Rel(root, dir); err == nil {
+			report.Path = filepath.ToSlash(rel)
+		}
+		reports = append(reports, report)
+		counts[report.Status]++
+		if report.MinVersion > 0 {
+			byVersion[report.MinVersion]++
+		}
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"root":       root,
+			"projects":   reports,
+			"by_version": byVersion,
+			"counts":     counts,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(reports) == 0 {
+		fmt.Printf("%s 下没有找到项目\n", root)
+		return nil
+	}
+	fmt.Printf("%-40s %-8s %-16s %s\n", "项目", "构建", "需要", "JDK")
+	for _, report := range reports {
+		build, requirement, choice := report.Build, report.Requirement, report.JDK
+		if build == "" {
+			build = "-"
+		}
+		if report.Source != "" && report.Source != "build" {
+			requirement = shim.VersionFile + ": " + requirement
+		}
+		switch report.Status {
+		case projectUnknown:
+			requirement, choice = "未知", "-"
+		case projectUnsatisfiable:
+			choice = "没有满足要求的JDK"
+		case projectError:
+			choice = "分析失败"
+		}
+		fmt.Printf("%-40s %-8s %-16s %s\n", report.Path, build, requirement, choice)
+		for _, problem := range report.Problems {
+			fmt.Printf("    %s\n", problem)
+		}
+	}
+
+	var versions []int
+	for v := range byVersion {
+		versions = append(versions, v)
+	}
+	sort.Ints(versions)
+	fmt.Printf("\n共 %d 个项目", len(reports))
+	for _, v := range versions {
+		fmt.Printf("，Java %d: %d 个", v, byVersion[v])
+	}
+	fmt.Println()
+	if n := counts[projectUnsatisfiable]; n > 0 {
+		fmt.Printf("%d 个项目没有满足要求的JDK\n", n)
+	}
+	if n := counts[projectUnknown]; n > 0 {
+		fmt.Printf("%d 个项目无法确定需要的Java版本\n", n)
+	}
+	if n := counts[projectError]; n > 0 {
+		fmt.Printf("%d 个项目分析失败\n", n)
+	}
+	return nil
+}
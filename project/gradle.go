@@ -0,0 +1,121 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Gradle构建脚本和设置脚本的文件名（Groovy和Kotlin DSL），以及识别版本设置的正则表达式
+var (
+	gradleScripts         = []string{"build.gradle", "build.gradle.kts"}
+	gradleSettings        = []string{"settings.gradle", "settings.gradle.kts"}
+	gradleComments        = regexp.MustCompile(`(?s)/\*.*?\*/|//[^\n]*`)
+	gradleInclude         = regexp.MustCompile(`(?m)^\s*include\b\s*\(?([^\n]*)`)
+	gradleQuoted          = regexp.MustCompile(`["']([^"']+)["']`)
+	gradleSettingPatterns = []struct {
+		setting string
+		exact   bool
+		re      *regexp.Regexp
+	}{
+		{"toolchain.languageVersion", true, regexp.MustCompile(`languageVersion\s*(?:=|\.set\s*\()\s*JavaLanguageVersion\.of\s*\(\s*["']?([\d.]+)`)},
+		{"jvmToolchain", true, regexp.MustCompile(`jvmToolchain\s*\(\s*(\d+)`)},
+		{"sourceCompatibility", false, regexp.MustCompile(`sourceCompatibility\s*(?:=|\.set\s*\()?\s*(?:JavaVersion\.(VERSION_[\d_]+)|JavaVersion\.toVersion\s*\(\s*["']?([\d.]+)|["']?([\d.]+))`)},
+		{"targetCompatibility", false, regexp.MustCompile(`targetCompatibility\s*(?:=|\.set\s*\()?\s*(?:JavaVersion\.(VERSION_[\d_]+)|JavaVersion\.toVersion\s*\(\s*["']?([\d.]+)|["']?([\d.]+))`)},
+		{"options.release", false, regexp.MustCompile(`options\.release\s*(?:=|\.set\s*\()\s*(\d+)`)},
+	}
+)
+
+// gradleBuild 判断目录是否为Gradle项目
+func gradleBuild(dir string) bool {
+	return findFile(dir, gradleScripts) != "" || findFile(dir, gradleSettings) != ""
+}
+
+// findFile 返回dir中第一个存在的文件
+func findFile(dir string, names []string) string {
+	for _, name := range names {
+		if path := filepath.Join(dir, name); exists(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// analyzeGradle 分析根项目和settings.gradle中include的子项目的构建脚本
+func analyzeGradle(a *Analysis) error {
+	dirs := []string{a.Dir}
+	if settings := findFile(a.Dir, gradleSettings); settings != "" {
+		data, err := os.ReadFile(settings)
+		if err != nil {
+			return fmt.Errorf("读取 %s 失败: %v", settings, err)
+		}
+		for _, path := range gradleIncludes(string(data)) {
+			dir := filepath.Join(a.Dir, filepath.FromSlash(path))
+			if findFile(dir, gradleScripts) == "" && !exists(dir) {
+				a.warn("%s: 找不到子项目 %s", settings, path)
+				continue
+			}
+			dirs = append(dirs, dir)
+		}
+	}
+
+	for _, dir := range dirs {
+		a.Modules = append(a.Modules, a.moduleName(dir))
+		script := findFile(dir, gradleScripts)
+		if script == "" {
+			continue
+		}
+		data, err := os.ReadFile(script)
+		if err != nil {
+			return fmt.Errorf("读取 %s 失败: %v", script, err)
+		}
+		analyzeGradleScript(a, a.moduleName(dir), script, string(data))
+	}
+	return nil
+}
+
+// gradleIncludes 返回settings脚本中include的项目路径，":app:core" 对应目录 app/core
+func gradleIncludes(settings string) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	settings = gradleComments.ReplaceAllString(settings, "")
+	for _, match := range gradleInclude.FindAllStringSubmatch(settings, -1) {
+		for _, quoted := range gradleQuoted.FindAllStringSubmatch(match[1], -1) {
+			path := strings.ReplaceAll(strings.TrimPrefix(quoted[1], ":"), ":", "/")
+			if path != "" && !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
+}
+
+// analyzeGradleScript 从构建脚本中查找工具链和源码兼容性设置
+// 只识别常见的字面量写法，通过变量或属性设置的版本无法识别
+func analyzeGradleScript(a *Analysis, module, file, script string) {
+	script = gradleComments.ReplaceAllString(script, "")
+	for _, s := range gradleSettingPatterns {
+		for _, match := range s.re.FindAllStringSubmatch(script, -1) {
+			value := ""
+			for _, group := range match[1:] {
+				if group != "" {
+					value = group
+					break
+				}
+			}
+			major := parseVersion(value)
+			if major == 0 {
+				a.warn("%s: 无法识别 %s 的值 %q", file, s.setting, value)
+				continue
+			}
+			r := Requirement{Module: module, File: file, Setting: s.setting, Value: value, Min: major}
+			if s.exact {
+				r.Max = major + 1
+			}
+			a.add(r)
+		}
+	}
+}
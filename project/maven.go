@@ -0,0 +1,208 @@
+package project
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// pom pom.xml中与Java版本有关的部分
+type pom struct {
+	Properties struct {
+		Entries []pomProperty `xml:",any"`
+	} `xml:"properties"`
+	Modules []string `xml:"modules>module"`
+	Build   struct {
+		Plugins          []pomPlugin `xml:"plugins>plugin"`
+		PluginManagement struct {
+			Plugins []pomPlugin `xml:"plugins>plugin"`
+		} `xml:"pluginManagement"`
+	} `xml:"build"`
+}
+
+type pomProperty struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type pomPlugin struct {
+	ArtifactID    string          `xml:"artifactId"`
+	Configuration pomPluginConfig `xml:"configuration"`
+	Executions    []struct {
+		Configuration pomPluginConfig `xml:"configuration"`
+	} `xml:"executions>execution"`
+}
+
+type pomPluginConfig struct {
+	Release string `xml:"release"`
+	Source  string `xml:"source"`
+	Target  string `xml:"target"`
+	// RequireJavaVersion maven-enforcer-plugin的requireJavaVersion规则
+	RequireJavaVersion string `xml:"rules>requireJavaVersion>version"`
+}
+
+// mavenProperties 作为Java版本要求记录的属性
+var mavenProperties = []string{"maven.compiler.release", "maven.compiler.source", "maven.compiler.target", "java.version"}
+
+// propertyRef 匹配 ${name} 形式的属性引用
+var propertyRef = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// analyzeMaven 从根目录的pom.xml开始，按modules递归分析所有模块
+// 子模块继承聚合它的pom中的属性
+func analyzeMaven(a *Analysis) error {
+	return analyzePom(a, a.Dir, nil, make(map[string]bool))
+}
+
+func analyzePom(a *Analysis, dir string, inherited map[string]string, visited map[string]bool) error {
+	file := filepath.Join(dir, "pom.xml")
+	if abs, err := filepath.Abs(file); err == nil {
+		if visited[abs] {
+			return nil
+		}
+		visited[abs] = true
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("读取 %s 失败: %v", file, err)
+	}
+	var p pom
+	if err := xml.Unmarshal(data, &p); err != nil {
+		return fmt.Errorf("解析 %s 失败: %v", file, err)
+	}
+
+	module := a.moduleName(dir)
+	a.Modules = append(a.Modules, module)
+
+	props := make(map[string]string)
+	for name, value := range inherited {
+		props[name] = value
+	}
+	for _, prop := range p.Properties.Entries {
+		props[prop.XMLName.Local] = strings.TrimSpace(prop.Value)
+	}
+
+	// 只记录本pom中声明的属性，继承的属性已在上级模块中记录
+	recorded := make(map[string]bool)
+	for _, prop := range p.Properties.Entries {
+		for _, name := range mavenProperties {
+			if prop.XMLName.Local == name {
+				addMaven(a, module, file, name, resolveProperties(props[name], props), false)
+				recorded[name] = true
+			}
+		}
+	}
+
+	plugins := append(p.Build.Plugins, p.Build.PluginManagement.Plugins...)
+	for _, plugin := range plugins {
+		configs := []pomPluginConfig{plugin.Configuration}
+		for _, execution := range plugin.Executions {
+			configs = append(configs, execution.Configuration)
+		}
+		for _, cfg := range configs {
+			switch plugin.ArtifactID {
+			case "maven-compiler-plugin":
+				settings := [][2]string{{"release", cfg.Release}, {"source", cfg.Source}, {"target", cfg.Target}}
+				for _, setting := range settings {
+					// 引用了已记录属性的值不重复记录
+					if match := propertyRef.FindStringSubmatch(strings.TrimSpace(setting[1])); match != nil && recorded[match[1]] {
+						continue
+					}
+					addMaven(a, module, file, "maven-compiler-plugin "+setting[0], resolveProperties(setting[1], props), false)
+				}
+			case "maven-enforcer-plugin":
+				addMaven(a, module, file, "requireJavaVersion", resolveProperties(cfg.RequireJavaVersion, props), true)
+			}
+		}
+	}
+
+	for _, name := range p.Modules {
+		child := filepath.Join(dir, filepath.FromSlash(strings.TrimSpace(name)))
+		// module也可以直接指向pom文件
+		if strings.HasSuffix(child, ".xml") {
+			child = filepath.Dir(child)
+		}
+		if !exists(filepath.Join(child, "pom.xml")) {
+			a.warn("%s: 找不到模块 %s", file, name)
+			continue
+		}
+		if err := analyzePom(a, child, props, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addMaven 记录一项Maven版本要求，isRange为true时value是enforcer的版本范围
+func addMaven(a *Analysis, module, file, setting, value string, isRange bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	r := Requirement{Module: module, File: file, Setting: setting, Value: value}
+	ok := true
+	if isRange {
+		r.Min, r.Max, ok = parseRange(value)
+	} else {
+		r.Min = parseVersion(value)
+		ok = r.Min > 0
+	}
+	if !ok {
+		a.warn("%s: 无法识别 %s 的值 %q", file, setting, value)
+		return
+	}
+	a.add(r)
+}
+
+// resolveProperties 替换值中的属性引用，无法解析的引用保持原样
+func resolveProperties(value string, props map[string]string) string {
+	for i := 0; i < 10 && strings.Contains(value, "${"); i++ {
+		value = propertyRef.ReplaceAllStringFunc(value, func(ref string) string {
+			if resolved, ok := props[ref[2:len(ref)-1]]; ok {
+				return resolved
+			}
+			return ref
+		})
+	}
+	return value
+}
+
+// parseRange 解析Maven的版本范围，如 "17"、"[1.8,)"、"[11,18)"、"(,12]"
+// 单独的版本号表示最低版本；有多个范围时只使用第一个范围的下限
+func parseRange(value string) (min, max int, ok bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || value[0] != '[' && value[0] != '(' {
+		min = parseVersion(value)
+		return min, 0, min > 0
+	}
+
+	end := strings.IndexAny(value, "])")
+	if end < 0 {
+		return 0, 0, false
+	}
+	lower, upper, hasComma := strings.Cut(value[1:end], ",")
+	multiple := strings.Contains(value[end+1:], ",")
+	if !hasComma {
+		// [17] 表示确切版本
+		min = parseVersion(lower)
+		return min, min + 1, min > 0
+	}
+	if strings.TrimSpace(lower) != "" {
+		if min = parseVersion(lower); min == 0 {
+			return 0, 0, false
+		}
+	}
+	if strings.TrimSpace(upper) != "" && !multiple {
+		if max = parseVersion(upper); max == 0 {
+			return 0, 0, false
+		}
+		// 包含上限时该主版本的所有更新版本都满足
+		if value[end] == ']' {
+			max++
+		}
+	}
+	return min, max, true
+}
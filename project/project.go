@@ -0,0 +1,199 @@
+// Package project 分析Maven和Gradle构建文件中声明的Java版本，推断构建项目需要的JDK
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"switch/jdk"
+)
+
+// 构建工具
+const (
+	BuildMaven  = "maven"
+	BuildGradle = "gradle"
+)
+
+// Requirement 构建文件中的一项Java版本要求
+type Requirement struct {
+	// Module 模块相对于项目目录的路径，根项目为 "."
+	Module string
+	// File 声明所在的构建文件
+	File string
+	// Setting 声明的设置名称，如 maven.compiler.release、toolchain.languageVersion
+	Setting string
+	// Value 构建文件中的原始值
+	Value string
+	// Min 需要的最低主版本号
+	Min int
+	// Max 不为0时主版本号必须低于Max，Gradle工具链要求的是确切的版本
+	Max int
+}
+
+// Satisfies 判断主版本号是否满足该要求
+func (r Requirement) Satisfies(major int) bool {
+	return major >= r.Min && (r.Max == 0 || major < r.Max)
+}
+
+// String 以易读的形式描述版本范围
+func (r Requirement) String() string {
+	return describeRange(r.Min, r.Max)
+}
+
+// Conflict 无法同时满足的两项要求
+type Conflict struct {
+	A, B Requirement
+}
+
+// Analysis 项目的分析结果
+type Analysis struct {
+	Dir string
+	// Build 构建工具，不是Maven或Gradle项目时为空
+	Build string
+	// Modules 参与分析的模块，根项目为 "."
+	Modules      []string
+	Requirements []Requirement
+	// Min、Max 合并所有要求后的版本范围，Max为0表示没有上限
+	Min, Max  int
+	Conflicts []Conflict
+	// Warnings 无法解析的值、找不到的模块等
+	Warnings []string
+}
+
+// Analyze 分析dir中的pom.xml或Gradle构建脚本，包括其中声明的所有子模块
+func Analyze(dir string) (*Analysis, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("无法访问项目目录: %v", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s 不是目录", dir)
+	}
+
+	a := &Analysis{Dir: dir}
+	switch {
+	case exists(filepath.Join(dir, "pom.xml")):
+		a.Build = BuildMaven
+		err = analyzeMaven(a)
+	case gradleBuild(dir):
+		a.Build = BuildGradle
+		err = analyzeGradle(a)
+	}
+	if err != nil {
+		return nil, err
+	}
+	a.combine()
+	return a, nil
+}
+
+// HasRequirement 判断构建文件中是否声明了Java版本
+func (a *Analysis) HasRequirement() bool {
+	return len(a.Requirements) > 0
+}
+
+// Satisfiable 判断所有要求能否由同一个JDK满足
+func (a *Analysis) Satisfiable() bool {
+	return a.Max == 0 || a.Min < a.Max
+}
+
+// Satisfies 判断主版本号是否满足所有要求
+func (a *Analysis) Satisfies(major int) bool {
+	return major >= a.Min && (a.Max == 0 || major < a.Max)
+}
+
+// String 以易读的形式描述合并后的版本范围
+func (a *Analysis) String() string {
+	return describeRange(a.Min, a.Max)
+}
+
+// ModuleMinimums 返回每个模块需要的最低主版本号
+func (a *Analysis) ModuleMinimums() map[string]int {
+	mins := make(map[string]int)
+	for _, r := range a.Requirements {
+		if r.Min > mins[r.Module] {
+			mins[r.Module] = r.Min
+		}
+	}
+	return mins
+}
+
+// Choose 在installs中选择满足所有要求的最低版本JDK
+func (a *Analysis) Choose(installs []jdk.Installation) (jdk.Installation, bool) {
+	if !a.Satisfiable() {
+		return jdk.Installation{}, false
+	}
+	install, ok := jdk.LowestSatisfying(installs, a.Min)
+	if !ok || !a.Satisfies(install.Major()) {
+		return jdk.Installation{}, false
+	}
+	return install, true
+}
+
+// add 记录一项要求
+func (a *Analysis) add(r Requirement) {
+	a.Requirements = append(a.Requirements, r)
+}
+
+// warn 记录一条警告
+func (a *Analysis) warn(format string, args ...interface{}) {
+	a.Warnings = append(a.Warnings, fmt.Sprintf(format, args...))
+}
+
+// combine 合并所有要求得到版本范围，并找出无法同时满足的要求
+func (a *Analysis) combine() {
+	for _, r := range a.Requirements {
+		if r.Min > a.Min {
+			a.Min = r.Min
+		}
+		if r.Max != 0 && (a.Max == 0 || r.Max < a.Max) {
+			a.Max = r.Max
+		}
+	}
+	for i, r := range a.Requirements {
+		for _, other := range a.Requirements[i+1:] {
+			if r.Max != 0 && other.Min >= r.Max || other.Max != 0 && r.Min >= other.Max {
+				a.Conflicts = append(a.Conflicts, Conflict{A: r, B: other})
+			}
+		}
+	}
+	sort.Strings(a.Modules)
+}
+
+// moduleName 返回模块目录相对于项目目录的路径
+func (a *Analysis) moduleName(dir string) string {
+	rel, err := filepath.Rel(a.Dir, dir)
+	if err != nil {
+		return dir
+	}
+	return filepath.ToSlash(rel)
+}
+
+// parseVersion 将 "1.8"、"8"、"17"、"VERSION_1_8" 等写法转换为主版本号，无法识别时返回0
+func parseVersion(value string) int {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "VERSION_")
+	value = strings.ReplaceAll(value, "_", ".")
+	if value == "" || value[0] < '0' || value[0] > '9' {
+		return 0
+	}
+	return jdk.MajorVersion(value)
+}
+
+// describeRange 描述版本范围，如 "Java 17+"、"Java 11"、"Java 8 ~ 10"
+func describeRange(min, max int) string {
+	switch {
+	case max == 0:
+		return fmt.Sprintf("Java %d+", min)
+	case max == min+1:
+		return fmt.Sprintf("Java %d", min)
+	case max <= min:
+		return fmt.Sprintf("Java %d+ 且低于 %d（无法满足）", min, max)
+	}
+	return fmt.Sprintf("Java %d ~ %d", min, max-1)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,247 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles 在dir中创建文件，键为相对路径
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("写入文件失败: %v", err)
+		}
+	}
+}
+
+func TestAnalyzeMaven(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"pom.xml": `<project>
+  <properties>
+    <java.version>11</java.version>
+    <maven.compiler.release>${java.version}</maven.compiler.release>
+  </properties>
+  <modules><module>core</module><module>web</module><module>missing</module></modules>
+  <build><plugins>
+    <plugin>
+      <artifactId>maven-compiler-plugin</artifactId>
+      <configuration><release>${maven.compiler.release}</release></configuration>
+    </plugin>
+    <plugin>
+      <artifactId>maven-enforcer-plugin</artifactId>
+      <executions><execution><configuration><rules>
+        <requireJavaVersion><version>[1.8,18)</version></requireJavaVersion>
+      </rules></configuration></execution></executions>
+    </plugin>
+  </plugins></build>
+</project>`,
+		"core/pom.xml": `<project><build><plugins><plugin>
+  <artifactId>maven-compiler-plugin</artifactId>
+  <configuration><source>1.8</source><target>1.8</target></configuration>
+</plugin></plugins></build></project>`,
+		"web/pom.xml": `<project><properties><maven.compiler.release>17</maven.compiler.release></properties></project>`,
+	})
+
+	a, err := Analyze(dir)
+	if err != nil {
+		t.Fatalf("分析失败: %v", err)
+	}
+	if a.Build != BuildMaven || strings.Join(a.Modules, ",") != ".,core,web" {
+		t.Errorf("模块错误: %s %v", a.Build, a.Modules)
+	}
+	// java.version、maven.compiler.release、requireJavaVersion、core的source和target、web的release
+	// 编译插件引用了已记录的属性，不重复记录
+	if len(a.Requirements) != 6 {
+		t.Errorf("期望6项要求，实际: %+v", a.Requirements)
+	}
+	if a.Min != 17 || a.Max != 18 || !a.Satisfiable() || a.String() != "Java 17" {
+		t.Errorf("合并结果错误: %d %d %s", a.Min, a.Max, a)
+	}
+	if mins := a.ModuleMinimums(); mins["."] != 11 || mins["core"] != 8 || mins["web"] != 17 {
+		t.Errorf("模块最低版本错误: %v", mins)
+	}
+	if len(a.Conflicts) != 0 {
+		t.Errorf("不应有冲突: %+v", a.Conflicts)
+	}
+	if len(a.Warnings) != 1 || !strings.Contains(a.Warnings[0], "missing") {
+		t.Errorf("期望找不到模块的警告: %v", a.Warnings)
+	}
+
+	// 子模块要求的版本超出了enforcer的范围
+	writeFiles(t, dir, map[string]string{
+		"web/pom.xml": `<project><properties><maven.compiler.release>21</maven.compiler.release></properties></project>`,
+	})
+	a, err = Analyze(dir)
+	if err != nil {
+		t.Fatalf("分析失败: %v", err)
+	}
+	if a.Satisfiable() || len(a.Conflicts) != 1 || a.Conflicts[0].B.Module != "web" {
+		t.Errorf("期望检测到冲突: %+v", a.Conflicts)
+	}
+}
+
+func TestAnalyzeGradle(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"settings.gradle.kts": `rootProject.name = "demo"
+// include(":old")
+include(":app", ":lib:core")
+include("tools")`,
+		"build.gradle.kts": `subprojects { apply(plugin = "java") }`,
+		"app/build.gradle.kts": `java {
+    toolchain {
+        languageVersion.set(JavaLanguageVersion.of(17))
+    }
+}`,
+		"lib/core/build.gradle": `java {
+    sourceCompatibility = JavaVersion.VERSION_1_8
+    targetCompatibility = '1.8'
+}
+tasks.withType(JavaCompile) { options.release = 11 }`,
+	})
+
+	a, err := Analyze(dir)
+	if err != nil {
+		t.Fatalf("分析失败: %v", err)
+	}
+	if a.Build != BuildGradle || strings.Join(a.Modules, ",") != ".,app,lib/core" {
+		t.Errorf("模块错误: %s %v", a.Build, a.Modules)
+	}
+	if len(a.Requirements) != 4 || a.String() != "Java 17" {
+		t.Errorf("分析结果错误: %s %+v", a, a.Requirements)
+	}
+	if len(a.Warnings) != 1 || !strings.Contains(a.Warnings[0], "tools") {
+		t.Errorf("期望找不到子项目的警告: %v", a.Warnings)
+	}
+
+	// 工具链要求确切的版本，与其他模块更高的版本冲突
+	writeFiles(t, dir, map[string]string{"lib/core/build.gradle": `java { sourceCompatibility = JavaVersion.VERSION_21 }`})
+	a, err = Analyze(dir)
+	if err != nil {
+		t.Fatalf("分析失败: %v", err)
+	}
+	if a.Satisfiable() || len(a.Conflicts) != 1 {
+		t.Errorf("期望检测到冲突: %+v", a.Conflicts)
+	}
+
+	empty := t.TempDir()
+	if a, err := Analyze(empty); err != nil || a.Build != "" || a.HasRequirement() {
+		t.Errorf("空目录分析结果错误: %+v %v", a, err)
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		value    string
+		min, max int
+		ok       bool
+	}{
+		{"17", 17, 0, true},
+		{"1.8", 8, 0, true},
+		{"[1.8,)", 8, 0, true},
+		{"[11,18)", 11, 18, true},
+		{"[11,17]", 11, 18, true},
+		{"(,12)", 0, 12, true},
+		{"[17]", 17, 18, true},
+		{"[1.8,9),[11,)", 8, 0, true},
+		{"${java.version}", 0, 0, false},
+	}
+	for _, tt := range tests {
+		min, max, ok := parseRange(tt.value)
+		if min != tt.min || max != tt.max || ok != tt.ok {
+			t.Errorf("%s: 期望 %d %d %v，实际 %d %d %v", tt.value, tt.min, tt.max, tt.ok, min, max, ok)
+		}
+	}
+}
+
+func TestWrappers(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"gradle/wrapper/gradle-wrapper.properties": "distributionUrl=https\\://services.gradle.org/distributions/gradle-7.2-bin.zip\n",
+		".mvn/wrapper/maven-wrapper.properties":    "distributionUrl=https://repo.maven.apache.org/maven2/org/apache/maven/apache-maven/4.0.0/apache-maven-4.0.0-bin.zip\n",
+		"app/src/Main.java":                        "",
+	})
+
+	// 从子目录向上查找
+	wrappers, err := FindWrappers(filepath.Join(dir, "app", "src"))
+	if err != nil {
+		t.Fatalf("查找wrapper失败: %v", err)
+	}
+	if len(wrappers) != 2 || wrappers[0].Version != "7.2" || wrappers[1].Version != "4.0.0" {
+		t.Fatalf("wrapper错误: %+v", wrappers)
+	}
+	gradle, maven := wrappers[0], wrappers[1]
+	if !gradle.Supports(16) || gradle.Supports(17) || gradle.Supports(7) {
+		t.Errorf("Gradle 7.2 应支持Java 8 ~ 16: %s", gradle.Describe())
+	}
+	if maven.Supports(11) || !maven.Supports(25) {
+		t.Errorf("Maven 4 需要Java 17: %s", maven.Describe())
+	}
+
+	tests := []struct {
+		version  string
+		min, max int
+		known    bool
+	}{
+		{"8.10.2", 8, 23, true},
+		{"8.9", 8, 22, true},
+		{"9.1.0", 17, 25, true},
+		{"1.12", 0, 0, false},
+		{"", 0, 0, false},
+	}
+	for _, tt := range tests {
+		min, max, known := Wrapper{Tool: BuildGradle, Version: tt.version}.JavaRange()
+		if min != tt.min || max != tt.max || known != tt.known {
+			t.Errorf("Gradle %s: 期望 %d %d %v，实际 %d %d %v", tt.version, tt.min, tt.max, tt.known, min, max, known)
+		}
+	}
+	if !(Wrapper{Tool: BuildGradle}).Supports(25) {
+		t.Error("版本未知时应视为支持")
+	}
+}
+
+func TestFindProjects(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"svc/pom.xml":                  "<project/>",
+		"svc/module/pom.xml":           "<project/>",
+		"legacy/.java-version":         "8\n",
+		"group/app/build.gradle.kts":   "",
+		"group/deep/er/pom.xml":        "<project/>",
+		"tools/node_modules/x/pom.xml": "<project/>",
+		"tools/target/classes/pom.xml": "<project/>",
+		".cache/repo/pom.xml":          "<project/>",
+		"notes/readme.txt":             "",
+	})
+
+	rel := func(dirs []string) string {
+		var names []string
+		for _, dir := range dirs {
+			name, _ := filepath.Rel(root, dir)
+			names = append(names, filepath.ToSlash(name))
+		}
+		return strings.Join(names, ",")
+	}
+
+	projects, err := FindProjects(root, 3)
+	if err != nil {
+		t.Fatalf("查找项目失败: %v", err)
+	}
+	// 子模块、构建输出目录和隐藏目录中的pom.xml不单独作为项目
+	if got := rel(projects); got != "group/app,group/deep/er,legacy,svc" {
+		t.Errorf("项目列表错误: %s", got)
+	}
+
+	projects, _ = FindProjects(root, 2)
+	if got := rel(projects); got != "group/app,legacy,svc" {
+		t.Errorf("深度限制无效: %s", got)
+	}
+}
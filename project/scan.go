@@ -0,0 +1,65 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// skipDirs 查找项目时跳过的构建输出和工具目录
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"target":       true,
+	"build":        true,
+	"out":          true,
+	"bin":          true,
+	"dist":         true,
+	"vendor":       true,
+}
+
+// projectMarkers 目录中存在这些文件之一时视为项目
+var projectMarkers = append(append([]string{"pom.xml", ".java-version"}, gradleScripts...), gradleSettings...)
+
+// IsProject 判断目录是否为Maven、Gradle项目或有 .java-version 文件
+func IsProject(dir string) bool {
+	return findFile(dir, projectMarkers) != ""
+}
+
+// FindProjects 在root下查找项目，maxDepth为查找的最大目录深度（root为0），小于0表示不限制
+// 找到项目后不再查找其子目录，子模块由 Analyze 分析；以 . 开头的目录和构建输出目录会被跳过
+func FindProjects(root string, maxDepth int) ([]string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("无法访问目录: %v", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s 不是目录", root)
+	}
+
+	var projects []string
+	var walk func(dir string, depth int)
+	walk = func(dir string, depth int) {
+		if IsProject(dir) {
+			projects = append(projects, dir)
+			return
+		}
+		if maxDepth >= 0 && depth >= maxDepth {
+			return
+		}
+		// 无法读取的目录直接跳过，不影响其他项目
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if !entry.IsDir() || strings.HasPrefix(name, ".") || skipDirs[name] {
+				continue
+			}
+			walk(filepath.Join(dir, name), depth+1)
+		}
+	}
+	walk(root, 0)
+	return projects, nil
+}
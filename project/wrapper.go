@@ -0,0 +1,204 @@
+package project
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Wrapper 项目中的Gradle或Maven wrapper
+type Wrapper struct {
+	// Tool 构建工具，BuildGradle或BuildMaven
+	Tool string
+	// File wrapper的properties文件
+	File string
+	// Version distributionUrl中的构建工具版本
+	Version string
+}
+
+// wrapperFiles 各构建工具wrapper的properties文件相对于项目目录的路径
+var wrapperFiles = []struct {
+	tool string
+	path string
+	re   *regexp.Regexp
+}{
+	{BuildGradle, "gradle/wrapper/gradle-wrapper.properties", regexp.MustCompile(`gradle-(\d+(?:\.\d+)*)`)},
+	{BuildMaven, ".mvn/wrapper/maven-wrapper.properties", regexp.MustCompile(`apache-maven-(\d+(?:\.\d+)*)`)},
+}
+
+// javaSupport 构建工具从某个版本开始支持的Java版本范围
+type javaSupport struct {
+	since string
+	// min 运行该版本需要的最低Java版本
+	min int
+	// max 能运行该版本的最高Java版本，0表示没有已知的上限
+	max int
+}
+
+// gradleSupport Gradle各版本可以运行在哪些Java版本上，按Gradle版本排序
+// 参见 https://docs.gradle.org/current/userguide/compatibility.html
+var gradleSupport = []javaSupport{
+	{"2.0", 6, 8},
+	{"4.3", 7, 9},
+	{"4.7", 7, 10},
+	{"5.0", 8, 11},
+	{"5.4", 8, 12},
+	{"6.0", 8, 13},
+	{"6.3", 8, 14},
+	{"6.7", 8, 15},
+	{"7.0", 8, 16},
+	{"7.3", 8, 17},
+	{"7.5", 8, 18},
+	{"7.6", 8, 19},
+	{"8.3", 8, 20},
+	{"8.5", 8, 21},
+	{"8.8", 8, 22},
+	{"8.10", 8, 23},
+	{"8.14", 8, 24},
+	{"9.0", 17, 24},
+	{"9.1", 17, 25},
+}
+
+// mavenSupport Maven各版本需要的最低Java版本，Maven没有明确的最高版本
+var mavenSupport = []javaSupport{
+	{"3.0", 5, 0},
+	{"3.2", 6, 0},
+	{"3.3", 7, 0},
+	{"3.9", 8, 0},
+	{"4.0", 17, 0},
+}
+
+// FindWrappers 从dir开始逐级向上查找Gradle和Maven wrapper，每种构建工具只返回最近的一个
+func FindWrappers(dir string) ([]Wrapper, error) {
+	var wrappers []Wrapper
+	for _, wf := range wrapperFiles {
+		for d := dir; d != ""; {
+			file := filepath.Join(d, filepath.FromSlash(wf.path))
+			props, err := readProperties(file)
+			if err == nil {
+				w := Wrapper{Tool: wf.tool, File: file}
+				if match := wf.re.FindStringSubmatch(props["distributionUrl"]); match != nil {
+					w.Version = match[1]
+				}
+				wrappers = append(wrappers, w)
+				break
+			}
+			if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("读取 %s 失败: %v", file, err)
+			}
+			parent := filepath.Dir(d)
+			if parent == d {
+				break
+			}
+			d = parent
+		}
+	}
+	return wrappers, nil
+}
+
+// JavaRange 返回该版本的构建工具可以运行在哪些Java版本上，max为0表示没有已知的上限
+// 版本未知或早于兼容表时known为false
+func (w Wrapper) JavaRange() (min, max int, known bool) {
+	table := gradleSupport
+	if w.Tool == BuildMaven {
+		table = mavenSupport
+	}
+	version := parseToolVersion(w.Version)
+	if version == nil {
+		return 0, 0, false
+	}
+	for i := len(table) - 1; i >= 0; i-- {
+		if compareToolVersions(version, parseToolVersion(table[i].since)) >= 0 {
+			return table[i].min, table[i].max, true
+		}
+	}
+	return 0, 0, false
+}
+
+// Supports 判断该wrapper的构建工具能否运行在指定主版本的Java上，版本限制未知时视为支持
+func (w Wrapper) Supports(major int) bool {
+	min, max, known := w.JavaRange()
+	return !known || major >= min && (max == 0 || major <= max)
+}
+
+// Describe 描述构建工具版本及其支持的Java版本
+func (w Wrapper) Describe() string {
+	name := "Gradle"
+	if w.Tool == BuildMaven {
+		name = "Maven"
+	}
+	min, max, known := w.JavaRange()
+	switch {
+	case !known:
+		return fmt.Sprintf("%s %s", name, w.Version)
+	case max == 0:
+		return fmt.Sprintf("%s %s (需要Java %d或更高版本)", name, w.Version, min)
+	}
+	return fmt.Sprintf("%s %s (支持Java %d ~ %d)", name, w.Version, min, max)
+}
+
+// parseToolVersion 将 "7.2"、"8.10.2" 解析为数字数组，无法解析时返回nil
+func parseToolVersion(version string) []int {
+	if version == "" {
+		return nil
+	}
+	var parts []int
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+// compareToolVersions 比较两个版本，缺少的部分视为0
+func compareToolVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// readProperties 读取properties文件中的键值对，值中的 \: 等转义会被还原
+func readProperties(file string) (map[string]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	props := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		value = strings.NewReplacer(`\:`, ":", `\=`, "=", `\\`, `\`).Replace(value)
+		props[strings.TrimSpace(key)] = value
+	}
+	return props, scanner.Err()
+}
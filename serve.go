@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"switch/api"
+	"switch/config"
+)
+
+// serveAPI 启动本机JSON接口，切换和备份使用与命令行相同的逻辑
+func serveAPI(addr string) error {
+	if err := api.CheckLoopback(addr); err != nil {
+		return err
+	}
+	token, err := api.LoadOrCreateToken()
+	if err != nil {
+		return err
+	}
+	server := api.New(token, api.Actions{
+		Switch: apiSwitch,
+		Backup: backupEnvironment,
+	})
+	fmt.Printf("JSON接口已启动: http://%s/api/v1/\n", addr)
+	fmt.Printf("令牌保存在 %s，请求时使用 Authorization: Bearer <令牌>\n", api.TokenPath())
+	fmt.Println("按Ctrl-C停止")
+	return api.ListenAndServe(addr, server)
+}
+
+// apiSwitch 处理接口的切换请求：与 -set 相同地解析版本或配置方案，再通过switchJDK切换
+// 每次都重新读取配置，命令行在服务运行期间所做的修改也会生效
+func apiSwitch(req api.SwitchRequest) (*api.SwitchResult, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	opts := switchOptions{
+		verify:   req.Verify || req.Rollback || cfg.VerifyAfterSwitch || cfg.RollbackOnVerifyFailure,
+		rollback: req.Rollback || cfg.RollbackOnVerifyFailure,
+	}
+
+	version := req.Version
+	if profile := cfg.Profiles[req.Version]; profile != nil && req.Arch == "" {
+		version, opts.profile = profile.JDK, req.Version
+	} else {
+		target, err := resolveVersion(cfg, req.Version, req.Arch)
+		if err != nil {
+			return nil, &api.RequestError{Err: err}
+		}
+		version = target
+	}
+
+	if err := switchJDK(cfg, version, opts); err != nil {
+		return nil, err
+	}
+	return &api.SwitchResult{
+		Version: cfg.CurrentVersion,
+		Profile: cfg.CurrentProfile,
+		Path:    cfg.JDKPaths[cfg.CurrentVersion],
+	}, nil
+}
@@ -0,0 +1,29 @@
+//go:build !linux && !darwin && !freebsd && !openbsd && !netbsd && !dragonfly
+// +build !linux,!darwin,!freebsd,!openbsd,!netbsd,!dragonfly
+
+package shim
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+)
+
+// Exec 启动程序并等待结束，返回其退出码
+// Ctrl-C由该程序自己处理，这里忽略中断信号，等待它退出后返回相同的退出码
+func Exec(bin string, args, env []string) (int, error) {
+	cmd := exec.Command(bin, args...)
+	cmd.Env = env
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	signal.Ignore(os.Interrupt)
+	defer signal.Reset(os.Interrupt)
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	return 0, err
+}
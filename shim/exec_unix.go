@@ -0,0 +1,11 @@
+//go:build linux || darwin || freebsd || openbsd || netbsd || dragonfly
+// +build linux darwin freebsd openbsd netbsd dragonfly
+
+package shim
+
+import "syscall"
+
+// Exec 用程序替换当前进程，成功时不会返回，退出码和信号由该程序直接处理
+func Exec(bin string, args, env []string) (int, error) {
+	return 0, syscall.Exec(bin, append([]string{bin}, args...), env)
+}
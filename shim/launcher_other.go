@@ -0,0 +1,27 @@
+//go:build !windows
+// +build !windows
+
+package shim
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"switch/fsutil"
+)
+
+// launcherName 非Windows平台上shim是与工具同名的shell脚本
+func launcherName(tool string) string {
+	return tool
+}
+
+// writeLauncher 生成通过 -exec 调用本程序的shell脚本，exec保证退出码和信号原样传递
+func writeLauncher(dir, tool, exe string) error {
+	script := fmt.Sprintf("#!/bin/sh\n# 由 jdk-switch -rehash 生成，请勿修改\nexec %s -exec %s \"$@\"\n", shellQuote(exe), shellQuote(tool))
+	return fsutil.WriteFile(filepath.Join(dir, launcherName(tool)), []byte(script), 0755)
+}
+
+// shellQuote 使用单引号包裹字符串
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,50 @@
+//go:build windows
+// +build windows
+
+package shim
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// launcherName Windows上shim是程序本身的副本，以工具名命名，例如java.exe
+func launcherName(tool string) string {
+	return tool + ".exe"
+}
+
+// writeLauncher 优先创建硬链接，不在同一卷上时复制程序
+// 使用.exe而不是.cmd，其他程序通过CreateProcess启动java时也能找到shim
+func writeLauncher(dir, tool, exe string) error {
+	path := filepath.Join(dir, launcherName(tool))
+	tmp := path + ".new"
+	os.Remove(tmp)
+	if err := os.Link(exe, tmp); err != nil {
+		if err := copyFile(exe, tmp); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
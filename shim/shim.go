@@ -0,0 +1,273 @@
+// Package shim 生成java、javac等工具的启动器（shim），运行时按当前目录解析应使用的JDK
+package shim
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"switch/config"
+	"switch/jdk"
+)
+
+// VersionFile 项目中指定JDK版本的文件名
+const VersionFile = ".java-version"
+
+// VersionEnv 指定JDK版本的环境变量，优先级低于 .java-version
+const VersionEnv = "JDK_SWITCH_VERSION"
+
+// Resolution 解析出的JDK
+type Resolution struct {
+	// Version 配置中的JDK版本名称
+	Version string
+	// Profile 版本文件或环境变量中指定的是配置方案时的方案名称
+	Profile string
+	Path    string
+	// Source 版本的来源：.java-version文件路径、JDK_SWITCH_VERSION或current_version
+	Source string
+}
+
+// Resolve 依次从dir及其上级目录中的 .java-version、环境变量 JDK_SWITCH_VERSION、
+// 配置中的 current_version 确定要使用的JDK
+func Resolve(cfg *config.Config, dir string, getenv func(string) string) (*Resolution, error) {
+	query, source, err := FindVersionFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	if query == "" {
+		if query = strings.TrimSpace(getenv(VersionEnv)); query != "" {
+			source = VersionEnv
+		}
+	}
+	if query == "" && cfg.CurrentVersion != "" {
+		query, source = cfg.CurrentVersion, "current_version"
+		if cfg.CurrentProfile != "" {
+			query = cfg.CurrentProfile
+		}
+	}
+	if query == "" {
+		return nil, fmt.Errorf("没有指定JDK版本: 请在 %s 中指定、设置 %s 或使用 -set 切换", VersionFile, VersionEnv)
+	}
+
+	res, err := lookup(cfg, query)
+	if err != nil {
+		return nil, fmt.Errorf("%v（来自 %s）", err, source)
+	}
+	res.Source = source
+	return res, nil
+}
+
+// lookup 将版本查询解析为配置中的JDK：版本名称、配置方案名称，或唯一匹配的完整版本号、主版本号
+func lookup(cfg *config.Config, query string) (*Resolution, error) {
+	if path, exists := cfg.JDKPaths[query]; exists {
+		return &Resolution{Version: query, Path: path}, nil
+	}
+	if profile := cfg.Profiles[query]; profile != nil {
+		return &Resolution{Version: profile.JDK, Profile: query, Path: cfg.JDKPaths[profile.JDK]}, nil
+	}
+
+	matches := jdk.MatchInstallations(jdk.Installations(cfg.JDKPaths), query, "")
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("JDK版本 %s 不存在", query)
+	case 1:
+		return &Resolution{Version: matches[0].Key, Path: matches[0].Path}, nil
+	}
+	var keys []string
+	for _, match := range matches {
+		keys = append(keys, match.Key)
+	}
+	return nil, fmt.Errorf("版本 %s 匹配到多个JDK: %s，请使用完整的版本名称", query, strings.Join(keys, ", "))
+}
+
+// FindVersionFile 从dir开始逐级向上查找 .java-version，返回其中的版本和文件路径
+func FindVersionFile(dir string) (string, string, error) {
+	for dir != "" {
+		file := filepath.Join(dir, VersionFile)
+		version, err := readVersionFile(file)
+		if err == nil && version != "" {
+			return version, file, nil
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return "", "", fmt.Errorf("读取 %s 失败: %v", file, err)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return "", "", nil
+}
+
+// readVersionFile 返回版本文件中第一个非空、非注释行
+func readVersionFile(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line, nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+// Tools 返回所有已配置JDK的bin目录中可执行工具的名称（不含.exe），按名称排序
+func Tools(cfg *config.Config) []string {
+	seen := make(map[string]bool)
+	for _, path := range cfg.JDKPaths {
+		entries, err := os.ReadDir(filepath.Join(path, "bin"))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if name, ok := toolName(filepath.Join(path, "bin"), entry); ok {
+				seen[name] = true
+			}
+		}
+	}
+
+	var tools []string
+	for name := range seen {
+		tools = append(tools, name)
+	}
+	sort.Strings(tools)
+	return tools
+}
+
+// toolName 判断bin目录中的文件是否为可执行工具，返回工具名称
+func toolName(dir string, entry os.DirEntry) (string, bool) {
+	name := entry.Name()
+	if runtime.GOOS == "windows" {
+		if !strings.EqualFold(filepath.Ext(name), ".exe") {
+			return "", false
+		}
+		return strings.TrimSuffix(name, filepath.Ext(name)), true
+	}
+	info, err := os.Stat(filepath.Join(dir, name))
+	if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+		return "", false
+	}
+	return name, true
+}
+
+// Rehash 根据所有已配置JDK中的工具重新生成shim，删除不再需要的shim，返回生成的工具名称
+func Rehash(cfg *config.Config) ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("无法获取程序路径: %v", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	dir := jdk.ShimsDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建shim目录失败: %v", err)
+	}
+
+	tools := Tools(cfg)
+	wanted := make(map[string]bool)
+	for _, tool := range tools {
+		if err := writeLauncher(dir, tool, exe); err != nil {
+			return nil, fmt.Errorf("生成%s的shim失败: %v", tool, err)
+		}
+		wanted[launcherName(tool)] = true
+	}
+
+	// shim目录完全由本工具管理，其余文件都是之前生成、现在已不需要的shim
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取shim目录失败: %v", err)
+	}
+	for _, entry := range entries {
+		if !wanted[entry.Name()] {
+			if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+				return nil, fmt.Errorf("删除旧的shim失败: %v", err)
+			}
+		}
+	}
+	return tools, nil
+}
+
+// Invoked 判断当前程序是否作为shim（shim目录中的程序副本）运行，返回对应的工具名称
+func Invoked() (string, bool) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", false
+	}
+	dir, shimsDir := filepath.Clean(filepath.Dir(exe)), filepath.Clean(jdk.ShimsDir())
+	if dir != shimsDir && !(runtime.GOOS == "windows" && strings.EqualFold(dir, shimsDir)) {
+		return "", false
+	}
+	name := filepath.Base(exe)
+	return strings.TrimSuffix(name, filepath.Ext(name)), true
+}
+
+// Run 解析当前目录应使用的JDK并运行其中的工具，返回工具的退出码
+// 错误信息输出到标准错误，不影响工具本身的标准输出
+func Run(tool string, args []string) int {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jdk-switch: 加载配置失败: %v\n", err)
+		return 1
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jdk-switch: 无法获取当前目录: %v\n", err)
+		return 1
+	}
+	res, err := Resolve(cfg, dir, os.Getenv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jdk-switch: %v\n", err)
+		return 1
+	}
+
+	bin := filepath.Join(res.Path, "bin", jdk.ExecutableName(tool))
+	if _, err := os.Stat(bin); err != nil {
+		fmt.Fprintf(os.Stderr, "jdk-switch: JDK %s 中没有 %s（版本来自 %s）\n", res.Version, tool, res.Source)
+		return 127
+	}
+
+	env, err := Environment(cfg, res)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jdk-switch: %v\n", err)
+		return 1
+	}
+	code, err := Exec(bin, args, env)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jdk-switch: 运行 %s 失败: %v\n", bin, err)
+		return 126
+	}
+	return code
+}
+
+// Environment 返回运行工具时的环境变量：JAVA_HOME指向解析出的JDK，
+// 附加环境变量和PATH条目与切换到该版本（或配置方案）时相同，JDK的bin目录放在PATH最前面
+func Environment(cfg *config.Config, res *Resolution) ([]string, error) {
+	extraEnv, addPath, err := cfg.SwitchEnv(res.Version, res.Profile)
+	if err != nil {
+		return nil, err
+	}
+	overrides := make(map[string]string)
+	for name, value := range extraEnv {
+		overrides[name] = value
+	}
+	overrides["JAVA_HOME"] = res.Path
+
+	entries := append([]string{filepath.Join(res.Path, "bin")}, addPath...)
+	if path := os.Getenv("PATH"); path != "" {
+		entries = append(entries, path)
+	}
+	overrides["PATH"] = strings.Join(entries, string(os.PathListSeparator))
+	return jdk.MergeEnv(os.Environ(), overrides), nil
+}
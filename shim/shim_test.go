@@ -0,0 +1,123 @@
+package shim
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"switch/config"
+	"switch/jdk"
+	"testing"
+)
+
+// makeJDK 创建包含指定工具的JDK目录
+func makeJDK(t *testing.T, dir, version string, tools ...string) {
+	t.Helper()
+	bin := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(bin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, tool := range tools {
+		if err := os.WriteFile(filepath.Join(bin, jdk.ExecutableName(tool)), []byte("fake"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	release := "JAVA_VERSION=\"" + version + "\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "release"), []byte(release), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testConfig(t *testing.T) *config.Config {
+	root := t.TempDir()
+	makeJDK(t, filepath.Join(root, "jdk11"), "11.0.21", "java", "javac")
+	makeJDK(t, filepath.Join(root, "jdk17"), "17.0.9", "java", "javac", "jshell")
+	return &config.Config{
+		JDKPaths: map[string]string{
+			"11":      filepath.Join(root, "jdk11"),
+			"temurin": filepath.Join(root, "jdk17"),
+		},
+		CurrentVersion: "11",
+		Profiles:       map[string]*config.Profile{"work": {JDK: "temurin"}},
+	}
+}
+
+func TestResolve(t *testing.T) {
+	cfg := testConfig(t)
+	project := t.TempDir()
+	sub := filepath.Join(project, "module", "src")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{}
+	getenv := func(name string) string { return env[name] }
+
+	// 没有版本文件和环境变量时使用当前版本
+	res, err := Resolve(cfg, sub, getenv)
+	if err != nil || res.Version != "11" || res.Source != "current_version" {
+		t.Fatalf("期望使用当前版本11，实际: %+v, %v", res, err)
+	}
+
+	// 环境变量优先于当前版本，可以使用主版本号
+	env[VersionEnv] = "17"
+	res, err = Resolve(cfg, sub, getenv)
+	if err != nil || res.Version != "temurin" || res.Source != VersionEnv {
+		t.Fatalf("期望使用环境变量中的17，实际: %+v, %v", res, err)
+	}
+
+	// 上级目录中的 .java-version 优先于环境变量，可以指定配置方案
+	versionFile := filepath.Join(project, VersionFile)
+	if err := os.WriteFile(versionFile, []byte("# 项目JDK\n\nwork\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	res, err = Resolve(cfg, sub, getenv)
+	if err != nil || res.Version != "temurin" || res.Profile != "work" || res.Source != versionFile {
+		t.Fatalf("期望使用版本文件中的配置方案work，实际: %+v, %v", res, err)
+	}
+
+	// 版本不存在时报告来源
+	os.WriteFile(versionFile, []byte("21\n"), 0644)
+	if _, err := Resolve(cfg, sub, getenv); err == nil || !strings.Contains(err.Error(), versionFile) {
+		t.Errorf("期望报告版本文件中的版本不存在，实际: %v", err)
+	}
+
+	cfg.CurrentVersion = ""
+	if _, err := Resolve(cfg, t.TempDir(), func(string) string { return "" }); err == nil {
+		t.Error("没有任何版本来源时应返回错误")
+	}
+}
+
+func TestRehash(t *testing.T) {
+	t.Setenv(config.HomeEnv, t.TempDir())
+	cfg := testConfig(t)
+
+	tools, err := Rehash(cfg)
+	if err != nil {
+		t.Fatalf("生成shim失败: %v", err)
+	}
+	if strings.Join(tools, ",") != "java,javac,jshell" {
+		t.Errorf("期望生成java、javac、jshell，实际: %v", tools)
+	}
+	if runtime.GOOS != "windows" {
+		data, err := os.ReadFile(filepath.Join(jdk.ShimsDir(), "java"))
+		if err != nil || !strings.Contains(string(data), "-exec 'java' \"$@\"") {
+			t.Errorf("shim脚本内容错误: %s, %v", data, err)
+		}
+	}
+
+	// 删除JDK后重新生成，不再需要的shim被删除
+	delete(cfg.JDKPaths, "temurin")
+	if _, err := Rehash(cfg); err != nil {
+		t.Fatalf("重新生成shim失败: %v", err)
+	}
+	entries, _ := os.ReadDir(jdk.ShimsDir())
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	if want := launcherName("java") + "," + launcherName("javac"); strings.Join(names, ",") != want {
+		t.Errorf("期望shim目录中为 %s，实际: %v", want, names)
+	}
+}
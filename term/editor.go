@@ -0,0 +1,288 @@
+// Package term 提供交互模式使用的行编辑器，支持历史记录和Tab补全
+package term
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrInterrupted 用户在输入时按下了Ctrl-C
+var ErrInterrupted = errors.New("输入被中断")
+
+// Editor 行编辑器
+// 输入为终端时进入原始模式，支持光标移动、上下方向键浏览历史和Tab补全；否则按行读取
+type Editor struct {
+	// Complete 返回光标前输入内容的补全候选，每个候选为补全后的完整内容
+	Complete func(line string) []string
+
+	in      *bufio.Reader
+	out     io.Writer
+	file    *os.File
+	history []string
+}
+
+// NewEditor 创建行编辑器
+func NewEditor(in io.Reader, out io.Writer) *Editor {
+	e := &Editor{in: bufio.NewReader(in), out: out}
+	if f, ok := in.(*os.File); ok {
+		e.file = f
+	}
+	return e
+}
+
+// History 返回已输入的历史记录，最早的在前
+func (e *Editor) History() []string {
+	return append([]string{}, e.history...)
+}
+
+// ReadLine 显示提示符并读取一行输入
+// 输入结束（EOF或空行上的Ctrl-D）时返回io.EOF，按下Ctrl-C时返回ErrInterrupted
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	var line string
+	var err error
+
+	restore, rawErr := e.makeRaw()
+	if rawErr == nil {
+		line, err = e.editLine(prompt)
+		restore()
+	} else {
+		line, err = e.readPlain(prompt)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if strings.TrimSpace(line) != "" && (len(e.history) == 0 || e.history[len(e.history)-1] != line) {
+		e.history = append(e.history, line)
+	}
+	return line, nil
+}
+
+// makeRaw 输入为终端时切换到原始模式，返回恢复函数
+func (e *Editor) makeRaw() (func(), error) {
+	if e.file == nil {
+		return nil, errors.New("输入不是终端")
+	}
+	return MakeRaw(e.file)
+}
+
+// MakeRaw 将终端切换到原始模式，返回恢复函数；f不是终端时返回错误
+func MakeRaw(f *os.File) (func(), error) {
+	return makeRaw(f.Fd())
+}
+
+// Size 返回终端的列数和行数
+func Size(f *os.File) (width, height int, err error) {
+	return getSize(f.Fd())
+}
+
+// readPlain 非终端输入时按行读取
+func (e *Editor) readPlain(prompt string) (string, error) {
+	fmt.Fprint(e.out, prompt)
+	line, err := e.in.ReadString('\n')
+	if err != nil {
+		if err == io.EOF && line != "" {
+			fmt.Fprintln(e.out)
+			return strings.TrimRight(line, "\r\n"), nil
+		}
+		fmt.Fprintln(e.out)
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// editLine 原始模式下逐个字符读取并编辑一行
+func (e *Editor) editLine(prompt string) (string, error) {
+	var buf []rune
+	pos := 0
+	historyIndex := len(e.history)
+	// pending 浏览历史记录前正在输入的内容
+	var pending []rune
+
+	e.refresh(prompt, buf, pos)
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			fmt.Fprint(e.out, "\r\n")
+			if err == io.EOF && len(buf) > 0 {
+				return string(buf), nil
+			}
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(buf), nil
+		case 3: // Ctrl-C
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", ErrInterrupted
+		case 4: // Ctrl-D，空行时结束输入，否则删除光标处字符
+			if len(buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			if pos < len(buf) {
+				buf = append(buf[:pos], buf[pos+1:]...)
+			}
+		case 127, 8: // Backspace
+			if pos > 0 {
+				buf = append(buf[:pos-1], buf[pos:]...)
+				pos--
+			}
+		case 1: // Ctrl-A
+			pos = 0
+		case 5: // Ctrl-E
+			pos = len(buf)
+		case 21: // Ctrl-U，删除光标前的内容
+			buf = append([]rune{}, buf[pos:]...)
+			pos = 0
+		case '\t':
+			buf, pos = e.complete(prompt, buf, pos)
+		case 27: // 方向键等转义序列
+			switch e.readEscape() {
+			case 'A': // 上
+				if historyIndex > 0 {
+					if historyIndex == len(e.history) {
+						pending = buf
+					}
+					historyIndex--
+					buf = []rune(e.history[historyIndex])
+					pos = len(buf)
+				}
+			case 'B': // 下
+				if historyIndex < len(e.history) {
+					historyIndex++
+					if historyIndex == len(e.history) {
+						buf = pending
+					} else {
+						buf = []rune(e.history[historyIndex])
+					}
+					pos = len(buf)
+				}
+			case 'C': // 右
+				if pos < len(buf) {
+					pos++
+				}
+			case 'D': // 左
+				if pos > 0 {
+					pos--
+				}
+			case 'H':
+				pos = 0
+			case 'F':
+				pos = len(buf)
+			case '~': // Delete
+				if pos < len(buf) {
+					buf = append(buf[:pos], buf[pos+1:]...)
+				}
+			}
+		default:
+			if r >= 32 {
+				buf = append(buf[:pos], append([]rune{r}, buf[pos:]...)...)
+				pos++
+			}
+		}
+		e.refresh(prompt, buf, pos)
+	}
+}
+
+// readEscape 读取ESC之后的转义序列，返回方向键等的结束字符，Delete键（ESC [ 3 ~）返回'~'
+func (e *Editor) readEscape() rune {
+	r, _, err := e.in.ReadRune()
+	if err != nil || (r != '[' && r != 'O') {
+		return 0
+	}
+	for {
+		r, _, err = e.in.ReadRune()
+		if err != nil {
+			return 0
+		}
+		// 参数部分为数字和分号，遇到其他字符时序列结束
+		if (r < '0' || r > '9') && r != ';' {
+			return r
+		}
+	}
+}
+
+// complete 对光标前的内容进行Tab补全
+// 只有一个候选时直接补全；多个候选时补全公共前缀，无法继续补全时列出所有候选
+func (e *Editor) complete(prompt string, buf []rune, pos int) ([]rune, int) {
+	if e.Complete == nil {
+		return buf, pos
+	}
+	prefix := string(buf[:pos])
+	tail := buf[pos:]
+	candidates := e.Complete(prefix)
+
+	var completed string
+	switch len(candidates) {
+	case 0:
+		fmt.Fprint(e.out, "\a")
+		return buf, pos
+	case 1:
+		completed = candidates[0]
+	default:
+		completed = commonPrefix(candidates)
+		if len(completed) <= len(prefix) {
+			fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+			return buf, pos
+		}
+	}
+
+	newBuf := append([]rune(completed), tail...)
+	return newBuf, len([]rune(completed))
+}
+
+// refresh 重新绘制提示符和输入内容，并将光标移动到pos
+func (e *Editor) refresh(prompt string, buf []rune, pos int) {
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", prompt, string(buf))
+	if back := displayWidth(buf[pos:]); back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+// commonPrefix 返回所有字符串的最长公共前缀，按字符比较，不会截断多字节字符
+func commonPrefix(values []string) string {
+	prefix := []rune(values[0])
+	for _, value := range values[1:] {
+		n := 0
+		for _, r := range value {
+			if n >= len(prefix) || prefix[n] != r {
+				break
+			}
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
+
+// DisplayWidth 计算字符串在终端中占用的列数
+func DisplayWidth(s string) int {
+	return displayWidth([]rune(s))
+}
+
+// displayWidth 计算字符在终端中占用的列数，中日韩等全角字符占两列
+func displayWidth(runes []rune) int {
+	width := 0
+	for _, r := range runes {
+		switch {
+		case r >= 0x1100 && r <= 0x115F,
+			r >= 0x2E80 && r <= 0xA4CF,
+			r >= 0xAC00 && r <= 0xD7A3,
+			r >= 0xF900 && r <= 0xFAFF,
+			r >= 0xFE30 && r <= 0xFE4F,
+			r >= 0xFF00 && r <= 0xFF60,
+			r >= 0xFFE0 && r <= 0xFFE6:
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
@@ -0,0 +1,120 @@
+package term
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// newTestEditor 创建读取固定输入的编辑器，测试中直接调用editLine模拟终端原始模式
+func newTestEditor(input string) *Editor {
+	return NewEditor(strings.NewReader(input), io.Discard)
+}
+
+// 测试原始模式下的编辑操作
+func TestEditLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"普通输入", "use 17\r", "use 17"},
+		{"退格", "ab\x7fc\r", "ac"},
+		{"左移后插入", "ac\x1b[Db\r", "abc"},
+		{"Ctrl-A和Delete", "xabc\x01\x1b[3~\r", "abc"},
+		{"Ctrl-U", "abc\x15d\r", "d"},
+		{"中文输入", "版本\x7f本\r", "版本"},
+	}
+	for _, tt := range tests {
+		e := newTestEditor(tt.input)
+		got, err := e.editLine("> ")
+		if err != nil {
+			t.Errorf("%s: 返回错误 %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: 得到 %q，期望 %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// 测试Ctrl-C和Ctrl-D
+func TestEditLineInterrupt(t *testing.T) {
+	if _, err := newTestEditor("abc\x03").editLine("> "); err != ErrInterrupted {
+		t.Errorf("Ctrl-C应返回ErrInterrupted，实际为 %v", err)
+	}
+	if _, err := newTestEditor("\x04").editLine("> "); err != io.EOF {
+		t.Errorf("空行上的Ctrl-D应返回io.EOF，实际为 %v", err)
+	}
+	if _, err := newTestEditor("").editLine("> "); err != io.EOF {
+		t.Errorf("输入结束应返回io.EOF，实际为 %v", err)
+	}
+}
+
+// 测试上下方向键浏览历史记录
+func TestEditLineHistory(t *testing.T) {
+	e := newTestEditor("\x1b[A\x1b[A\r\x1b[A\x1b[B\r")
+	e.history = []string{"use 8", "use 17"}
+
+	if got, _ := e.editLine("> "); got != "use 8" {
+		t.Errorf("向上两次应得到 use 8，实际为 %q", got)
+	}
+	// 向上再向下回到正在输入的空行
+	if got, _ := e.editLine("> "); got != "" {
+		t.Errorf("向上再向下应回到空行，实际为 %q", got)
+	}
+}
+
+// 测试Tab补全
+func TestEditLineComplete(t *testing.T) {
+	candidates := []string{"use 17", "use 17-zulu", "use 11"}
+	complete := func(line string) []string {
+		var result []string
+		for _, c := range candidates {
+			if strings.HasPrefix(c, line) {
+				result = append(result, c)
+			}
+		}
+		return result
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"use 1\t\r", "use 1"},
+		{"use 17-\t\r", "use 17-zulu"},
+		{"use 1\t7\t\r", "use 17"},
+		{"us\t\r", "use 1"},
+	}
+	for _, tt := range tests {
+		e := newTestEditor(tt.input)
+		e.Complete = complete
+		got, err := e.editLine("> ")
+		if err != nil || got != tt.want {
+			t.Errorf("输入 %q: 得到 %q (%v)，期望 %q", tt.input, got, err, tt.want)
+		}
+	}
+
+	// 候选项在同一个多字节字符的中间字节处才不同，公共前缀不能截断该字符
+	if got := commonPrefix([]string{"use 开发", "use 引擎"}); got != "use " {
+		t.Errorf("公共前缀错误: %q", got)
+	}
+}
+
+// 测试非终端输入按行读取并记录历史
+func TestReadLinePlain(t *testing.T) {
+	e := newTestEditor("list\r\n\nuse 17")
+	for _, want := range []string{"list", "", "use 17"} {
+		got, err := e.ReadLine("> ")
+		if err != nil || got != want {
+			t.Fatalf("得到 %q (%v)，期望 %q", got, err, want)
+		}
+	}
+	if _, err := e.ReadLine("> "); err != io.EOF {
+		t.Errorf("输入结束应返回io.EOF，实际为 %v", err)
+	}
+	if history := e.History(); strings.Join(history, ",") != "list,use 17" {
+		t.Errorf("历史记录不正确: %v", history)
+	}
+}
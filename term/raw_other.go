@@ -0,0 +1,16 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !windows
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!windows
+
+package term
+
+import "errors"
+
+// makeRaw 当前平台不支持原始模式，ReadLine会退回到按行读取
+func makeRaw(fd uintptr) (func(), error) {
+	return nil, errors.New("当前平台不支持终端原始模式")
+}
+
+// getSize 当前平台不支持读取终端窗口大小
+func getSize(fd uintptr) (int, int, error) {
+	return 0, 0, errors.New("当前平台不支持读取终端窗口大小")
+}
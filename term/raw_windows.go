@@ -0,0 +1,46 @@
+//go:build windows
+// +build windows
+
+package term
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// makeRaw 关闭控制台的行输入、回显和Ctrl-C处理，并开启虚拟终端序列，使方向键以转义序列形式输入
+func makeRaw(fd uintptr) (func(), error) {
+	in := windows.Handle(fd)
+	var inMode uint32
+	if err := windows.GetConsoleMode(in, &inMode); err != nil {
+		return nil, err
+	}
+	raw := inMode &^ (windows.ENABLE_ECHO_INPUT | windows.ENABLE_PROCESSED_INPUT | windows.ENABLE_LINE_INPUT)
+	raw |= windows.ENABLE_VIRTUAL_TERMINAL_INPUT
+	if err := windows.SetConsoleMode(in, raw); err != nil {
+		return nil, err
+	}
+
+	// 输出需要支持光标移动等转义序列
+	out := windows.Stdout
+	var outMode uint32
+	outOK := windows.GetConsoleMode(out, &outMode) == nil
+	if outOK {
+		windows.SetConsoleMode(out, outMode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING)
+	}
+
+	return func() {
+		windows.SetConsoleMode(in, inMode)
+		if outOK {
+			windows.SetConsoleMode(out, outMode)
+		}
+	}, nil
+}
+
+// getSize 读取控制台窗口大小
+func getSize(fd uintptr) (int, int, error) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(fd), &info); err != nil {
+		return 0, 0, err
+	}
+	return int(info.Window.Right-info.Window.Left) + 1, int(info.Window.Bottom-info.Window.Top) + 1, nil
+}
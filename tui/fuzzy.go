@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FuzzyScore 判断pattern中的字符是否按顺序出现在text中（不区分大小写）
+// 返回的得分越高表示越匹配：连续匹配和在单词开头匹配会加分
+func FuzzyScore(pattern, text string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(text))
+	if len(p) == 0 {
+		return 0, true
+	}
+
+	score, pi, last := 0, 0, -2
+	for i, r := range t {
+		if pi == len(p) {
+			break
+		}
+		if r != p[pi] {
+			continue
+		}
+		score++
+		if last == i-1 {
+			score += 2
+		}
+		if i == 0 || !unicode.IsLetter(t[i-1]) && !unicode.IsDigit(t[i-1]) {
+			score += 3
+		}
+		last = i
+		pi++
+	}
+	if pi < len(p) {
+		return 0, false
+	}
+	return score, true
+}
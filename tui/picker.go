@@ -0,0 +1,371 @@
+// Package tui 提供全屏的JDK选择界面
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"switch/term"
+	"unicode"
+)
+
+// 默认的屏幕大小，无法读取终端大小时使用
+const (
+	DefaultWidth  = 80
+	DefaultHeight = 24
+)
+
+// Item 列表中的一个JDK
+type Item struct {
+	Key     string
+	Version string
+	Vendor  string
+	Arch    string
+	Path    string
+	// Current 是否为当前使用的JDK
+	Current bool
+}
+
+// ActionKind 用户在界面中选择的操作
+type ActionKind int
+
+const (
+	// ActionQuit 退出界面，不做任何操作
+	ActionQuit ActionKind = iota
+	// ActionSwitch 切换到选中的JDK
+	ActionSwitch
+	// ActionBackup 备份环境变量
+	ActionBackup
+)
+
+// Action 界面退出时返回的操作及选中的JDK
+type Action struct {
+	Kind ActionKind
+	Item Item
+}
+
+// Picker 全屏JDK选择界面
+// 输入字符进行模糊筛选，上下方向键选择，Enter切换，Ctrl-B备份，Esc或Ctrl-C退出
+type Picker struct {
+	Items []Item
+	// Preview 返回选中JDK的预览内容，例如切换后PATH的变化
+	Preview func(Item) []string
+	// Width 和 Height 为屏幕大小
+	Width  int
+	Height int
+	// Status 显示在底部的状态信息
+	Status string
+
+	filter  []rune
+	matches []int
+	cursor  int
+	offset  int
+}
+
+// NewPicker 创建选择界面，初始光标位于当前JDK
+func NewPicker(items []Item) *Picker {
+	p := &Picker{Items: items, Width: DefaultWidth, Height: DefaultHeight}
+	p.refilter()
+	for i, index := range p.matches {
+		if items[index].Current {
+			p.cursor = i
+		}
+	}
+	return p
+}
+
+// Run 在备用屏幕中运行界面，直到用户选择操作或输入结束
+// in应当已处于原始模式，输入结束时返回ActionQuit
+func (p *Picker) Run(in io.Reader, out io.Writer) (Action, error) {
+	reader := bufio.NewReader(in)
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	for {
+		p.draw(out)
+		key, err := readKey(reader)
+		if err != nil {
+			if err == io.EOF {
+				return Action{Kind: ActionQuit}, nil
+			}
+			return Action{Kind: ActionQuit}, err
+		}
+		if action, done := p.HandleKey(key); done {
+			return action, nil
+		}
+	}
+}
+
+// Key 一次按键，Rune为普通字符或控制字符，Code为方向键等特殊键
+type Key struct {
+	Rune rune
+	Code KeyCode
+}
+
+// KeyCode 特殊键
+type KeyCode int
+
+const (
+	KeyNone KeyCode = iota
+	KeyUp
+	KeyDown
+	KeyPageUp
+	KeyPageDown
+	KeyEscape
+)
+
+// HandleKey 处理一次按键，返回true表示界面应当退出并执行返回的操作
+func (p *Picker) HandleKey(key Key) (Action, bool) {
+	switch key.Code {
+	case KeyUp:
+		p.move(-1)
+		return Action{}, false
+	case KeyDown:
+		p.move(1)
+		return Action{}, false
+	case KeyPageUp:
+		p.move(-p.listHeight())
+		return Action{}, false
+	case KeyPageDown:
+		p.move(p.listHeight())
+		return Action{}, false
+	case KeyEscape:
+		return Action{Kind: ActionQuit}, true
+	}
+
+	switch key.Rune {
+	case 3: // Ctrl-C
+		return Action{Kind: ActionQuit}, true
+	case '\r', '\n':
+		if item, ok := p.Selected(); ok {
+			return Action{Kind: ActionSwitch, Item: item}, true
+		}
+	case 2: // Ctrl-B
+		item, _ := p.Selected()
+		return Action{Kind: ActionBackup, Item: item}, true
+	case 16: // Ctrl-P
+		p.move(-1)
+	case 14: // Ctrl-N
+		p.move(1)
+	case 127, 8: // Backspace
+		if len(p.filter) > 0 {
+			p.filter = p.filter[:len(p.filter)-1]
+			p.refilter()
+		}
+	case 21: // Ctrl-U
+		p.filter = nil
+		p.refilter()
+	default:
+		if unicode.IsPrint(key.Rune) {
+			p.filter = append(p.filter, key.Rune)
+			p.refilter()
+		}
+	}
+	return Action{}, false
+}
+
+// Selected 返回光标所在的JDK
+func (p *Picker) Selected() (Item, bool) {
+	if len(p.matches) == 0 {
+		return Item{}, false
+	}
+	return p.Items[p.matches[p.cursor]], true
+}
+
+// Filter 返回当前的筛选内容
+func (p *Picker) Filter() string {
+	return string(p.filter)
+}
+
+// move 移动光标，超出范围时停在两端
+func (p *Picker) move(delta int) {
+	p.cursor += delta
+	if p.cursor >= len(p.matches) {
+		p.cursor = len(p.matches) - 1
+	}
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+}
+
+// refilter 按筛选内容重新计算匹配的JDK，得分高的在前，光标回到第一项
+func (p *Picker) refilter() {
+	type scored struct {
+		index int
+		score int
+	}
+	var results []scored
+	pattern := string(p.filter)
+	for i, item := range p.Items {
+		text := strings.Join([]string{item.Key, item.Version, item.Vendor, item.Arch}, " ")
+		if score, ok := FuzzyScore(pattern, text); ok {
+			results = append(results, scored{i, score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	p.matches = p.matches[:0]
+	for _, r := range results {
+		p.matches = append(p.matches, r.index)
+	}
+	p.cursor = 0
+	p.offset = 0
+}
+
+// listHeight 列表区域的行数，其余空间留给预览
+func (p *Picker) listHeight() int {
+	// 标题、筛选行、两条分隔线和状态行之外的空间，列表最多占一半
+	available := p.Height - 5
+	height := len(p.matches)
+	if height < 1 {
+		height = 1
+	}
+	if height > available/2 {
+		height = available / 2
+	}
+	if height < 1 {
+		height = 1
+	}
+	return height
+}
+
+// Frame 返回当前界面的每一行内容（不含终端控制序列），行数等于屏幕高度
+func (p *Picker) Frame() []string {
+	separator := strings.Repeat("-", p.Width)
+	lines := []string{
+		"JDK Switch  输入筛选  ↑↓选择  Enter切换  Ctrl-B备份  Esc退出",
+		"筛选: " + string(p.filter),
+		separator,
+	}
+
+	// 列表区域，保持光标可见
+	height := p.listHeight()
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+height {
+		p.offset = p.cursor - height + 1
+	}
+	if len(p.matches) == 0 {
+		lines = append(lines, "  没有匹配的JDK")
+	}
+	for i := p.offset; i < len(p.matches) && i < p.offset+height; i++ {
+		item := p.Items[p.matches[i]]
+		marker := "  "
+		if i == p.cursor {
+			marker = "> "
+		}
+		current := " "
+		if item.Current {
+			current = "*"
+		}
+		lines = append(lines, fmt.Sprintf("%s%s %-12s %-14s %-10s %-6s %s", marker, current, item.Key, item.Version, item.Vendor, item.Arch, item.Path))
+	}
+	for len(lines) < 3+height {
+		lines = append(lines, "")
+	}
+	lines = append(lines, separator)
+
+	// 预览区域
+	if item, ok := p.Selected(); ok && p.Preview != nil {
+		for _, line := range p.Preview(item) {
+			if len(lines) >= p.Height-1 {
+				break
+			}
+			lines = append(lines, line)
+		}
+	}
+	for len(lines) < p.Height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, p.Status)
+
+	for i, line := range lines {
+		lines[i] = truncate(line, p.Width)
+	}
+	return lines
+}
+
+// draw 将界面绘制到终端
+func (p *Picker) draw(out io.Writer) {
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, line := range p.Frame() {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString("\x1b[K")
+	}
+	b.WriteString("\x1b[J")
+	io.WriteString(out, b.String())
+}
+
+// truncate 截断超出屏幕宽度的内容
+func truncate(s string, width int) string {
+	if term.DisplayWidth(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		w := term.DisplayWidth(string(r))
+		if used+w > width {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	return b.String()
+}
+
+// readKey 读取一次按键，识别方向键和翻页键的转义序列
+// 单独的ESC（之后没有已缓冲的输入）视为Esc键
+func readKey(reader *bufio.Reader) (Key, error) {
+	r, _, err := reader.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+	if r != 27 {
+		return Key{Rune: r}, nil
+	}
+	if reader.Buffered() == 0 {
+		return Key{Code: KeyEscape}, nil
+	}
+
+	next, _, err := reader.ReadRune()
+	if err != nil {
+		return Key{Code: KeyEscape}, nil
+	}
+	if next != '[' && next != 'O' {
+		reader.UnreadRune()
+		return Key{Code: KeyEscape}, nil
+	}
+
+	var params []rune
+	for {
+		r, _, err := reader.ReadRune()
+		if err != nil {
+			return Key{}, err
+		}
+		if (r >= '0' && r <= '9') || r == ';' {
+			params = append(params, r)
+			continue
+		}
+		switch {
+		case r == 'A':
+			return Key{Code: KeyUp}, nil
+		case r == 'B':
+			return Key{Code: KeyDown}, nil
+		case r == '~' && string(params) == "5":
+			return Key{Code: KeyPageUp}, nil
+		case r == '~' && string(params) == "6":
+			return Key{Code: KeyPageDown}, nil
+		}
+		return Key{}, nil
+	}
+}
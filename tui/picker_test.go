@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// screen 简单的虚拟终端，只解释界面用到的控制序列，用于检查绘制结果
+type screen struct {
+	lines    [][]rune
+	row, col int
+}
+
+func newScreen(height int) *screen {
+	return &screen{lines: make([][]rune, height)}
+}
+
+// write 解释终端输出：光标归位、清除到行尾/屏幕末尾、回车换行，其他控制序列忽略
+func (s *screen) write(output string) {
+	runes := []rune(output)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch r {
+		case '\r':
+			s.col = 0
+		case '\n':
+			s.row++
+		case 27:
+			j := i + 2
+			for j < len(runes) && (runes[j] < '@' || runes[j] > '~') {
+				j++
+			}
+			if j >= len(runes) {
+				return
+			}
+			switch runes[j] {
+			case 'H':
+				s.row, s.col = 0, 0
+			case 'K':
+				if s.row < len(s.lines) && s.col < len(s.lines[s.row]) {
+					s.lines[s.row] = s.lines[s.row][:s.col]
+				}
+			case 'J':
+				for k := s.row + 1; k < len(s.lines); k++ {
+					s.lines[k] = nil
+				}
+			}
+			i = j
+		default:
+			if s.row >= len(s.lines) {
+				continue
+			}
+			line := s.lines[s.row]
+			for len(line) < s.col {
+				line = append(line, ' ')
+			}
+			if s.col < len(line) {
+				line[s.col] = r
+			} else {
+				line = append(line, r)
+			}
+			s.lines[s.row] = line
+			s.col++
+		}
+	}
+}
+
+func (s *screen) text() string {
+	var lines []string
+	for _, line := range s.lines {
+		lines = append(lines, string(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func testItems() []Item {
+	return []Item{
+		{Key: "8", Version: "1.8.0_392", Vendor: "temurin", Arch: "x64", Path: "/jdk/8"},
+		{Key: "11", Version: "11.0.21", Vendor: "temurin", Arch: "x64", Path: "/jdk/11", Current: true},
+		{Key: "17", Version: "17.0.9", Vendor: "temurin", Arch: "x64", Path: "/jdk/17"},
+		{Key: "17-zulu", Version: "17.0.9", Vendor: "zulu", Arch: "arm64", Path: "/jdk/17-zulu"},
+	}
+}
+
+// 测试模糊匹配
+func TestFuzzyScore(t *testing.T) {
+	if _, ok := FuzzyScore("zl", "17-zulu 17.0.9 zulu"); !ok {
+		t.Errorf("zl 应该匹配 17-zulu")
+	}
+	if _, ok := FuzzyScore("21", "17 17.0.9 temurin"); ok {
+		t.Errorf("21 不应该匹配 17")
+	}
+	prefix, _ := FuzzyScore("17", "17 17.0.9 temurin")
+	scattered, _ := FuzzyScore("17", "11 11.0.21 temurin x64 1x7")
+	if prefix <= scattered {
+		t.Errorf("连续匹配得分(%d)应高于分散匹配(%d)", prefix, scattered)
+	}
+}
+
+// 测试按键脚本对应的操作
+func TestPickerRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		action ActionKind
+		key    string
+	}{
+		{"初始光标在当前版本", "\r", ActionSwitch, "11"},
+		{"方向键选择", "\x1b[B\r", ActionSwitch, "17"},
+		{"Ctrl-N和Ctrl-P", "\x0e\x0e\x10\r", ActionSwitch, "17"},
+		{"模糊筛选", "zu\r", ActionSwitch, "17-zulu"},
+		{"退格修改筛选", "zu\x7f\x7f8\r", ActionSwitch, "8"},
+		{"备份", "\x02", ActionBackup, "11"},
+		{"Esc退出", "\x1b", ActionQuit, ""},
+		{"Ctrl-C退出", "\x03", ActionQuit, ""},
+		{"输入结束", "", ActionQuit, ""},
+		{"无匹配时Enter不退出", "xyz\r", ActionQuit, ""},
+	}
+	for _, tt := range tests {
+		p := NewPicker(testItems())
+		action, err := p.Run(strings.NewReader(tt.input), &bytes.Buffer{})
+		if err != nil {
+			t.Errorf("%s: 返回错误 %v", tt.name, err)
+			continue
+		}
+		if action.Kind != tt.action || action.Item.Key != tt.key {
+			t.Errorf("%s: 得到 %v %q，期望 %v %q", tt.name, action.Kind, action.Item.Key, tt.action, tt.key)
+		}
+	}
+}
+
+// 测试绘制的界面内容，包括预览区域
+func TestPickerScreen(t *testing.T) {
+	p := NewPicker(testItems())
+	p.Width, p.Height = 60, 16
+	p.Status = "环境变量已备份"
+	p.Preview = func(item Item) []string {
+		return []string{"PATH:", "- /jdk/11/bin", "+ " + item.Path + "/bin"}
+	}
+
+	var out bytes.Buffer
+	if _, err := p.Run(strings.NewReader("zu"), &out); err != nil {
+		t.Fatalf("运行失败: %v", err)
+	}
+
+	// 回放全部输出，备用屏幕的切换序列被忽略，屏幕上保留最后一帧
+	s := newScreen(p.Height)
+	s.write(out.String())
+	text := s.text()
+
+	for _, want := range []string{"筛选: zu", "> ", "17-zulu", "+ /jdk/17-zulu/bin", "环境变量已备份"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("界面中缺少 %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "/jdk/8") {
+		t.Errorf("筛选后不应显示JDK 8:\n%s", text)
+	}
+	for i, line := range s.lines {
+		if len(line) > p.Width {
+			t.Errorf("第%d行超出屏幕宽度: %q", i, string(line))
+		}
+	}
+	if lines := strings.Split(text, "\n"); len(lines) != p.Height || !strings.Contains(lines[p.Height-1], "环境变量已备份") {
+		t.Errorf("状态信息应位于最后一行:\n%s", text)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"switch/config"
+	"switch/history"
+	"switch/jdk"
+	"time"
+)
+
+// recordHistory 写入一条历史记录，写入失败时只提示，不影响操作结果
+func recordHistory(entry history.Entry, start time.Time, err error) {
+	entry.Time = start
+	entry.Duration = time.Since(start).Milliseconds()
+	if entry.Scope == "" {
+		entry.Scope = history.ScopeSystem
+	}
+	entry.Outcome = history.OutcomeSuccess
+	if err != nil {
+		entry.Outcome = history.OutcomeFailed
+		entry.Error = err.Error()
+	}
+	if appendErr := history.Append(entry); appendErr != nil {
+		fmt.Printf("警告: %v\n", appendErr)
+	}
+}
+
+// backupEnvironment 备份环境变量并记录历史，返回备份标识
+func backupEnvironment() (id string, err error) {
+	record := history.Entry{Action: history.ActionBackup}
+	start := time.Now()
+	defer func() { recordHistory(record, start, err) }()
+
+	record.BackupID, err = jdk.BackupEnvironmentVariables(backupExtraNames()...)
+	return record.BackupID, err
+}
+
+// showHistory 显示最近的历史记录，version非空时只显示与该版本相关的记录
+func showHistory(version string, limit int) error {
+	entries, err := history.Read()
+	if err != nil {
+		return err
+	}
+	entries = history.Query(entries, history.Filter{Version: version, Limit: limit})
+	if len(entries) == 0 {
+		fmt.Println("没有历史记录")
+		return nil
+	}
+	for _, entry := range entries {
+		fmt.Println(entry.Describe())
+	}
+	fmt.Printf("\n历史记录文件: %s\n", history.Path())
+	return nil
+}
+
+// undoLastSwitch 使用最近一次切换前的备份恢复环境变量，并将当前版本改回切换前的版本
+func undoLastSwitch() (err error) {
+	entries, err := history.Read()
+	if err != nil {
+		return err
+	}
+	target, err := history.LastUndoable(entries)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("撤销 %s 的切换 %s -> %s，恢复备份 %s\n", target.Time.Format("2006-01-02 15:04:05"), target.From, target.To, target.BackupID)
+
+	// 与切换使用同一把锁，避免与其他进程同时进行的切换交错修改环境变量
+	unlock, err := config.LockSwitch()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record := history.Entry{
+		Action:      history.ActionUndo,
+		From:        target.To,
+		To:          target.From,
+		FromProfile: target.Profile,
+		Profile:     target.FromProfile,
+		Undoes:      target.ID,
+	}
+	start := time.Now()
+	defer func() { recordHistory(record, start, err) }()
+
+	record.BackupID, err = jdk.RestoreBackup(target.BackupID, backupExtraNames()...)
+	if err != nil {
+		return fmt.Errorf("恢复备份失败: %v", err)
+	}
+
+	// 环境变量已恢复为切换前的状态，同步更新配置中的当前版本和附加环境变量记录
+	_, err = config.Update(func(c *config.Config) error {
+		if _, exists := c.JDKPaths[target.From]; !exists {
+			fmt.Printf("警告: JDK版本 %s 已不在配置中，当前版本保持为 %s\n", target.From, c.CurrentVersion)
+			return nil
+		}
+		profile := target.FromProfile
+		if _, exists := c.Profiles[profile]; profile != "" && !exists {
+			fmt.Printf("警告: 配置方案 %s 已不在配置中\n", profile)
+			profile = ""
+		}
+		extraEnv, addPath, err := c.SwitchEnv(target.From, profile)
+		if err != nil {
+			return err
+		}
+		c.ManagedEnv = config.ManagedEnvNames(extraEnv)
+		c.ManagedPath = addPath
+		c.CurrentProfile = profile
+		c.CurrentVersion = target.From
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("更新配置失败: %v", err)
+	}
+	return nil
+}
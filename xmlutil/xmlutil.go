@@ -0,0 +1,100 @@
+package xmlutil
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// Span 元素在原内容中的字节范围，End为结束标签之后的位置
+type Span struct {
+	Start, End int
+}
+
+// Escape 转义XML文本和属性值中的特殊字符
+func Escape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// RemoveChildren 用XML解析器找到第一个满足isParent的元素，将其中名为name的直接子元素解析为T，
+// 返回remove为true的子元素位置以及父元素结束标签的位置（没有找到父元素时为-1）
+// 注释和CDATA中的标签不会被当作元素，带属性的开始标签也能正确识别
+func RemoveChildren[T any](data []byte, isParent func(xml.StartElement) bool, name string, remove func(T) bool) ([]Span, int, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var spans []Span
+	depth, parentDepth := 0, -1
+	for {
+		offset := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return spans, -1, nil
+		}
+		if err != nil {
+			return nil, -1, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if parentDepth < 0 && isParent(t) {
+				parentDepth = depth
+			} else if parentDepth >= 0 && depth == parentDepth+1 && t.Name.Local == name {
+				var child T
+				if err := dec.DecodeElement(&child, &t); err != nil {
+					return nil, -1, err
+				}
+				if remove(child) {
+					spans = append(spans, Span{offset, int(dec.InputOffset())})
+				}
+				continue
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+			if depth == parentDepth {
+				return spans, offset, nil
+			}
+		}
+	}
+}
+
+// Closing 返回第一个满足match的元素结束标签的位置，没有找到时返回-1
+func Closing(data []byte, match func(xml.StartElement) bool) (int, error) {
+	_, closing, err := RemoveChildren(data, match, "", func(struct{}) bool { return false })
+	return closing, err
+}
+
+// Splice 删除spans（连同所在行的缩进和换行），并在at所在行之前插入text，其他内容保持不变
+// at所在行在at之前还有其他内容时直接插入到at处。spans须按位置排列且都在at之前
+func Splice(data []byte, spans []Span, at int, text string) []byte {
+	content := string(data)
+	var out strings.Builder
+	pos := 0
+	for _, span := range spans {
+		start, end := span.Start, span.End
+		lineStart := strings.LastIndex(content[:start], "\n") + 1
+		if lineStart >= pos && strings.TrimSpace(content[lineStart:start]) == "" {
+			start = lineStart
+		}
+		if strings.HasPrefix(content[end:], "\r\n") {
+			end += 2
+		} else if strings.HasPrefix(content[end:], "\n") {
+			end++
+		}
+		out.WriteString(content[pos:start])
+		pos = end
+	}
+
+	if at < pos {
+		at = pos
+	}
+	lineStart := strings.LastIndex(content[:at], "\n") + 1
+	if lineStart >= pos && strings.TrimSpace(content[lineStart:at]) == "" {
+		at = lineStart
+	}
+	out.WriteString(content[pos:at])
+	out.WriteString(text)
+	out.WriteString(content[at:])
+	return []byte(out.String())
+}
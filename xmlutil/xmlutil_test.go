@@ -0,0 +1,45 @@
+package xmlutil
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+type item struct {
+	ID string `xml:"id"`
+}
+
+// 测试只删除父元素下真正的子元素，注释和其他元素中的同名标签保持不变
+func TestRemoveChildren(t *testing.T) {
+	data := []byte(`<root>
+  <!-- <item><id>a</id></item> -->
+  <other><item><id>a</id></item></other>
+  <item kind="x"><id>a</id></item>
+  <item><id>b</id></item>
+</root>
+`)
+	isRoot := func(e xml.StartElement) bool { return e.Name.Local == "root" }
+	spans, closing, err := RemoveChildren(data, isRoot, "item", func(it item) bool { return it.ID == "a" })
+	if err != nil {
+		t.Fatalf("RemoveChildren 错误: %v", err)
+	}
+	if len(spans) != 1 {
+		t.Fatalf("期望删除1个元素, 得到 %v", spans)
+	}
+
+	out := string(Splice(data, spans, closing, "  <item><id>c</id></item>\n"))
+	expected := `<root>
+  <!-- <item><id>a</id></item> -->
+  <other><item><id>a</id></item></other>
+  <item><id>b</id></item>
+  <item><id>c</id></item>
+</root>
+`
+	if out != expected {
+		t.Errorf("期望:\n%s\n得到:\n%s", expected, out)
+	}
+
+	if closing, err := Closing(data, func(e xml.StartElement) bool { return e.Name.Local == "missing" }); err != nil || closing != -1 {
+		t.Errorf("找不到元素时应返回-1, 得到 %d, %v", closing, err)
+	}
+}